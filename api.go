@@ -0,0 +1,375 @@
+package conoha
+
+import (
+	"context"
+	"io"
+)
+
+// The service interfaces below list the methods of *Client by API, so that
+// code can depend on only the APIs it uses and tests can substitute a fake
+// for the client, such as the ones in package conohafake:
+//
+//	func launch(ctx context.Context, compute conoha.ComputeAPI) error
+//
+// Each method behaves as documented on *Client.
+
+// ComputeAPI is the Compute API of *Client: servers, flavors, keypairs,
+// attachments and monitoring.
+type ComputeAPI interface {
+	// Server CRUD
+	ListServers(ctx context.Context, opts *ListServersOptions) ([]Server, error)
+	ListServersDetail(ctx context.Context, opts *ListServersOptions) ([]ServerDetail, error)
+	GetServer(ctx context.Context, serverID string) (*ServerDetail, error)
+	CreateServer(ctx context.Context, opts CreateServerRequest) (*CreateServerResponse, error)
+	DeleteServer(ctx context.Context, serverID string) error
+
+	// Server Actions
+	StartServer(ctx context.Context, serverID string) error
+	StopServer(ctx context.Context, serverID string) error
+	RebootServer(ctx context.Context, serverID string) error
+	ForceStopServer(ctx context.Context, serverID string) error
+	RebuildServer(ctx context.Context, serverID string, opts RebuildServerRequest) error
+	ResizeServer(ctx context.Context, serverID, flavorRef string) error
+	ConfirmResize(ctx context.Context, serverID string) error
+	RevertResize(ctx context.Context, serverID string) error
+	SetVideoDevice(ctx context.Context, serverID, model string) error
+	SetNetworkAdapter(ctx context.Context, serverID, model string) error
+	SetStorageController(ctx context.Context, serverID, bus string) error
+	MountISO(ctx context.Context, serverID, imageRef string) (string, error)
+	UnmountISO(ctx context.Context, serverID string) error
+
+	// Server Network Info
+	GetServerAddresses(ctx context.Context, serverID string) (map[string][]Address, error)
+	GetServerAddressesByNetwork(ctx context.Context, serverID, networkName string) ([]Address, error)
+
+	// Server Security Groups
+	GetServerSecurityGroups(ctx context.Context, serverID string) ([]ServerSecurityGroup, error)
+
+	// Server Console
+	GetConsoleURL(ctx context.Context, serverID string, opts RemoteConsoleRequest) (*RemoteConsole, error)
+	GetVNCConsoleURL(ctx context.Context, serverID string) (string, error)
+
+	// Server Metadata
+	GetServerMetadata(ctx context.Context, serverID string) (map[string]string, error)
+	UpdateServerMetadata(ctx context.Context, serverID string, metadata map[string]string) (map[string]string, error)
+
+	// Flavors
+	ListFlavors(ctx context.Context) ([]Flavor, error)
+	ListFlavorsDetail(ctx context.Context) ([]FlavorDetail, error)
+	GetFlavor(ctx context.Context, flavorID string) (*FlavorDetail, error)
+
+	// SSH Keypairs
+	ListKeypairs(ctx context.Context, opts *ListKeypairsOptions) ([]Keypair, error)
+	CreateKeypair(ctx context.Context, name string) (*Keypair, error)
+	ImportKeypair(ctx context.Context, name, publicKey string) (*Keypair, error)
+	GetKeypair(ctx context.Context, name string) (*Keypair, error)
+	DeleteKeypair(ctx context.Context, name string) error
+
+	// Port Attachments (Server Interfaces)
+	ListServerInterfaces(ctx context.Context, serverID string) ([]InterfaceAttachment, error)
+	GetServerInterface(ctx context.Context, serverID, portID string) (*InterfaceAttachment, error)
+	AttachPort(ctx context.Context, serverID, portID string) (*InterfaceAttachment, error)
+	DetachPort(ctx context.Context, serverID, portID string) error
+
+	// Volume Attachments (Server Volumes)
+	ListServerVolumes(ctx context.Context, serverID string) ([]ServerVolumeAttachment, error)
+	GetServerVolume(ctx context.Context, serverID, volumeID string) (*ServerVolumeAttachment, error)
+	AttachVolume(ctx context.Context, serverID, volumeID string) (*ServerVolumeAttachment, error)
+	DetachVolume(ctx context.Context, serverID, volumeID string) error
+
+	// Monitoring (RRD Graphs)
+	GetCPUUsage(ctx context.Context, serverID string, opts *MonitoringOptions) (*RRDData, error)
+	GetDiskIO(ctx context.Context, serverID string, opts *DiskMonitoringOptions) (*RRDData, error)
+	GetNetworkTraffic(ctx context.Context, serverID string, opts NetworkMonitoringOptions) (*RRDData, error)
+
+	// Pagination
+	EachServer(ctx context.Context, opts *ListServersOptions, fn func(Server) error) error
+	ListAllServers(ctx context.Context, opts *ListServersOptions) ([]Server, error)
+	EachServerDetail(ctx context.Context, opts *ListServersOptions, fn func(ServerDetail) error) error
+	ListAllServersDetail(ctx context.Context, opts *ListServersOptions) ([]ServerDetail, error)
+	EachKeypair(ctx context.Context, opts *ListKeypairsOptions, fn func(Keypair) error) error
+	ListAllKeypairs(ctx context.Context, opts *ListKeypairsOptions) ([]Keypair, error)
+
+	// Name Resolution
+	ResolveServer(ctx context.Context, nameOrID string) (*ServerDetail, error)
+	ResolveFlavor(ctx context.Context, nameOrID string) (*FlavorDetail, error)
+
+	// Provisioning
+	LaunchServer(ctx context.Context, spec ServerSpec) (*ServerDetail, error)
+
+	// Waiters
+	WaitForServerStatus(ctx context.Context, serverID, status string, opts *WaitOptions) (*ServerDetail, error)
+	WaitForServerDeleted(ctx context.Context, serverID string, opts *WaitOptions) error
+}
+
+// BlockStorageAPI is the Block Storage API of *Client: volumes, volume
+// types and backups.
+type BlockStorageAPI interface {
+	// Volume Types
+	ListVolumes(ctx context.Context, opts *ListVolumesOptions) ([]Volume, error)
+	ListVolumesDetail(ctx context.Context, opts *ListVolumesOptions) ([]Volume, error)
+	GetVolume(ctx context.Context, volumeID string) (*Volume, error)
+	CreateVolume(ctx context.Context, opts CreateVolumeRequest) (*Volume, error)
+	DeleteVolume(ctx context.Context, volumeID string, force bool) error
+	UpdateVolume(ctx context.Context, volumeID, name string, description *string) (*Volume, error)
+	SaveVolumeAsImage(ctx context.Context, volumeID, imageName string) (*VolumeImageSaveResponse, error)
+	ListVolumeTypes(ctx context.Context) ([]VolumeType, error)
+	GetVolumeType(ctx context.Context, volumeTypeID string) (*VolumeType, error)
+
+	// Backups
+	ListBackups(ctx context.Context, opts *ListBackupsOptions) ([]Backup, error)
+	ListBackupsDetail(ctx context.Context, opts *ListBackupsOptions) ([]Backup, error)
+	GetBackup(ctx context.Context, backupID string) (*Backup, error)
+	EnableAutoBackup(ctx context.Context, serverID string, opts *EnableAutoBackupOptions) (*Backup, error)
+	UpdateBackupRetention(ctx context.Context, serverID string, retention int) (*Backup, error)
+	DisableAutoBackup(ctx context.Context, serverID string) error
+	RestoreBackup(ctx context.Context, backupID, volumeID string) (*BackupRestoreResponse, error)
+
+	// Pagination
+	EachVolume(ctx context.Context, opts *ListVolumesOptions, fn func(Volume) error) error
+	ListAllVolumes(ctx context.Context, opts *ListVolumesOptions) ([]Volume, error)
+	EachBackup(ctx context.Context, opts *ListBackupsOptions, fn func(Backup) error) error
+	ListAllBackups(ctx context.Context, opts *ListBackupsOptions) ([]Backup, error)
+
+	// Name Resolution
+	ResolveVolume(ctx context.Context, nameOrID string) (*Volume, error)
+
+	// Waiters
+	WaitForVolumeStatus(ctx context.Context, volumeID, status string, opts *WaitOptions) (*Volume, error)
+	WaitForVolumeDeleted(ctx context.Context, volumeID string, opts *WaitOptions) error
+	WaitForBackup(ctx context.Context, backupID string, opts *WaitOptions) (*Backup, error)
+	WaitForBackupDeleted(ctx context.Context, backupID string, opts *WaitOptions) error
+}
+
+// ImageAPI is the Image Service API of *Client.
+type ImageAPI interface {
+	// Image Types
+	ListImages(ctx context.Context, opts *ListImagesOptions) ([]Image, error)
+	GetImage(ctx context.Context, imageID string) (*Image, error)
+	DeleteImage(ctx context.Context, imageID string) error
+	GetImageQuota(ctx context.Context) (*ImageQuota, error)
+	GetImageUsage(ctx context.Context) (*ImageUsage, error)
+	SetImageQuota(ctx context.Context, imageSize string) (*ImageQuota, error)
+	CreateISOImage(ctx context.Context, name string) (*Image, error)
+	UploadISOImage(ctx context.Context, imageID string, data io.Reader) error
+
+	// Pagination
+	EachImage(ctx context.Context, opts *ListImagesOptions, fn func(Image) error) error
+	ListAllImages(ctx context.Context, opts *ListImagesOptions) ([]Image, error)
+
+	// Name Resolution
+	ResolveImage(ctx context.Context, nameOrID string) (*Image, error)
+
+	// Waiters
+	WaitForImageActive(ctx context.Context, imageID string, opts *WaitOptions) (*Image, error)
+	WaitForImageDeleted(ctx context.Context, imageID string, opts *WaitOptions) error
+}
+
+// NetworkAPI is the Networking API of *Client: networks, subnets, ports,
+// security groups and QoS policies.
+type NetworkAPI interface {
+	// QoS Policies
+	ListQoSPolicies(ctx context.Context, opts *ListQoSPoliciesOptions) ([]QoSPolicy, error)
+	GetQoSPolicy(ctx context.Context, policyID string) (*QoSPolicy, error)
+
+	// Subnets
+	ListSubnets(ctx context.Context, opts *ListSubnetsOptions) ([]Subnet, error)
+	GetSubnet(ctx context.Context, subnetID string) (*Subnet, error)
+	CreateSubnet(ctx context.Context, networkID, cidr string) (*Subnet, error)
+	DeleteSubnet(ctx context.Context, subnetID string) error
+
+	// Security Groups
+	ListSecurityGroups(ctx context.Context, opts *ListSecurityGroupsOptions) ([]SecurityGroup, error)
+	GetSecurityGroup(ctx context.Context, sgID string) (*SecurityGroup, error)
+	CreateSecurityGroup(ctx context.Context, name, description string) (*SecurityGroup, error)
+	UpdateSecurityGroup(ctx context.Context, sgID, name, description string) (*SecurityGroup, error)
+	DeleteSecurityGroup(ctx context.Context, sgID string) error
+	ListSecurityGroupRules(ctx context.Context, opts *ListSecurityGroupRulesOptions) ([]SecurityGroupRule, error)
+	GetSecurityGroupRule(ctx context.Context, ruleID string) (*SecurityGroupRule, error)
+	CreateSecurityGroupRule(ctx context.Context, opts CreateSecurityGroupRuleRequest) (*SecurityGroupRule, error)
+	DeleteSecurityGroupRule(ctx context.Context, ruleID string) error
+
+	// Networks
+	ListNetworks(ctx context.Context, opts *ListNetworksOptions) ([]Network, error)
+	GetNetwork(ctx context.Context, networkID string) (*Network, error)
+	CreateNetwork(ctx context.Context) (*Network, error)
+	DeleteNetwork(ctx context.Context, networkID string) error
+
+	// Ports
+	ListPorts(ctx context.Context, opts *ListPortsOptions) ([]Port, error)
+	GetPort(ctx context.Context, portID string) (*Port, error)
+	CreatePort(ctx context.Context, opts CreatePortRequest) (*Port, error)
+	AllocateAdditionalIP(ctx context.Context, count int, securityGroups []string) (*Port, error)
+	UpdatePort(ctx context.Context, portID string, opts UpdatePortRequest) (*Port, error)
+	DeletePort(ctx context.Context, portID string) error
+
+	// Pagination
+	EachPort(ctx context.Context, opts *ListPortsOptions, fn func(Port) error) error
+	ListAllPorts(ctx context.Context, opts *ListPortsOptions) ([]Port, error)
+	EachSecurityGroup(ctx context.Context, opts *ListSecurityGroupsOptions, fn func(SecurityGroup) error) error
+	ListAllSecurityGroups(ctx context.Context, opts *ListSecurityGroupsOptions) ([]SecurityGroup, error)
+	EachNetwork(ctx context.Context, opts *ListNetworksOptions, fn func(Network) error) error
+	ListAllNetworks(ctx context.Context, opts *ListNetworksOptions) ([]Network, error)
+
+	// Name Resolution
+	ResolveSecurityGroup(ctx context.Context, nameOrID string) (*SecurityGroup, error)
+	ResolveNetwork(ctx context.Context, nameOrID string) (*Network, error)
+}
+
+// LoadBalancerAPI is the Load Balancer API of *Client.
+type LoadBalancerAPI interface {
+	// Load Balancers
+	ListLoadBalancers(ctx context.Context) ([]LoadBalancer, error)
+	GetLoadBalancer(ctx context.Context, lbID string) (*LoadBalancer, error)
+	CreateLoadBalancer(ctx context.Context, name string) (*LoadBalancer, error)
+	UpdateLoadBalancer(ctx context.Context, lbID, name string) (*LoadBalancer, error)
+	DeleteLoadBalancer(ctx context.Context, lbID string) error
+
+	// Listeners
+	ListListeners(ctx context.Context) ([]Listener, error)
+	GetListener(ctx context.Context, listenerID string) (*Listener, error)
+	CreateListener(ctx context.Context, name, protocol string, port int, lbID string) (*Listener, error)
+	UpdateListener(ctx context.Context, listenerID, name string) (*Listener, error)
+	DeleteListener(ctx context.Context, listenerID string) error
+
+	// Pools
+	ListPools(ctx context.Context) ([]Pool, error)
+	GetPool(ctx context.Context, poolID string) (*Pool, error)
+	CreatePool(ctx context.Context, name, protocol, lbAlgorithm, listenerID string) (*Pool, error)
+	UpdatePool(ctx context.Context, poolID string, name, lbAlgorithm string) (*Pool, error)
+	DeletePool(ctx context.Context, poolID string) error
+
+	// Members
+	ListMembers(ctx context.Context, poolID string) ([]Member, error)
+	GetMember(ctx context.Context, poolID, memberID string) (*Member, error)
+	AddMember(ctx context.Context, poolID, name, address string, port int) (*Member, error)
+	UpdateMember(ctx context.Context, poolID, memberID string, adminStateUp bool) (*Member, error)
+	DeleteMember(ctx context.Context, poolID, memberID string) error
+
+	// Health Monitors
+	ListHealthMonitors(ctx context.Context) ([]HealthMonitor, error)
+	GetHealthMonitor(ctx context.Context, hmID string) (*HealthMonitor, error)
+	CreateHealthMonitor(ctx context.Context, opts CreateHealthMonitorRequest) (*HealthMonitor, error)
+	UpdateHealthMonitor(ctx context.Context, hmID, name string) (*HealthMonitor, error)
+	DeleteHealthMonitor(ctx context.Context, hmID string) error
+
+	// Waiters
+	WaitForLoadBalancerActive(ctx context.Context, lbID string, opts *WaitOptions) (*LoadBalancer, error)
+	WaitForLoadBalancerDeleted(ctx context.Context, lbID string, opts *WaitOptions) error
+}
+
+// ObjectStorageAPI is the Object Storage API of *Client.
+type ObjectStorageAPI interface {
+	// Account Operations
+	GetAccountInfo(ctx context.Context) (*AccountInfo, error)
+	SetAccountQuota(ctx context.Context, gigaBytes string) error
+
+	// Container Operations
+	ListContainers(ctx context.Context) ([]Container, error)
+	CreateContainer(ctx context.Context, name string) error
+	DeleteContainer(ctx context.Context, name string) error
+	GetContainerInfo(ctx context.Context, name string) (*ContainerInfo, error)
+
+	// Object Operations
+	ListObjects(ctx context.Context, container string, opts *ListObjectsOptions) ([]Object, error)
+	UploadObject(ctx context.Context, container, objectName string, data io.Reader) error
+	DownloadObject(ctx context.Context, container, objectName string) (io.ReadCloser, error)
+	DeleteObject(ctx context.Context, container, objectName string) error
+	GetObjectInfo(ctx context.Context, container, objectName string) (*ObjectInfo, error)
+	CopyObject(ctx context.Context, srcContainer, srcObject, dstContainer, dstObject string) error
+	ScheduleObjectDeletion(ctx context.Context, container, objectName string, deleteAt int64) error
+	ScheduleObjectDeletionAfter(ctx context.Context, container, objectName string, deleteAfterSeconds int64) error
+
+	// Container Configuration
+	EnableVersioning(ctx context.Context, container, versionsContainer string) error
+	DisableVersioning(ctx context.Context, container string) error
+	EnableWebPublishing(ctx context.Context, container string) error
+	DisableWebPublishing(ctx context.Context, container string) error
+	SetTempURLKey(ctx context.Context, key string) error
+	RemoveTempURLKey(ctx context.Context) error
+	GenerateTempURL(method, container, objectName, key string, expires int64) (string, error)
+
+	// Large Object Upload
+	CreateDLOManifest(ctx context.Context, container, manifestName, segmentContainer, segmentPrefix string) error
+	CreateSLOManifest(ctx context.Context, container, manifestName string, segments []SLOSegment) error
+
+	// Pagination
+	EachObject(ctx context.Context, container string, opts *ListObjectsOptions, fn func(Object) error) error
+	ListAllObjects(ctx context.Context, container string, opts *ListObjectsOptions) ([]Object, error)
+}
+
+// DNSAPI is the DNS Service API of *Client.
+type DNSAPI interface {
+	// Domain Operations
+	ListDomains(ctx context.Context, opts *ListDomainsOptions) ([]Domain, error)
+	GetDomain(ctx context.Context, domainID string) (*Domain, error)
+	CreateDomain(ctx context.Context, opts CreateDomainRequest) (*Domain, error)
+	UpdateDomain(ctx context.Context, domainID string, opts UpdateDomainRequest) (*Domain, error)
+	DeleteDomain(ctx context.Context, domainID string) error
+
+	// DNS Record Operations
+	ListDNSRecords(ctx context.Context, domainID string, opts *ListDNSRecordsOptions) ([]DNSRecord, error)
+	GetDNSRecord(ctx context.Context, domainID, recordID string) (*DNSRecord, error)
+	CreateDNSRecord(ctx context.Context, domainID string, opts CreateDNSRecordRequest) (*DNSRecord, error)
+	UpdateDNSRecord(ctx context.Context, domainID, recordID string, opts UpdateDNSRecordRequest) (*DNSRecord, error)
+	DeleteDNSRecord(ctx context.Context, domainID, recordID string) error
+
+	// Pagination
+	EachDomain(ctx context.Context, opts *ListDomainsOptions, fn func(Domain) error) error
+	ListAllDomains(ctx context.Context, opts *ListDomainsOptions) ([]Domain, error)
+	EachDNSRecord(ctx context.Context, domainID string, opts *ListDNSRecordsOptions, fn func(DNSRecord) error) error
+	ListAllDNSRecords(ctx context.Context, domainID string, opts *ListDNSRecordsOptions) ([]DNSRecord, error)
+
+	// Name Resolution
+	ResolveDomain(ctx context.Context, nameOrID string) (*Domain, error)
+}
+
+// IdentityAPI is the Identity API of *Client: tokens, credentials,
+// sub-users and roles.
+type IdentityAPI interface {
+	// Token
+	Authenticate(ctx context.Context, userID, password, tenantID string) (*Token, error)
+	AuthenticateByName(ctx context.Context, userName, password, tenantName string) (*Token, error)
+	AuthenticateWithCredential(ctx context.Context, access, secret string) (*Token, error)
+	ValidateToken(ctx context.Context) (*Token, error)
+	RevokeToken(ctx context.Context) error
+
+	// Credentials
+	ListCredentials(ctx context.Context, userID string) ([]Credential, error)
+	CreateCredential(ctx context.Context, userID, tenantID string) (*Credential, error)
+	GetCredential(ctx context.Context, userID, credentialID string) (*Credential, error)
+	DeleteCredential(ctx context.Context, userID, credentialID string) error
+
+	// Sub-Users
+	ListSubUsers(ctx context.Context) ([]SubUser, error)
+	CreateSubUser(ctx context.Context, password string, roles []string) (*SubUser, error)
+	GetSubUser(ctx context.Context, subUserID string) (*SubUser, error)
+	UpdateSubUser(ctx context.Context, subUserID, password string) (*SubUser, error)
+	DeleteSubUser(ctx context.Context, subUserID string) error
+	AssignRolesToSubUser(ctx context.Context, subUserID string, roleIDs []string) (*SubUser, error)
+	UnassignRolesFromSubUser(ctx context.Context, subUserID string, roleIDs []string) (*SubUser, error)
+
+	// Roles
+	ListRoles(ctx context.Context) ([]RoleDetail, error)
+	CreateRole(ctx context.Context, name string, permissions []string) (*RoleDetail, error)
+	GetRole(ctx context.Context, roleID string) (*RoleDetail, error)
+	UpdateRole(ctx context.Context, roleID, name string) (*RoleDetail, error)
+	DeleteRole(ctx context.Context, roleID string) error
+
+	// Permissions
+	ListPermissions(ctx context.Context) ([]Permission, error)
+	AssignPermissionsToRole(ctx context.Context, roleID string, permissions []string) (*RoleDetail, error)
+	UnassignPermissionsFromRole(ctx context.Context, roleID string, permissions []string) (*RoleDetail, error)
+}
+
+// Compile-time checks that *Client implements every service interface.
+var (
+	_ ComputeAPI       = (*Client)(nil)
+	_ BlockStorageAPI  = (*Client)(nil)
+	_ ImageAPI         = (*Client)(nil)
+	_ NetworkAPI       = (*Client)(nil)
+	_ LoadBalancerAPI  = (*Client)(nil)
+	_ ObjectStorageAPI = (*Client)(nil)
+	_ DNSAPI           = (*Client)(nil)
+	_ IdentityAPI      = (*Client)(nil)
+)
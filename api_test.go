@@ -0,0 +1,42 @@
+package conoha
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestServiceInterfacesCoverClient checks that every API method of *Client
+// is in exactly one service interface, so that the interfaces (and the
+// fakes generated from them) keep up with new methods.
+func TestServiceInterfacesCoverClient(t *testing.T) {
+	interfaces := []reflect.Type{
+		reflect.TypeOf((*ComputeAPI)(nil)).Elem(),
+		reflect.TypeOf((*BlockStorageAPI)(nil)).Elem(),
+		reflect.TypeOf((*ImageAPI)(nil)).Elem(),
+		reflect.TypeOf((*NetworkAPI)(nil)).Elem(),
+		reflect.TypeOf((*LoadBalancerAPI)(nil)).Elem(),
+		reflect.TypeOf((*ObjectStorageAPI)(nil)).Elem(),
+		reflect.TypeOf((*DNSAPI)(nil)).Elem(),
+		reflect.TypeOf((*IdentityAPI)(nil)).Elem(),
+	}
+	// Client configuration rather than API calls.
+	notAPI := map[string]bool{"Endpoint": true, "Endpoints": true, "Catalog": true}
+
+	in := make(map[string][]string)
+	for _, it := range interfaces {
+		for i := 0; i < it.NumMethod(); i++ {
+			name := it.Method(i).Name
+			in[name] = append(in[name], it.Name())
+		}
+	}
+	client := reflect.TypeOf((*Client)(nil))
+	for i := 0; i < client.NumMethod(); i++ {
+		name := client.Method(i).Name
+		if notAPI[name] {
+			continue
+		}
+		if len(in[name]) != 1 {
+			t.Errorf("(*Client).%s is in interfaces %v, want exactly one", name, in[name])
+		}
+	}
+}
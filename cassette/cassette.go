@@ -0,0 +1,397 @@
+// Package cassette records HTTP traffic to JSON files and replays it, so
+// that tests written against the live ConoHa API can run offline.
+//
+// A Recorder is an http.RoundTripper. In Record mode it forwards requests
+// to the network and keeps every interaction; Stop writes them to the
+// cassette file with tokens, passwords, tenant IDs and adminPass values
+// scrubbed. In Replay mode it answers requests from the cassette without
+// touching the network.
+//
+//	rec, err := cassette.New("testdata/servers.json", cassette.Replay)
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//	client := conoha.NewClient(conoha.WithHTTPClient(rec.Client()))
+//
+// Requests are matched by method, URL path, query and body; JSON bodies
+// are compared after normalization and scrubbing, so key order and
+// redacted values don't matter. When several recorded interactions match,
+// they are replayed in the recorded order, which makes polling loops
+// replay the same status sequence they recorded.
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// Mode selects whether a Recorder records or replays.
+type Mode int
+
+const (
+	// Replay answers requests from the cassette file and never uses the
+	// network.
+	Replay Mode = iota
+	// Record sends requests to the network and saves the interactions to
+	// the cassette file on Stop.
+	Record
+)
+
+// String returns "replay" or "record".
+func (m Mode) String() string {
+	switch m {
+	case Replay:
+		return "replay"
+	case Record:
+		return "record"
+	}
+	return "Mode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// ErrNoInteraction is returned, wrapped, by a replaying Recorder for a
+// request that matches no unused interaction of the cassette.
+var ErrNoInteraction = errors.New("cassette: no matching interaction")
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the scrubbed form of a request.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// RecordedResponse is the scrubbed form of a response.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is a request or response body. It is stored as a string when it is
+// valid UTF-8 and as base64 otherwise.
+type Body []byte
+
+// MarshalJSON encodes b as a string, or as {"base64": "..."} for binary
+// data.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if isText(b) {
+		return marshal(string(b))
+	}
+	return marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON decodes the formats written by MarshalJSON.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+	var enc struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return fmt.Errorf("cassette: decoding body: %w", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(enc.Base64)
+	if err != nil {
+		return fmt.Errorf("cassette: decoding body: %w", err)
+	}
+	*b = raw
+	return nil
+}
+
+// Load reads a cassette file.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("cassette: parsing %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to path, creating its directory if needed.
+func (c *Cassette) Save(path string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return fmt.Errorf("cassette: encoding: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	return nil
+}
+
+// Recorder is an http.RoundTripper that records or replays a cassette. It
+// is safe for concurrent use, but concurrent requests are recorded in the
+// order they complete, so replay is only deterministic for sequential
+// traffic.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	scrubber  scrubber
+	ignored   map[string]bool // query parameters ignored by matching
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+	stopped  bool
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithTransport sets the transport a recording Recorder sends requests
+// with. The default is http.DefaultTransport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = rt
+	}
+}
+
+// WithReplacement replaces every occurrence of secret in URLs, headers and
+// bodies with placeholder, e.g. a user ID with "USER_ID". In replay mode
+// the replacement applies to incoming requests before matching. The option
+// can be given several times.
+//
+// Tenant IDs of the token responses are replaced with "TENANT_ID"
+// automatically.
+func WithReplacement(secret, placeholder string) Option {
+	return func(r *Recorder) {
+		if secret != "" {
+			r.scrubber.replacements = append(r.scrubber.replacements, replacement{secret, placeholder})
+		}
+	}
+}
+
+// WithRedactedHeaders adds headers whose values are replaced with
+// "REDACTED", in addition to the token, authorization and temporary URL
+// key headers.
+func WithRedactedHeaders(names ...string) Option {
+	return func(r *Recorder) {
+		for _, name := range names {
+			r.scrubber.headers[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// WithRedactedFields adds JSON object keys whose values are replaced with
+// "REDACTED" at any depth of request and response bodies, in addition to
+// password, adminPass and secret.
+func WithRedactedFields(keys ...string) Option {
+	return func(r *Recorder) {
+		for _, key := range keys {
+			r.scrubber.fields[key] = true
+		}
+	}
+}
+
+// WithIgnoredQuery makes request matching ignore the given query
+// parameters, such as the expiry time of a temporary URL.
+func WithIgnoredQuery(names ...string) Option {
+	return func(r *Recorder) {
+		for _, name := range names {
+			r.ignored[name] = true
+		}
+	}
+}
+
+// New returns a Recorder for the cassette at path. In Replay mode the
+// cassette is loaded immediately and must exist; in Record mode it is
+// written by Stop, replacing any previous content.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		scrubber:  newScrubber(),
+		ignored:   make(map[string]bool),
+		cassette:  &Cassette{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	switch mode {
+	case Replay:
+		c, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = c
+		r.used = make([]bool, len(c.Interactions))
+	case Record:
+	default:
+		return nil, fmt.Errorf("cassette: unknown mode %v", mode)
+	}
+	return r, nil
+}
+
+// Mode returns the mode of the Recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an HTTP client that uses the Recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Stop ends the recording and writes the scrubbed cassette file. It does
+// nothing in Replay mode or when called again.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mode != Record || r.stopped {
+		return nil
+	}
+	r.stopped = true
+	return r.scrubber.scrubCassette(r.cassette).Save(r.path)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: reading request body: %w", err)
+	}
+	if r.mode == Replay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cassette: reading response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := resp.Header.Clone()
+	header.Del("Content-Length") // scrubbing may change the length
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: req.Header.Clone(),
+			Body:   body,
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       respBody,
+		},
+	})
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	path, query := r.matchKey(req.URL)
+	body = r.scrubber.body(body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.cassette.Interactions {
+		in := &r.cassette.Interactions[i]
+		if r.used[i] || in.Request.Method != req.Method {
+			continue
+		}
+		u, err := url.Parse(in.Request.URL)
+		if err != nil {
+			continue
+		}
+		if p, q := r.matchKey(u); p != path || q != query {
+			continue
+		}
+		if !bytes.Equal(r.scrubber.body(in.Request.Body), body) {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        strconv.Itoa(in.Response.StatusCode) + " " + http.StatusText(in.Response.StatusCode),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w for %s %s", ErrNoInteraction, req.Method, req.URL)
+}
+
+// matchKey returns the scrubbed path and the encoded query of u without
+// the ignored parameters.
+func (r *Recorder) matchKey(u *url.URL) (path, query string) {
+	q := u.Query()
+	for name := range r.ignored {
+		q.Del(name)
+	}
+	return r.scrubber.replace(u.EscapedPath()), r.scrubber.replace(q.Encode())
+}
+
+// readBody reads and closes the body of req. It returns nil for a request
+// without a body.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,274 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+	"github.com/leonunix/conohav3-golang-sdk/conohatest"
+)
+
+const testTenant = "0123456789abcdef0123456789abcdef"
+
+// newUpstream returns a server that answers like a tiny ConoHa API: a
+// token request, a server creation with adminPass and a status that
+// changes between polls.
+func newUpstream(t *testing.T) *httptest.Server {
+	t.Helper()
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v3/auth/tokens":
+			w.Header().Set("X-Subject-Token", "secret-token")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token":{"project":{"id":%q},"catalog":[]}}`, testTenant)
+		case r.Method == http.MethodPost && r.URL.Path == "/v2.1/servers":
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprint(w, `{"server":{"id":"srv-1","adminPass":"p4ss"}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/v2.1/servers/srv-1":
+			polls++
+			status := "BUILD"
+			if polls > 1 {
+				status = "ACTIVE"
+			}
+			fmt.Fprintf(w, `{"server":{"id":"srv-1","status":%q}}`, status)
+		case r.URL.Path == "/v3/"+testTenant+"/volumes":
+			fmt.Fprint(w, `{"volumes":[]}`)
+		case r.URL.Path == "/v1/AUTH_"+testTenant+"/c/o":
+			w.Write([]byte{0xff, 0x00, 0xfe})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func do(t *testing.T, c *http.Client, method, url, body string) (int, string) {
+	t.Helper()
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Auth-Token", "secret-token")
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(data)
+}
+
+// record runs a fixed session against upstream and returns the cassette
+// path.
+func record(t *testing.T, upstream string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "testdata", "session.json")
+	rec, err := New(path, Record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := rec.Client()
+	do(t, c, "POST", upstream+"/v3/auth/tokens", `{"auth":{"identity":{"password":{"user":{"id":"u","password":"hunter2"}}},"scope":{"project":{"id":"`+testTenant+`"}}}}`)
+	if code, body := do(t, c, "POST", upstream+"/v2.1/servers", `{"server":{"flavorRef":"f","adminPass":"p4ss"}}`); code != 202 || !strings.Contains(body, "p4ss") {
+		t.Fatalf("recording returned %d %s, want the live response", code, body)
+	}
+	do(t, c, "GET", upstream+"/v2.1/servers/srv-1", "")
+	do(t, c, "GET", upstream+"/v2.1/servers/srv-1", "")
+	do(t, c, "GET", upstream+"/v3/"+testTenant+"/volumes?limit=10&marker=x", "")
+	do(t, c, "GET", upstream+"/v1/AUTH_"+testTenant+"/c/o", "")
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	return path
+}
+
+func TestRecordScrubsSecrets(t *testing.T) {
+	upstream := newUpstream(t)
+	path := record(t, upstream.URL)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret-token", "hunter2", "p4ss", testTenant} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+	for _, want := range []string{Redacted, "/v3/" + TenantPlaceholder + "/volumes", "AUTH_" + TenantPlaceholder} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("cassette does not contain %q", want)
+		}
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(c.Interactions) != 6 {
+		t.Fatalf("got %d interactions, want 6", len(c.Interactions))
+	}
+	if got := c.Interactions[5].Response.Body; !bytes.Equal(got, []byte{0xff, 0x00, 0xfe}) {
+		t.Errorf("binary body = %v, want it unchanged", got)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	upstream := newUpstream(t)
+	path := record(t, upstream.URL)
+	upstream.Close() // replay must not use the network
+
+	rec, err := New(path, Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Stop()
+	c := rec.Client()
+
+	// The body matches after scrubbing and key reordering.
+	code, body := do(t, c, "POST", upstream.URL+"/v3/auth/tokens", `{"auth":{"scope":{"project":{"id":"TENANT_ID"}},"identity":{"password":{"user":{"password":"other","id":"u"}}}}}`)
+	if code != 201 || !strings.Contains(body, TenantPlaceholder) {
+		t.Errorf("token = %d %s", code, body)
+	}
+	if code, _ := do(t, c, "POST", upstream.URL+"/v2.1/servers", `{"server":{"flavorRef":"f","adminPass":"x"}}`); code != 202 {
+		t.Errorf("create status = %d, want 202", code)
+	}
+
+	// Polls replay the recorded sequence.
+	for _, want := range []string{"BUILD", "ACTIVE"} {
+		if _, body := do(t, c, "GET", upstream.URL+"/v2.1/servers/srv-1", ""); !strings.Contains(body, want) {
+			t.Errorf("poll = %s, want %s", body, want)
+		}
+	}
+	req, _ := http.NewRequest("GET", upstream.URL+"/v2.1/servers/srv-1", nil)
+	if _, err := c.Do(req); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("third poll error = %v, want ErrNoInteraction", err)
+	}
+
+	// Query order doesn't matter.
+	if code, _ := do(t, c, "GET", upstream.URL+"/v3/TENANT_ID/volumes?marker=x&limit=10", ""); code != 200 {
+		t.Errorf("volumes status = %d, want 200", code)
+	}
+	if _, body := do(t, c, "GET", upstream.URL+"/v1/AUTH_TENANT_ID/c/o", ""); body != "\xff\x00\xfe" {
+		t.Errorf("object = %q", body)
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	upstream := newUpstream(t)
+	path := record(t, upstream.URL)
+
+	rec, err := New(path, Replay, WithReplacement("real-tenant", TenantPlaceholder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := rec.Client()
+	for _, tc := range []struct{ method, url, body string }{
+		{"DELETE", "/v2.1/servers/srv-1", ""},
+		{"POST", "/v2.1/servers", `{"server":{"flavorRef":"g"}}`},
+		{"GET", "/v3/TENANT_ID/volumes?limit=10", ""},
+	} {
+		req, _ := http.NewRequest(tc.method, upstream.URL+tc.url, strings.NewReader(tc.body))
+		if _, err := c.Do(req); !errors.Is(err, ErrNoInteraction) {
+			t.Errorf("%s %s error = %v, want ErrNoInteraction", tc.method, tc.url, err)
+		}
+	}
+
+	// Replacements apply to incoming requests.
+	if code, _ := do(t, c, "GET", upstream.URL+"/v3/real-tenant/volumes?limit=10&marker=x", ""); code != 200 {
+		t.Errorf("volumes status = %d, want 200", code)
+	}
+}
+
+func TestIgnoredQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "object")
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "temp.json")
+
+	rec, err := New(path, Record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	do(t, rec.Client(), "GET", srv.URL+"/v1/AUTH_t/c/o?temp_url_sig=a&temp_url_expires=100", "")
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err = New(path, Replay, WithIgnoredQuery("temp_url_sig", "temp_url_expires"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, body := do(t, rec.Client(), "GET", srv.URL+"/v1/AUTH_t/c/o?temp_url_sig=b&temp_url_expires=200", ""); body != "object" {
+		t.Errorf("body = %q, want object", body)
+	}
+}
+
+func TestNewReplayMissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), Replay); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestClientRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	srv := conohatest.NewServer()
+	path := filepath.Join(t.TempDir(), "client.json")
+
+	rec, err := New(path, Record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := srv.NewClient(conoha.WithHTTPClient(rec.Client()))
+	kp, err := client.CreateKeypair(ctx, "kp")
+	if err != nil {
+		t.Fatalf("CreateKeypair: %v", err)
+	}
+	vol, err := client.CreateVolume(ctx, conoha.CreateVolumeRequest{Size: 100, Name: "data"})
+	if err != nil {
+		t.Fatalf("CreateVolume: %v", err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	rec, err = New(path, Replay, WithReplacement(conohatest.TenantID, TenantPlaceholder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed := srv.NewClient(conoha.WithHTTPClient(rec.Client()))
+	gotKP, err := replayed.CreateKeypair(ctx, "kp")
+	if err != nil {
+		t.Fatalf("replayed CreateKeypair: %v", err)
+	}
+	if gotKP.Fingerprint != kp.Fingerprint || gotKP.PrivateKey != kp.PrivateKey {
+		t.Errorf("replayed keypair = %+v, want %+v", gotKP, kp)
+	}
+	gotVol, err := replayed.CreateVolume(ctx, conoha.CreateVolumeRequest{Size: 100, Name: "data"})
+	if err != nil {
+		t.Fatalf("replayed CreateVolume: %v", err)
+	}
+	if gotVol.ID != vol.ID {
+		t.Errorf("replayed volume ID = %s, want %s", gotVol.ID, vol.ID)
+	}
+}
@@ -0,0 +1,183 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redacted replaces the values of redacted headers and JSON fields.
+const Redacted = "REDACTED"
+
+// TenantPlaceholder replaces the tenant IDs found in token responses.
+const TenantPlaceholder = "TENANT_ID"
+
+type replacement struct {
+	secret, placeholder string
+}
+
+// scrubber removes credentials from recorded interactions.
+type scrubber struct {
+	headers      map[string]bool // canonical header names
+	fields       map[string]bool // JSON object keys
+	replacements []replacement
+}
+
+func newScrubber() scrubber {
+	return scrubber{
+		headers: map[string]bool{
+			"Authorization":                   true,
+			"X-Auth-Token":                    true,
+			"X-Subject-Token":                 true,
+			"X-Account-Meta-Temp-Url-Key":     true,
+			"X-Account-Meta-Temp-Url-Key-2":   true,
+			"X-Container-Meta-Temp-Url-Key":   true,
+			"X-Container-Meta-Temp-Url-Key-2": true,
+		},
+		fields: map[string]bool{
+			"password":  true,
+			"adminPass": true,
+			"secret":    true,
+		},
+	}
+}
+
+// scrubCassette returns a scrubbed copy of c. It first adds a replacement
+// for the tenant ID of every token response in c.
+func (s *scrubber) scrubCassette(c *Cassette) *Cassette {
+	for _, in := range c.Interactions {
+		if id := tokenTenantID(in.Response.Body); id != "" {
+			s.learn(id, TenantPlaceholder)
+		}
+	}
+	out := &Cassette{Interactions: make([]Interaction, len(c.Interactions))}
+	for i, in := range c.Interactions {
+		out.Interactions[i] = Interaction{
+			Request: RecordedRequest{
+				Method: in.Request.Method,
+				URL:    s.replace(in.Request.URL),
+				Header: s.header(in.Request.Header),
+				Body:   s.body(in.Request.Body),
+			},
+			Response: RecordedResponse{
+				StatusCode: in.Response.StatusCode,
+				Header:     s.header(in.Response.Header),
+				Body:       s.body(in.Response.Body),
+			},
+		}
+	}
+	return out
+}
+
+// learn adds a replacement unless secret is already replaced.
+func (s *scrubber) learn(secret, placeholder string) {
+	for _, r := range s.replacements {
+		if r.secret == secret {
+			return
+		}
+	}
+	s.replacements = append(s.replacements, replacement{secret, placeholder})
+}
+
+// replace applies the replacements to v.
+func (s *scrubber) replace(v string) string {
+	for _, r := range s.replacements {
+		v = strings.ReplaceAll(v, r.secret, r.placeholder)
+	}
+	return v
+}
+
+// header returns a scrubbed copy of h.
+func (s *scrubber) header(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(http.Header, len(h))
+	for name, values := range h {
+		scrubbed := make([]string, len(values))
+		for i, v := range values {
+			if s.headers[http.CanonicalHeaderKey(name)] {
+				scrubbed[i] = Redacted
+			} else {
+				scrubbed[i] = s.replace(v)
+			}
+		}
+		out[name] = scrubbed
+	}
+	return out
+}
+
+// body returns the scrubbed and normalized form of b. JSON bodies are
+// re-encoded with sorted keys and their redacted fields replaced; binary
+// bodies are returned unchanged.
+func (s *scrubber) body(b []byte) Body {
+	if len(b) == 0 {
+		return nil
+	}
+	if !isText(b) {
+		return b
+	}
+	if v, ok := decodeJSON(b); ok {
+		if enc, err := marshal(s.redact(v)); err == nil {
+			b = enc
+		}
+	}
+	return Body(s.replace(string(b)))
+}
+
+// redact replaces the string values of the redacted fields at any depth
+// of v. Objects under a redacted key, such as the password method of a
+// token request, are scrubbed recursively instead.
+func (s *scrubber) redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, val := range v {
+			if _, scalar := val.(string); scalar && s.fields[key] {
+				v[key] = Redacted
+			} else {
+				v[key] = s.redact(val)
+			}
+		}
+	case []any:
+		for i, val := range v {
+			v[i] = s.redact(val)
+		}
+	}
+	return v
+}
+
+// tokenTenantID returns the project ID of an Identity token response
+// body, or "".
+func tokenTenantID(b []byte) string {
+	var resp struct {
+		Token struct {
+			Project struct {
+				ID string `json:"id"`
+			} `json:"project"`
+		} `json:"token"`
+	}
+	if json.Unmarshal(b, &resp) != nil {
+		return ""
+	}
+	return resp.Token.Project.ID
+}
+
+func decodeJSON(b []byte) (any, bool) {
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return nil, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return nil, false
+	}
+	return v, true
+}
+
+func isText(b []byte) bool {
+	return utf8.Valid(b)
+}
@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 )
 
 const (
@@ -35,20 +37,32 @@ const (
 // The Client is safe for concurrent use across goroutines. Internally it uses
 // a sync.RWMutex to protect Token, TenantID, and endpoint URL fields from
 // data races when Authenticate() is called concurrently with other API methods.
+// Read the endpoints with Endpoint or Endpoints rather than the URL fields.
 type Client struct {
 	HTTPClient *http.Client
 	Token      string
 	TenantID   string
 	Region     string
 
-	IdentityURL      string
-	ComputeURL       string
-	BlockStorageURL  string
-	ImageServiceURL  string
-	NetworkingURL    string
-	LBaaSURL         string
+	// The endpoint URLs are updated by authentication while API calls may
+	// be in flight, so reading them directly races with Authenticate.
+
+	// Deprecated: Use Endpoint(ServiceTypeIdentity) and WithIdentityURL.
+	IdentityURL string
+	// Deprecated: Use Endpoint(ServiceTypeCompute) and WithComputeURL.
+	ComputeURL string
+	// Deprecated: Use Endpoint(ServiceTypeBlockStorage) and WithBlockStorageURL.
+	BlockStorageURL string
+	// Deprecated: Use Endpoint(ServiceTypeImage) and WithImageServiceURL.
+	ImageServiceURL string
+	// Deprecated: Use Endpoint(ServiceTypeNetwork) and WithNetworkingURL.
+	NetworkingURL string
+	// Deprecated: Use Endpoint(ServiceTypeLBaaS) and WithLBaaSURL.
+	LBaaSURL string
+	// Deprecated: Use Endpoint(ServiceTypeObjectStore) and WithObjectStorageURL.
 	ObjectStorageURL string
-	DNSServiceURL    string
+	// Deprecated: Use Endpoint(ServiceTypeDNS) and WithDNSServiceURL.
+	DNSServiceURL string
 
 	// mu protects Token, TenantID, and endpoint URL fields from concurrent
 	// read/write access (e.g. Authenticate writing while API methods read).
@@ -60,6 +74,63 @@ type Client struct {
 
 	// explicitRegion is true when the user explicitly called WithRegion().
 	explicitRegion bool
+
+	// reauth re-issues a token using the credentials passed to the most
+	// recent successful Authenticate* call. It is nil until then, in which
+	// case tokens are never refreshed automatically.
+	reauth func(ctx context.Context) error
+
+	// tokenBase is the client a region client takes its token from. Whether
+	// the token can be re-issued is up to it.
+	tokenBase *Client
+
+	// tokenExpiresAt is the parsed expires_at of the current token.
+	// The zero value means the expiry is unknown.
+	tokenExpiresAt time.Time
+
+	// catalog is the service catalog of the current token, with the
+	// endpoints of every region.
+	catalog []ServiceCatalog
+
+	// refreshing is the in-flight token refresh, shared by every caller
+	// that needs a new token while it is running.
+	refreshing *tokenRefresh
+
+	// retryPolicy is set by WithRetryPolicy. nil disables retries.
+	retryPolicy *RetryPolicy
+
+	// limiter is set by WithRateLimit. nil disables rate limiting.
+	limiter *rateLimiter
+
+	// middlewares wrap HTTPClient for every attempt; see WithMiddleware.
+	middlewares []Middleware
+
+	// logger is set by WithLogger. nil disables logging.
+	logger *slog.Logger
+
+	// tracer is set by WithTracer. nil disables tracing.
+	tracer Tracer
+
+	// metrics is set by WithMetrics. nil disables metrics.
+	metrics MetricsCollector
+
+	// tokenStore is set by WithTokenStore. nil disables token caching.
+	tokenStore TokenStore
+
+	// tokenKey is the token store key of the current token.
+	tokenKey string
+
+	// endpointSources records where each endpoint URL that was not set
+	// explicitly came from; see Endpoints.
+	endpointSources map[string]EndpointSource
+
+	// resolver is set by WithEndpointResolver. nil uses the catalog and
+	// region pattern only.
+	resolver EndpointResolver
+
+	// lazyAuth is set by NewClientFromConfig, whose clients authenticate
+	// on their first request.
+	lazyAuth *lazyAuthState
 }
 
 // ClientOption configures the Client.
@@ -82,6 +153,16 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithToken starts the client with an existing token, e.g. one minted by
+// a central service, instead of authenticating with a password. Call
+// ValidateToken to fill in the tenant ID and endpoints from the token.
+// Such a client cannot re-issue the token when it expires.
+func WithToken(token string) ClientOption {
+	return func(c *Client) {
+		c.Token = token
+	}
+}
+
 // WithIdentityURL sets only the Identity API endpoint.
 // Other endpoints will be auto-discovered from the Service Catalog after authentication.
 func WithIdentityURL(url string) ClientOption {
@@ -203,8 +284,11 @@ func WithEndpoints(ep Endpoints) ClientOption {
 //
 // Endpoint resolution order (highest priority first):
 //  1. Explicitly set via With*URL() or WithEndpoints() — never overridden
-//  2. Auto-discovered from Service Catalog after Authenticate()
-//  3. Generated from Region pattern https://{service}.{region}.conoha.io
+//  2. Returned by the EndpointResolver set via WithEndpointResolver()
+//  3. Auto-discovered from Service Catalog after Authenticate()
+//  4. Generated from Region pattern https://{service}.{region}.conoha.io
+//
+// Endpoints() reports the URL and source of each endpoint.
 //
 // Examples:
 //
@@ -247,6 +331,7 @@ func NewClient(opts ...ClientOption) *Client {
 	// SDK methods can append resource paths directly (e.g. "/auth/tokens").
 	c.normalizeExplicitURLs()
 
+	c.applyResolver(nil)
 	c.fillURLsFromRegion()
 	return c
 }
@@ -254,32 +339,32 @@ func NewClient(opts ...ClientOption) *Client {
 // fillURLsFromRegion fills any unset URLs using the region pattern.
 // Only fills URLs that were NOT explicitly set by the user.
 // Generated URLs include the API version path so that service methods
-// can append resource paths directly (e.g. c.ComputeURL + "/servers").
+// can append resource paths directly (e.g. c.computeURL() + "/servers").
 func (c *Client) fillURLsFromRegion() {
 	r := c.Region
 	if c.IdentityURL == "" {
-		c.IdentityURL = fmt.Sprintf("https://identity.%s.conoha.io/v3", r)
+		c.setEndpoint(ServiceTypeIdentity, fmt.Sprintf("https://identity.%s.conoha.io/v3", r), EndpointSourceRegion)
 	}
 	if c.ComputeURL == "" {
-		c.ComputeURL = fmt.Sprintf("https://compute.%s.conoha.io/v2.1", r)
+		c.setEndpoint(ServiceTypeCompute, fmt.Sprintf("https://compute.%s.conoha.io/v2.1", r), EndpointSourceRegion)
 	}
 	if c.BlockStorageURL == "" {
-		c.BlockStorageURL = fmt.Sprintf("https://block-storage.%s.conoha.io/v3", r)
+		c.setEndpoint(ServiceTypeBlockStorage, fmt.Sprintf("https://block-storage.%s.conoha.io/v3", r), EndpointSourceRegion)
 	}
 	if c.ImageServiceURL == "" {
-		c.ImageServiceURL = fmt.Sprintf("https://image-service.%s.conoha.io/v2", r)
+		c.setEndpoint(ServiceTypeImage, fmt.Sprintf("https://image-service.%s.conoha.io/v2", r), EndpointSourceRegion)
 	}
 	if c.NetworkingURL == "" {
-		c.NetworkingURL = fmt.Sprintf("https://networking.%s.conoha.io/v2.0", r)
+		c.setEndpoint(ServiceTypeNetwork, fmt.Sprintf("https://networking.%s.conoha.io/v2.0", r), EndpointSourceRegion)
 	}
 	if c.LBaaSURL == "" {
-		c.LBaaSURL = fmt.Sprintf("https://lbaas.%s.conoha.io/v2.0", r)
+		c.setEndpoint(ServiceTypeLBaaS, fmt.Sprintf("https://lbaas.%s.conoha.io/v2.0", r), EndpointSourceRegion)
 	}
 	if c.ObjectStorageURL == "" {
-		c.ObjectStorageURL = fmt.Sprintf("https://object-storage.%s.conoha.io/v1", r)
+		c.setEndpoint(ServiceTypeObjectStore, fmt.Sprintf("https://object-storage.%s.conoha.io/v1", r), EndpointSourceRegion)
 	}
 	if c.DNSServiceURL == "" {
-		c.DNSServiceURL = fmt.Sprintf("https://dns-service.%s.conoha.io/v1", r)
+		c.setEndpoint(ServiceTypeDNS, fmt.Sprintf("https://dns-service.%s.conoha.io/v1", r), EndpointSourceRegion)
 	}
 }
 
@@ -287,75 +372,23 @@ func (c *Client) fillURLsFromRegion() {
 // returned by authentication. Only updates URLs that were NOT explicitly set.
 // When the client has a Region set, only endpoints matching that region are used.
 //
-// The ConoHa service catalog may return base URLs without version paths
-// (e.g. "https://networking.c3j1.conoha.io" instead of ".../v2.0").
-// This method normalizes catalog URLs by ensuring the required API version
-// path is present, since SDK methods append only resource paths (e.g. "/servers").
+// Catalog URLs are normalized by normalizeCatalogURL, since SDK methods
+// append only resource paths (e.g. "/servers"). Afterwards the
+// EndpointResolver, if any, may replace them.
 func (c *Client) updateEndpointsFromCatalog(catalog []ServiceCatalog) {
 	for _, svc := range catalog {
-		// Find the public endpoint URL, preferring one that matches the client's region.
-		var publicURL string
-		for _, ep := range svc.Endpoints {
-			if ep.Interface != "public" {
-				continue
-			}
-			if c.Region != "" && ep.Region != c.Region && ep.RegionID != c.Region {
-				continue
-			}
-			publicURL = strings.TrimRight(ep.URL, "/")
-			break
+		service := catalogServiceType(svc.Type)
+		if service == "" || c.explicitURLs[service] {
+			continue
 		}
+		// Use the public endpoint URL that matches the client's region.
+		publicURL := catalogURL(svc, "public", c.Region)
 		if publicURL == "" {
 			continue
 		}
-
-		switch svc.Type {
-		case ServiceTypeIdentity:
-			if !c.explicitURLs["identity"] {
-				c.IdentityURL = ensureVersionPath(publicURL, "/v3")
-			}
-		case ServiceTypeCompute:
-			if !c.explicitURLs["compute"] {
-				c.ComputeURL = ensureVersionPath(publicURL, "/v2.1")
-			}
-		case ServiceTypeBlockStorage, "volumev3":
-			if !c.explicitURLs["block-storage"] {
-				// Catalog may include tenant ID in path (e.g. /v3/{tenantID}).
-				// Strip everything after the version path since SDK methods add tenant ID.
-				u := ensureVersionPath(publicURL, "/v3")
-				if idx := strings.Index(u, "/v3/"); idx >= 0 {
-					u = u[:idx+3]
-				}
-				c.BlockStorageURL = u
-			}
-		case ServiceTypeImage:
-			if !c.explicitURLs["image"] {
-				c.ImageServiceURL = ensureVersionPath(publicURL, "/v2")
-			}
-		case ServiceTypeNetwork:
-			if !c.explicitURLs["network"] {
-				c.NetworkingURL = ensureVersionPath(publicURL, "/v2.0")
-			}
-		case ServiceTypeLBaaS:
-			if !c.explicitURLs["load-balancer"] {
-				c.LBaaSURL = ensureVersionPath(publicURL, "/v2.0")
-			}
-		case ServiceTypeObjectStore:
-			if !c.explicitURLs["object-store"] {
-				// Catalog may include /v1/AUTH_{tenantID}. Strip the AUTH_ portion
-				// since objectStoragePath() appends it.
-				u := publicURL
-				if idx := strings.Index(u, "/AUTH_"); idx >= 0 {
-					u = u[:idx]
-				}
-				c.ObjectStorageURL = ensureVersionPath(u, "/v1")
-			}
-		case ServiceTypeDNS:
-			if !c.explicitURLs["dns"] {
-				c.DNSServiceURL = ensureVersionPath(publicURL, "/v1")
-			}
-		}
+		c.setEndpoint(service, normalizeCatalogURL(service, publicURL), EndpointSourceCatalog)
 	}
+	c.applyResolver(catalog)
 }
 
 // extractRegionFromConoHaURL extracts the region from a ConoHa-style URL.
@@ -417,20 +450,33 @@ func ensureVersionPath(rawURL, versionPath string) string {
 // The Body field always contains the raw response body string.
 // If the response body is a standard OpenStack JSON error (e.g.
 // {"badRequest": {"message": "Invalid input", "code": 400}}),
-// the Message and Code fields are populated with the parsed values.
+// the Message and Code fields are populated with the parsed values and
+// ErrorType holds the error key ("badRequest").
+//
+// Use errors.Is with ErrNotFound, ErrConflict and the other sentinel errors,
+// or the IsNotFound family of helpers, to classify an APIError.
 type APIError struct {
 	StatusCode int
 	Status     string
 	Body       string
 	Message    string // Parsed error message from JSON body, if available.
 	Code       int    // Parsed error code from JSON body, if available.
+	ErrorType  string // OpenStack error key from JSON body, e.g. "itemNotFound".
+
+	Method    string // Method of the failed request.
+	URL       string // URL of the failed request.
+	RequestID string // X-Openstack-Request-Id (or service equivalent) of the response.
 }
 
 func (e *APIError) Error() string {
+	prefix := "conoha api error: "
+	if e.Method != "" && e.URL != "" {
+		prefix += e.Method + " " + e.URL + ": "
+	}
 	if e.Message != "" {
-		return fmt.Sprintf("conoha api error: %s: %s", e.Status, e.Message)
+		return fmt.Sprintf("%s%s: %s", prefix, e.Status, e.Message)
 	}
-	return fmt.Sprintf("conoha api error: %s (body: %s)", e.Status, e.Body)
+	return fmt.Sprintf("%s%s (body: %s)", prefix, e.Status, e.Body)
 }
 
 // newAPIError creates an APIError and attempts to parse the body as a
@@ -442,15 +488,17 @@ func newAPIError(statusCode int, status, body string) *APIError {
 		Body:       body,
 	}
 
-	// Try to parse OpenStack-style error: {"errorType": {"message": "...", "code": N}}
+	// Try to parse OpenStack-style error: {"errorType": {"message": "...", "code": N}}.
+	// Networking wraps its errors as {"NeutronError": {"type": "...", "message": "..."}}.
 	var parsed map[string]json.RawMessage
 	if err := json.Unmarshal([]byte(body), &parsed); err != nil {
 		return e
 	}
-	for _, raw := range parsed {
+	for key, raw := range parsed {
 		var inner struct {
 			Message string `json:"message"`
 			Code    int    `json:"code"`
+			Type    string `json:"type"`
 		}
 		if err := json.Unmarshal(raw, &inner); err != nil {
 			continue
@@ -458,12 +506,28 @@ func newAPIError(statusCode int, status, body string) *APIError {
 		if inner.Message != "" {
 			e.Message = inner.Message
 			e.Code = inner.Code
+			e.ErrorType = key
+			if inner.Type != "" {
+				e.ErrorType = inner.Type
+			}
 			break
 		}
 	}
 	return e
 }
 
+// newResponseError creates an APIError for an error response, including
+// the request method and URL and the request ID of the response.
+func newResponseError(resp *http.Response, body []byte) *APIError {
+	e := newAPIError(resp.StatusCode, resp.Status, string(body))
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = resp.Request.URL.String()
+	}
+	e.RequestID = responseRequestID(resp.Header)
+	return e
+}
+
 // Link represents a resource link.
 type Link struct {
 	Rel  string `json:"rel"`
@@ -477,6 +541,13 @@ func (c *Client) tenantID() string {
 	return c.TenantID
 }
 
+// currentToken returns the Token under a read lock.
+func (c *Client) currentToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Token
+}
+
 func (c *Client) newRequest(ctx context.Context, method, url string, body interface{}) (*http.Request, error) {
 	var bodyReader io.Reader
 	if body != nil {
@@ -487,7 +558,7 @@ func (c *Client) newRequest(ctx context.Context, method, url string, body interf
 		bodyReader = bytes.NewReader(b)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	req, err := c.newRawRequest(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, err
 	}
@@ -496,17 +567,26 @@ func (c *Client) newRequest(ctx context.Context, method, url string, body interf
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	c.mu.RLock()
-	token := c.Token
-	c.mu.RUnlock()
-	if token != "" {
+	return req, nil
+}
+
+// newRawRequest builds a request that sends body as is, for endpoints that
+// stream their payload or take no JSON body, such as object and ISO
+// uploads. Like newRequest it carries the client's current token; send
+// takes care of lazy authentication, token refresh and retries.
+func (c *Client) newRawRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if token := c.currentToken(); token != "" {
 		req.Header.Set("X-Auth-Token", token)
 	}
 	return req, nil
 }
 
 func (c *Client) do(req *http.Request, result interface{}) (*http.Response, error) {
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -518,7 +598,7 @@ func (c *Client) do(req *http.Request, result interface{}) (*http.Response, erro
 	}
 
 	if resp.StatusCode >= 400 {
-		return resp, newAPIError(resp.StatusCode, resp.Status, string(respBody))
+		return resp, newResponseError(resp, respBody)
 	}
 
 	if result != nil && len(respBody) > 0 {
@@ -530,7 +610,7 @@ func (c *Client) do(req *http.Request, result interface{}) (*http.Response, erro
 }
 
 func (c *Client) doRaw(req *http.Request) (*http.Response, []byte, error) {
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -542,11 +622,154 @@ func (c *Client) doRaw(req *http.Request) (*http.Response, []byte, error) {
 	}
 
 	if resp.StatusCode >= 400 {
-		return resp, respBody, newAPIError(resp.StatusCode, resp.Status, string(respBody))
+		return resp, respBody, newResponseError(resp, respBody)
 	}
 	return resp, respBody, nil
 }
 
+// send executes req with the client's HTTP client and middlewares,
+// retrying transient failures according to the RetryPolicy set with
+// WithRetryPolicy. Every request of the SDK goes through send.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	req, err := c.authenticateLazily(req)
+	if err != nil {
+		return nil, err
+	}
+	if c.tracer == nil && c.metrics == nil {
+		return c.sendWithReauth(req)
+	}
+
+	op := c.operation(req)
+	ctx := context.WithValue(req.Context(), operationKey{}, op)
+	var span Span
+	if c.tracer != nil {
+		ctx, span = c.tracer.Start(ctx, op)
+	}
+	start := time.Now()
+	resp, err := c.sendWithReauth(req.WithContext(ctx))
+
+	var status int
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	if span != nil {
+		span.End(status, err)
+	}
+	if c.metrics != nil {
+		c.metrics.ObserveRequest(op, status, time.Since(start), err)
+	}
+	return resp, err
+}
+
+// sendWithReauth sends req, keeping its token fresh.
+//
+// Requests that carry an X-Auth-Token get their token re-issued shortly
+// before it expires, and are replayed once with a fresh token when the API
+// answers 401 Unauthorized. Both only happen after a successful
+// Authenticate or AuthenticateByName call.
+func (c *Client) sendWithReauth(req *http.Request) (*http.Response, error) {
+	token := req.Header.Get("X-Auth-Token")
+	if token != "" && c.tokenNeedsRefresh() {
+		// A failed proactive refresh is not fatal: the current token may
+		// still be accepted, and a 401 below triggers another attempt.
+		if err := c.refreshToken(req.Context(), token); err == nil {
+			token = c.currentToken()
+			req.Header.Set("X-Auth-Token", token)
+		}
+	}
+
+	resp, err := c.sendWithRetry(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || token == "" || !c.canReauth() {
+		return resp, err
+	}
+
+	retry, err := rewindRequest(req)
+	if err != nil {
+		// The body cannot be replayed; surface the original 401.
+		return resp, nil
+	}
+	drainAndClose(resp.Body)
+
+	if err := c.refreshToken(req.Context(), token); err != nil {
+		return nil, fmt.Errorf("refresh token: %w", err)
+	}
+	retry.Header.Set("X-Auth-Token", c.currentToken())
+	return c.sendWithRetry(retry)
+}
+
+// attempt sends req once through the middleware chain, after waiting for
+// the rate limiter. n is the 1-based attempt number within the retry loop.
+func (c *Client) attempt(req *http.Request, n int) (*http.Response, error) {
+	release, err := c.limiter.acquire(req.Context(), c.serviceForURL(req.URL.String()))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doLogged(c.doer(), req, n)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// endpointBase is the endpoint URL of a service.
+type endpointBase struct{ service, base string }
+
+// endpointBases returns the current endpoint URL of every service.
+func (c *Client) endpointBases() [len(endpointServices)]endpointBase {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var bases [len(endpointServices)]endpointBase
+	for i, service := range endpointServices {
+		bases[i] = endpointBase{service, *c.endpointField(service)}
+	}
+	return bases
+}
+
+// matchEndpoint returns the entry of endpoints whose URL is the longest
+// prefix of rawURL, or the zero value if there is none.
+func matchEndpoint(endpoints [len(endpointServices)]endpointBase, rawURL string) endpointBase {
+	var match endpointBase
+	for _, ep := range endpoints {
+		if ep.base != "" && len(ep.base) > len(match.base) && strings.HasPrefix(rawURL, ep.base) {
+			match = ep
+		}
+	}
+	return match
+}
+
+// serviceForURL returns the service type (one of the ServiceType*
+// constants) whose endpoint URL is the longest prefix of rawURL, or "" if
+// rawURL does not belong to any configured endpoint.
+func (c *Client) serviceForURL(rawURL string) string {
+	return matchEndpoint(c.endpointBases(), rawURL).service
+}
+
+// rewindRequest returns a copy of req whose body can be sent again.
+// It fails for streaming bodies that were not built from a byte buffer.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return clone, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("request body of %s %s cannot be replayed", req.Method, req.URL.Path)
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	return clone, nil
+}
+
+// drainAndClose discards the rest of body so the connection can be reused.
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(io.Discard, body)
+	body.Close()
+}
+
 func buildQueryString(params map[string]string) string {
 	if len(params) == 0 {
 		return ""
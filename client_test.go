@@ -3,6 +3,7 @@ package conoha
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -659,6 +660,20 @@ func TestNewAPIError_OpenStackFormat(t *testing.T) {
 	if err.Body != body {
 		t.Errorf("Body = %q", err.Body)
 	}
+	if err.ErrorType != "badRequest" {
+		t.Errorf("ErrorType = %q", err.ErrorType)
+	}
+}
+
+func TestNewAPIError_NeutronFormat(t *testing.T) {
+	err := newAPIError(404, "404 Not Found", `{"NeutronError":{"type":"PortNotFound","message":"Port p1 could not be found.","detail":""}}`)
+
+	if err.ErrorType != "PortNotFound" {
+		t.Errorf("ErrorType = %q", err.ErrorType)
+	}
+	if err.Message != "Port p1 could not be found." {
+		t.Errorf("Message = %q", err.Message)
+	}
 }
 
 func TestNewAPIError_PlainText(t *testing.T) {
@@ -768,6 +783,71 @@ func TestClient_ConcurrentTokenAccess(t *testing.T) {
 	wg.Wait()
 }
 
+func TestClient_ConcurrentAuthenticateAndAPICalls(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/auth/tokens"):
+			w.Header().Set("X-Subject-Token", "new-token")
+			w.WriteHeader(201)
+			fmt.Fprintf(w, `{"token":{"project":{"id":"tenant-123"},"catalog":[
+				{"type":"compute","endpoints":[{"interface":"public","region":"c3j1","url":"%[1]s/compute"}]},
+				{"type":"volumev3","endpoints":[{"interface":"public","region":"c3j1","url":"%[1]s/volume/v3/tenant-123"}]},
+				{"type":"network","endpoints":[{"interface":"public","region":"c3j1","url":"%[1]s/network"}]},
+				{"type":"dns","endpoints":[{"interface":"public","region":"c3j1","url":"%[1]s/dns"}]},
+				{"type":"image","endpoints":[{"interface":"public","region":"c3j1","url":"%[1]s/image"}]},
+				{"type":"object-store","endpoints":[{"interface":"public","region":"c3j1","url":"%[1]s/object/v1/AUTH_tenant-123"}]}
+			]}}`, serverURL)
+		case r.Method == http.MethodPut:
+			w.WriteHeader(201)
+		case strings.Contains(r.URL.Path, "/servers"):
+			w.Write([]byte(`{"servers":[]}`))
+		case strings.Contains(r.URL.Path, "/volumes"):
+			w.Write([]byte(`{"volumes":[]}`))
+		case strings.Contains(r.URL.Path, "/networks"):
+			w.Write([]byte(`{"networks":[]}`))
+		default:
+			w.Write([]byte(`{"domains":[]}`))
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	c := NewClient(WithIdentityURL(server.URL))
+	_, err := c.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+
+	ctx := context.Background()
+	calls := []func() error{
+		func() error { _, err := c.Authenticate(ctx, "user", "pass", "tenant"); return err },
+		func() error { _, err := c.ListServers(ctx, nil); return err },
+		func() error { _, err := c.ListVolumes(ctx, nil); return err },
+		func() error { _, err := c.ListNetworks(ctx, nil); return err },
+		func() error { _, err := c.ListDomains(ctx, nil); return err },
+		func() error { return c.UploadObject(ctx, "bucket", "obj", strings.NewReader("data")) },
+		func() error { return c.UploadISOImage(ctx, "image-1", strings.NewReader("iso")) },
+		func() error { _ = c.Endpoints(); return nil },
+	}
+	var wg sync.WaitGroup
+	for _, call := range calls {
+		wg.Add(1)
+		go func(call func() error) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if err := call(); err != nil {
+					t.Errorf("concurrent call: %v", err)
+					return
+				}
+			}
+		}(call)
+	}
+	wg.Wait()
+
+	if got := c.Endpoint(ServiceTypeCompute); got != server.URL+"/compute/v2.1" {
+		t.Errorf("compute endpoint = %q", got)
+	}
+}
+
 // ============================================================
 // WithEndpoints - all service types
 // ============================================================
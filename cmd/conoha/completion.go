@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+// completeCommand is the hidden command the completion scripts call with
+// the words of the command line, the last one being the word to complete.
+// It prints one candidate per line.
+const completeCommand = "__complete"
+
+var completionScripts = map[string]string{
+	"bash": `# bash completion for conoha
+_conoha() {
+	local IFS=$'\n'
+	COMPREPLY=($(conoha __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _conoha conoha
+`,
+	"zsh": `#compdef conoha
+# zsh completion for conoha
+_conoha() {
+	local -a candidates
+	candidates=("${(@f)$(conoha __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+	compadd -a candidates
+}
+compdef _conoha conoha
+`,
+	"fish": `# fish completion for conoha
+complete -c conoha -f -a '(conoha __complete (commandline -opc)[2..-1] (commandline -ct))'
+`,
+}
+
+func completionCommand() *command {
+	return &command{
+		name:    "completion",
+		args:    "bash|zsh|fish",
+		summary: "Print the shell completion script",
+		nargs:   1,
+		local:   true,
+		complete: []lister{func(context.Context, *conoha.Client) ([]ref, error) {
+			return []ref{{"bash", ""}, {"zsh", ""}, {"fish", ""}}, nil
+		}},
+		run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+			script, ok := completionScripts[args[0]]
+			if !ok {
+				return usageError("unsupported shell %q", args[0])
+			}
+			_, err := fmt.Fprint(a.stdout, script)
+			return err
+		},
+	}
+}
+
+// completeWords prints the completions of the last of words.
+func (a *app) completeWords(ctx context.Context, root *command, words []string) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur, prev := words[len(words)-1], words[:len(words)-1]
+
+	cmd := root
+	for cmd.subs != nil && len(prev) > 0 {
+		if cmd = find(cmd.subs, prev[0]); cmd == nil {
+			return
+		}
+		prev = prev[1:]
+	}
+	var candidates []string
+	switch {
+	case cmd.subs != nil:
+		for _, sub := range cmd.subs {
+			candidates = append(candidates, sub.name)
+		}
+	case strings.HasPrefix(cur, "-"):
+		a.flagSet(cmd).VisitAll(func(f *flag.Flag) {
+			candidates = append(candidates, "-"+f.Name)
+			if len(f.Name) > 1 {
+				candidates = append(candidates, "--"+f.Name)
+			}
+		})
+	default:
+		list := a.argLister(cmd, prev)
+		if list == nil {
+			return
+		}
+		c, err := a.api()
+		if err != nil && !cmd.local {
+			return
+		}
+		refs, _ := list(ctx, c)
+		for _, r := range refs {
+			candidates = append(candidates, r.id, r.name)
+		}
+	}
+
+	sort.Strings(candidates)
+	last := ""
+	for _, cand := range candidates {
+		if cand != "" && cand != last && strings.HasPrefix(cand, cur) {
+			fmt.Fprintln(a.stdout, cand)
+		}
+		last = cand
+	}
+}
+
+// argLister returns the lister of the argument that follows args on the
+// command line of cmd: a flag value or a positional argument. It also
+// applies the flags of args, such as --profile.
+func (a *app) argLister(cmd *command, args []string) lister {
+	fs := a.flagSet(cmd)
+	positional := 0
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' {
+			positional++
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		f := fs.Lookup(name)
+		if f == nil {
+			continue
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() && !hasValue {
+			value, hasValue = "true", true
+		}
+		if hasValue {
+			fs.Set(name, value)
+			continue
+		}
+		if i == len(args)-1 {
+			return cmd.completeFlags[name]
+		}
+		i++
+		fs.Set(name, args[i])
+	}
+	if positional < len(cmd.complete) {
+		return cmd.complete[positional]
+	}
+	if cmd.nargs < 0 && len(cmd.complete) > 0 {
+		return cmd.complete[len(cmd.complete)-1]
+	}
+	return nil
+}
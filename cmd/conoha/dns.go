@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+var domainColumns = []column[conoha.Domain]{
+	col("ID", func(d conoha.Domain) string { return d.UUID }),
+	col("NAME", func(d conoha.Domain) string { return d.Name }),
+	col("TTL", func(d conoha.Domain) string { return itoa(d.TTL) }),
+	col("EMAIL", func(d conoha.Domain) string { return d.Email }),
+}
+
+var recordColumns = []column[conoha.DNSRecord]{
+	col("ID", func(r conoha.DNSRecord) string { return r.UUID }),
+	col("NAME", func(r conoha.DNSRecord) string { return r.Name }),
+	col("TYPE", func(r conoha.DNSRecord) string { return r.Type }),
+	col("DATA", func(r conoha.DNSRecord) string { return r.Data }),
+	col("TTL", func(r conoha.DNSRecord) string { return itoa(r.TTL) }),
+	col("PRIORITY", func(r conoha.DNSRecord) string { return deref(r.Priority) }),
+}
+
+func dnsCommand() *command {
+	var domain conoha.CreateDomainRequest
+	var record conoha.CreateDNSRecordRequest
+	var priority int
+
+	return &command{
+		name:    "dns",
+		summary: "Manage DNS domains and records",
+		subs: []*command{
+			{
+				name: "list", summary: "List domains",
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					domains, err := c.ListAllDomains(ctx, nil)
+					if err != nil {
+						return err
+					}
+					return printList(a, domains, domainColumns...)
+				},
+			},
+			{
+				name: "show", args: "<domain>", summary: "Show a domain", nargs: 1,
+				complete: []lister{listDomains},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					d, err := c.ResolveDomain(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return printItem(a, *d, domainColumns...)
+				},
+			},
+			{
+				name: "create", args: "<name>", summary: "Create a domain, e.g. example.com.", nargs: 1,
+				flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&domain.Email, "email", "", "administrator `email` (required)")
+					fs.IntVar(&domain.TTL, "ttl", 3600, "default `TTL` of the records in seconds")
+				},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					if domain.Email == "" {
+						return usageError("--email is required")
+					}
+					domain.Name = args[0]
+					d, err := c.CreateDomain(ctx, domain)
+					if err != nil {
+						return err
+					}
+					return printItem(a, *d, domainColumns...)
+				},
+			},
+			{
+				name: "delete", args: "<domain>", summary: "Delete a domain and its records", nargs: 1,
+				complete: []lister{listDomains},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					d, err := c.ResolveDomain(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return c.DeleteDomain(ctx, d.UUID)
+				},
+			},
+			{
+				name: "records", args: "<domain>", summary: "List the records of a domain", nargs: 1,
+				complete: []lister{listDomains},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					d, err := c.ResolveDomain(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					records, err := c.ListAllDNSRecords(ctx, d.UUID, nil)
+					if err != nil {
+						return err
+					}
+					return printList(a, records, recordColumns...)
+				},
+			},
+			{
+				name: "record-add", args: "<domain>", summary: "Add a record to a domain", nargs: 1,
+				flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&record.Name, "name", "", "fully qualified record `name`, e.g. www.example.com. (required)")
+					fs.StringVar(&record.Type, "type", "A", "record `type`: A, AAAA, CNAME, MX, TXT, SRV, …")
+					fs.StringVar(&record.Data, "data", "", "record `data`, e.g. an address (required)")
+					fs.IntVar(&priority, "priority", -1, "`priority` of MX and SRV records")
+				},
+				complete: []lister{listDomains},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					if record.Name == "" || record.Data == "" {
+						return usageError("--name and --data are required")
+					}
+					d, err := c.ResolveDomain(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					if priority >= 0 {
+						record.Priority = &priority
+					}
+					r, err := c.CreateDNSRecord(ctx, d.UUID, record)
+					if err != nil {
+						return err
+					}
+					return printItem(a, *r, recordColumns...)
+				},
+			},
+			{
+				name: "record-delete", args: "<domain> <record-id>", summary: "Delete a record", nargs: 2,
+				complete: []lister{listDomains},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					d, err := c.ResolveDomain(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return c.DeleteDNSRecord(ctx, d.UUID, args[1])
+				},
+			},
+		},
+	}
+}
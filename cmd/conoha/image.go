@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strconv"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+var imageColumns = []column[conoha.Image]{
+	col("ID", func(img conoha.Image) string { return img.ID }),
+	col("NAME", func(img conoha.Image) string { return img.Name }),
+	col("STATUS", func(img conoha.Image) string { return img.Status }),
+	col("VISIBILITY", func(img conoha.Image) string { return img.Visibility }),
+	col("OS", func(img conoha.Image) string { return img.OSType }),
+	col("SIZE", func(img conoha.Image) string { return strconv.FormatInt(img.Size, 10) }),
+}
+
+func imageCommand() *command {
+	var list conoha.ListImagesOptions
+
+	return &command{
+		name:    "image",
+		summary: "Manage images",
+		subs: []*command{
+			{
+				name: "list", summary: "List images",
+				flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&list.Visibility, "visibility", "", "list only public or private (shared) images: `public|shared`")
+					fs.StringVar(&list.OSType, "os", "", "list only images of an OS type: `linux|windows`")
+					fs.StringVar(&list.Name, "name", "", "list only images with this `name`")
+				},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					images, err := c.ListAllImages(ctx, &list)
+					if err != nil {
+						return err
+					}
+					return printList(a, images, imageColumns...)
+				},
+			},
+			{
+				name: "show", args: "<image>", summary: "Show an image", nargs: 1,
+				complete: []lister{listImages},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					img, err := c.ResolveImage(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return printItem(a, *img, imageColumns...)
+				},
+			},
+			{
+				name: "delete", args: "<image>", summary: "Delete an image", nargs: 1, wait: true,
+				complete: []lister{listImages},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					img, err := c.ResolveImage(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					id := img.ID
+					if err := c.DeleteImage(ctx, id); err != nil {
+						return err
+					}
+					if a.wait {
+						return c.WaitForImageDeleted(ctx, id, a.waitOptions(id))
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
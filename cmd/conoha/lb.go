@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+var lbColumns = []column[conoha.LoadBalancer]{
+	col("ID", func(lb conoha.LoadBalancer) string { return lb.ID }),
+	col("NAME", func(lb conoha.LoadBalancer) string { return lb.Name }),
+	col("PROVISIONING", func(lb conoha.LoadBalancer) string { return lb.ProvisioningStatus }),
+	col("OPERATING", func(lb conoha.LoadBalancer) string { return lb.OperatingStatus }),
+	col("VIP", func(lb conoha.LoadBalancer) string { return lb.VIPAddress }),
+}
+
+func lbCommand() *command {
+	return &command{
+		name:    "lb",
+		summary: "Manage load balancers",
+		subs: []*command{
+			{
+				name: "list", summary: "List load balancers",
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					lbs, err := c.ListLoadBalancers(ctx)
+					if err != nil {
+						return err
+					}
+					return printList(a, lbs, lbColumns...)
+				},
+			},
+			{
+				name: "show", args: "<lb>", summary: "Show a load balancer", nargs: 1,
+				complete: []lister{listLoadBalancers},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					id, err := resolve(ctx, c, listLoadBalancers, getLoadBalancer, "load balancer", args[0])
+					if err != nil {
+						return err
+					}
+					lb, err := c.GetLoadBalancer(ctx, id)
+					if err != nil {
+						return err
+					}
+					return printItem(a, *lb, lbColumns...)
+				},
+			},
+			{
+				name: "create", args: "<name>", summary: "Create a load balancer", nargs: 1, wait: true,
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					lb, err := c.CreateLoadBalancer(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					if a.wait {
+						if lb, err = c.WaitForLoadBalancerActive(ctx, lb.ID, a.waitOptions(lb.ID)); err != nil {
+							return err
+						}
+					}
+					return printItem(a, *lb, lbColumns...)
+				},
+			},
+			{
+				name: "delete", args: "<lb>", summary: "Delete a load balancer", nargs: 1, wait: true,
+				complete: []lister{listLoadBalancers},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					id, err := resolve(ctx, c, listLoadBalancers, getLoadBalancer, "load balancer", args[0])
+					if err != nil {
+						return err
+					}
+					if err := c.DeleteLoadBalancer(ctx, id); err != nil {
+						return err
+					}
+					if a.wait {
+						return c.WaitForLoadBalancerDeleted(ctx, id, a.waitOptions(id))
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
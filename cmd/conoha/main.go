@@ -0,0 +1,341 @@
+// Command conoha manages ConoHa VPS v3 resources from the command line.
+//
+// Usage:
+//
+//	conoha <service> <command> [flags] [arguments]
+//
+// The services are server, volume, image, network, sg, lb, dns, object and
+// user; "conoha help <service>" lists their commands. Resources can be
+// named by ID or by name.
+//
+// Credentials come from the profile named by --profile or $CONOHA_PROFILE
+// in the config file (see conoha.LoadProfile), or else from the CONOHA_*
+// environment variables, or else from the "default" profile.
+//
+// Every command accepts -o table|json|yaml. Commands that start a
+// transition, such as "server create" or "server stop", accept --wait to
+// poll until it completes.
+//
+// Shell completion, which looks up resource names and IDs through the API,
+// is enabled with:
+//
+//	source <(conoha completion bash)   # or zsh
+//	conoha completion fish | source
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(newApp(os.Stdout, os.Stderr).run(ctx, os.Args[1:]))
+}
+
+// Exit codes.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// errUsage reports a command used with wrong arguments. The command's
+// usage is printed with it.
+var errUsage = errors.New("usage error")
+
+// usageError returns an error wrapping errUsage.
+func usageError(format string, args ...any) error {
+	return fmt.Errorf("%w: "+format, append([]any{errUsage}, args...)...)
+}
+
+// app is one invocation of the command.
+type app struct {
+	stdout, stderr io.Writer
+
+	// newClient creates the API client. Tests replace it.
+	newClient func(profile, region string) (*conoha.Client, error)
+	client    *conoha.Client
+
+	// Flags common to every command.
+	profile string
+	region  string
+	output  string
+	wait    bool
+	timeout time.Duration
+}
+
+func newApp(stdout, stderr io.Writer) *app {
+	return &app{stdout: stdout, stderr: stderr, newClient: clientFromEnvironment}
+}
+
+// clientFromEnvironment creates a client from a profile when one is named,
+// from the CONOHA_* variables when they hold credentials, and from the
+// default profile otherwise.
+func clientFromEnvironment(profile, region string) (*conoha.Client, error) {
+	var opts []conoha.ClientOption
+	if region != "" {
+		opts = append(opts, conoha.WithRegion(region))
+	}
+	if profile == "" && os.Getenv("CONOHA_PROFILE") == "" {
+		if cfg := conoha.ConfigFromEnv(); cfg.Password != "" || cfg.AccessKey != "" {
+			return conoha.NewClientFromConfig(cfg, opts...)
+		}
+	}
+	c, err := conoha.NewClientFromProfile(profile, opts...)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.New("no credentials: set CONOHA_USER_ID, CONOHA_PASSWORD and CONOHA_TENANT_ID, or create a profile")
+	}
+	return c, err
+}
+
+// api returns the client, creating it on first use.
+func (a *app) api() (*conoha.Client, error) {
+	if a.client == nil {
+		c, err := a.newClient(a.profile, a.region)
+		if err != nil {
+			return nil, err
+		}
+		a.client = c
+	}
+	return a.client, nil
+}
+
+// command is a node of the command tree: a service with subcommands or a
+// runnable command.
+type command struct {
+	name    string
+	args    string // synopsis of the positional arguments
+	summary string
+	subs    []*command
+
+	// flags registers the command's own flags.
+	flags func(fs *flag.FlagSet)
+	// wait adds --wait and --timeout.
+	wait bool
+	// local commands run without an API client.
+	local bool
+	// run runs the command with its positional arguments.
+	run func(ctx context.Context, a *app, c *conoha.Client, args []string) error
+	// nargs is the number of positional arguments run requires, or -1 to
+	// accept any number.
+	nargs int
+
+	// complete lists the candidates for each positional argument.
+	complete []lister
+	// completeFlags lists the candidates for flag values.
+	completeFlags map[string]lister
+}
+
+// commands returns the command tree.
+func commands() []*command {
+	return []*command{
+		serverCommand(),
+		volumeCommand(),
+		imageCommand(),
+		networkCommand(),
+		sgCommand(),
+		lbCommand(),
+		dnsCommand(),
+		objectCommand(),
+		userCommand(),
+		completionCommand(),
+	}
+}
+
+// find returns the subcommand named name, or nil.
+func find(cmds []*command, name string) *command {
+	for _, c := range cmds {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// run runs the command line args and returns the exit code.
+func (a *app) run(ctx context.Context, args []string) int {
+	root := &command{name: "conoha", summary: "conoha manages ConoHa VPS v3 resources.", subs: commands()}
+	if len(args) > 0 && args[0] == completeCommand {
+		a.completeWords(ctx, root, args[1:])
+		return exitOK
+	}
+
+	path := []*command{root}
+	cmd := root
+	for cmd.subs != nil {
+		if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+			a.usage(a.stdout, path)
+			return exitOK
+		}
+		if args[0] == "help" {
+			for args = args[1:]; len(args) > 0 && cmd.subs != nil; args = args[1:] {
+				if cmd = find(cmd.subs, args[0]); cmd == nil {
+					break
+				}
+				path = append(path, cmd)
+			}
+			a.usage(a.stdout, path)
+			return exitOK
+		}
+		next := find(cmd.subs, args[0])
+		if next == nil {
+			fmt.Fprintf(a.stderr, "conoha: unknown command %q\n\n", strings.TrimSpace(commandPath(path)+" "+args[0]))
+			a.usage(a.stderr, path)
+			return exitUsage
+		}
+		cmd, args = next, args[1:]
+		path = append(path, cmd)
+	}
+
+	fs := a.flagSet(cmd)
+	fs.SetOutput(io.Discard)
+	pos, err := parseInterspersed(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		a.usage(a.stdout, path)
+		return exitOK
+	}
+	if err == nil && cmd.nargs >= 0 && len(pos) != cmd.nargs {
+		err = usageError("%s takes %d argument(s), got %d", commandPath(path), cmd.nargs, len(pos))
+	}
+	if err == nil {
+		err = a.checkOutput()
+	}
+	if err != nil && !errors.Is(err, errUsage) {
+		err = usageError("%v", err)
+	}
+	if err != nil {
+		return a.fail(path, err)
+	}
+
+	var client *conoha.Client
+	if !cmd.local {
+		if client, err = a.api(); err != nil {
+			return a.fail(path, err)
+		}
+	}
+	return a.fail(path, cmd.run(ctx, a, client, pos))
+}
+
+// fail reports err, if any, and returns the exit code for it. Usage errors
+// are followed by the usage of the command.
+func (a *app) fail(path []*command, err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(a.stderr, "conoha: %s\n\n", strings.TrimPrefix(err.Error(), errUsage.Error()+": "))
+		a.usage(a.stderr, path)
+		return exitUsage
+	}
+	fmt.Fprintf(a.stderr, "conoha: %v\n", err)
+	return exitError
+}
+
+// flagSet returns the flags of cmd, including the common ones.
+func (a *app) flagSet(cmd *command) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.StringVar(&a.profile, "profile", "", "`name` of the config file profile to use")
+	fs.StringVar(&a.region, "region", "", "`region` to use instead of the profile's, e.g. c3j1")
+	fs.StringVar(&a.output, "o", "table", "output `format`: table, json or yaml")
+	if cmd.wait {
+		fs.BoolVar(&a.wait, "wait", false, "wait until the operation completes")
+		fs.DurationVar(&a.timeout, "timeout", 15*time.Minute, "maximum time to wait with --wait")
+	}
+	if cmd.flags != nil {
+		cmd.flags(fs)
+	}
+	return fs
+}
+
+// parseInterspersed parses flags placed anywhere among the positional
+// arguments and returns the positional arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return pos, nil
+		}
+		pos = append(pos, args[0])
+		args = args[1:]
+	}
+}
+
+// usage prints the help of the last command of path.
+func (a *app) usage(w io.Writer, path []*command) {
+	cmd := path[len(path)-1]
+	name := commandPath(path)
+	if cmd.subs != nil {
+		if cmd.summary != "" {
+			fmt.Fprintf(w, "%s\n\n", cmd.summary)
+		}
+		fmt.Fprintf(w, "Usage:\n  %s <command> [flags] [arguments]\n\nCommands:\n", name)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, sub := range cmd.subs {
+			fmt.Fprintf(tw, "  %s\t%s\n", sub.name, sub.summary)
+		}
+		tw.Flush()
+		fmt.Fprintf(w, "\nRun \"%s help <command>\" for more information.\n", name)
+		return
+	}
+	fmt.Fprintf(w, "%s\n\nUsage:\n  %s [flags]", cmd.summary, name)
+	if cmd.args != "" {
+		fmt.Fprintf(w, " %s", cmd.args)
+	}
+	fmt.Fprint(w, "\n\nFlags:\n")
+	fs := a.flagSet(cmd)
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
+
+// commandPath returns the command line that selects the last command of
+// path, e.g. "conoha server list".
+func commandPath(path []*command) string {
+	names := make([]string, len(path))
+	for i, c := range path {
+		names[i] = c.name
+	}
+	return strings.Join(names, " ")
+}
+
+// waitOptions returns the options of the waiters used by --wait. Progress
+// is reported on stderr.
+func (a *app) waitOptions(what string) *conoha.WaitOptions {
+	last := ""
+	return &conoha.WaitOptions{
+		Timeout: a.timeout,
+		Progress: func(status string) {
+			if status != last {
+				fmt.Fprintf(a.stderr, "%s: %s\n", what, status)
+				last = status
+			}
+		},
+	}
+}
+
+// stringList is a flag that can be given several times.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
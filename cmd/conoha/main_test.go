@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+	"github.com/leonunix/conohav3-golang-sdk/conohatest"
+)
+
+// cli runs the command against an in-memory ConoHa API.
+type cli struct {
+	t      *testing.T
+	client *conoha.Client
+}
+
+func newCLI(t *testing.T) *cli {
+	srv := conohatest.NewServer()
+	t.Cleanup(srv.Close)
+	return &cli{t: t, client: srv.NewClient()}
+}
+
+// run runs args and returns the output and exit code.
+func (c *cli) run(args ...string) (stdout, stderr string, code int) {
+	c.t.Helper()
+	var out, errOut bytes.Buffer
+	a := newApp(&out, &errOut)
+	a.newClient = func(profile, region string) (*conoha.Client, error) {
+		return c.client, nil
+	}
+	code = a.run(context.Background(), args)
+	return out.String(), errOut.String(), code
+}
+
+// ok runs args, fails the test unless they succeed and returns stdout.
+func (c *cli) ok(args ...string) string {
+	c.t.Helper()
+	stdout, stderr, code := c.run(args...)
+	if code != exitOK {
+		c.t.Fatalf("conoha %s: exit %d: %s", strings.Join(args, " "), code, stderr)
+	}
+	return stdout
+}
+
+func TestServerLifecycle(t *testing.T) {
+	c := newCLI(t)
+
+	var vol conoha.Volume
+	out := c.ok("volume", "create", "--size", "100", "--type", conohatest.BootVolumeType,
+		"--image", "vmi-ubuntu-24.04-amd64", "--name", "boot", "--wait", "-o", "json")
+	if err := json.Unmarshal([]byte(out), &vol); err != nil {
+		t.Fatalf("volume create output %q: %v", out, err)
+	}
+	if vol.Status != "available" {
+		t.Errorf("volume status = %q, want available", vol.Status)
+	}
+
+	out = c.ok("server", "create", "--flavor", "g2l-t-c2m1", "--volume", "boot", "--name", "web", "--wait")
+	if !strings.Contains(out, "ACTIVE") || !strings.Contains(out, "web") {
+		t.Errorf("server create output:\n%s", out)
+	}
+	out = c.ok("server", "list")
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[1], "web") {
+		t.Errorf("server list output:\n%s", out)
+	}
+
+	if out := c.ok("server", "stop", "web", "--wait"); !strings.Contains(out, "SHUTOFF") {
+		t.Errorf("server stop output:\n%s", out)
+	}
+	var s conoha.ServerDetail
+	out = c.ok("server", "resize", "--flavor", "g2l-t-c3m2", "web", "--wait", "-o", "json")
+	if err := json.Unmarshal([]byte(out), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Status != "ACTIVE" || s.Flavor.ID == conohatest.FlavorID {
+		t.Errorf("after resize: status %s, flavor %s", s.Status, s.Flavor.ID)
+	}
+
+	c.ok("server", "delete", "web", "--wait")
+	if out := c.ok("server", "list", "-o", "json"); strings.TrimSpace(out) != "[]" {
+		t.Errorf("server list after delete = %s", out)
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	c := newCLI(t)
+	c.ok("dns", "create", "example.com.", "--email", "admin@example.com")
+
+	out := c.ok("dns", "show", "example.com.", "-o", "yaml")
+	for _, want := range []string{"name: example.com.\n", "email: admin@example.com\n", "ttl: 3600\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("yaml output does not contain %q:\n%s", want, out)
+		}
+	}
+	out = c.ok("dns", "show", "example.com.")
+	if !strings.Contains(out, "NAME:") || !strings.Contains(out, "example.com.") {
+		t.Errorf("table output:\n%s", out)
+	}
+
+	c.ok("dns", "record-add", "example.com.", "--name", "www.example.com.", "--data", "192.0.2.1")
+	var records []conoha.DNSRecord
+	if err := json.Unmarshal([]byte(c.ok("dns", "records", "example.com.", "-o", "json")), &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Data != "192.0.2.1" || records[0].Type != "A" {
+		t.Errorf("records = %+v", records)
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	v := map[string]any{
+		"name":  "web",
+		"count": 2,
+		"empty": []string{},
+		"tags":  []string{"a", "true", ""},
+		"addresses": map[string]any{
+			"net": []map[string]any{{"addr": "192.0.2.1", "version": 4}},
+		},
+		"note": "key: value",
+		"nil":  nil,
+	}
+	var buf bytes.Buffer
+	if err := writeYAML(&buf, v); err != nil {
+		t.Fatal(err)
+	}
+	want := `addresses:
+  net:
+    - addr: 192.0.2.1
+      version: 4
+count: 2
+empty: []
+name: web
+nil: null
+note: "key: value"
+tags:
+  - a
+  - "true"
+  - ""
+`
+	if buf.String() != want {
+		t.Errorf("writeYAML =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	c := newCLI(t)
+	c.ok("sg", "create", "web-sg")
+
+	for _, tc := range []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"se"}, []string{"server"}},
+		{[]string{"server", "st"}, []string{"start", "stop"}},
+		{[]string{"server", "create", "--fl"}, []string{"--flavor"}},
+		{[]string{"server", "create", "--flavor", "g2l-t-c4"}, []string{"g2l-t-c4m4"}},
+		{[]string{"server", "create", "--wait", "--sg", "web"}, []string{"web-sg"}},
+		{[]string{"sg", "delete", "web"}, []string{"web-sg"}},
+		{[]string{"completion", "z"}, []string{"zsh"}},
+	} {
+		out := c.ok(append([]string{completeCommand}, tc.args...)...)
+		if got := strings.Fields(out); strings.Join(got, " ") != strings.Join(tc.want, " ") {
+			t.Errorf("complete %q = %q, want %q", tc.args, got, tc.want)
+		}
+	}
+
+	// Positional arguments complete to names and IDs.
+	out := c.ok(completeCommand, "sg", "show", "")
+	if !strings.Contains(out, "web-sg\n") || len(strings.Fields(out)) != 2 {
+		t.Errorf("sg show completions:\n%s", out)
+	}
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		if out := c.ok("completion", shell); !strings.Contains(out, completeCommand) {
+			t.Errorf("%s script:\n%s", shell, out)
+		}
+	}
+}
+
+func TestObject(t *testing.T) {
+	c := newCLI(t)
+	file := filepath.Join(t.TempDir(), "hello.txt")
+	if err := os.WriteFile(file, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c.ok("object", "create", "docs")
+	c.ok("object", "put", "docs", file)
+	if out := c.ok("object", "list", "docs"); !strings.Contains(out, "hello.txt") {
+		t.Errorf("object list:\n%s", out)
+	}
+	if out := c.ok("object", "get", "docs", "hello.txt"); out != "hello" {
+		t.Errorf("object get = %q", out)
+	}
+	if _, stderr, code := c.run("object", "delete", "docs"); code != exitError || !strings.Contains(stderr, "409") {
+		t.Errorf("deleting a non-empty container: exit %d: %s", code, stderr)
+	}
+	c.ok("object", "delete", "docs", "hello.txt")
+	c.ok("object", "delete", "docs")
+}
+
+func TestAmbiguousName(t *testing.T) {
+	c := newCLI(t)
+	c.ok("volume", "create", "--size", "100", "--name", "data")
+	c.ok("volume", "create", "--size", "100", "--name", "data")
+
+	_, stderr, code := c.run("volume", "show", "data")
+	if code != exitError || !strings.Contains(stderr, `2 volumes are named "data"`) {
+		t.Errorf("exit %d: %s", code, stderr)
+	}
+	_, stderr, code = c.run("volume", "show", "nope")
+	if code != exitError || !strings.Contains(stderr, `no volume has the name or ID "nope"`) {
+		t.Errorf("unknown name: exit %d: %s", code, stderr)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	c := newCLI(t)
+	for _, args := range [][]string{
+		{"nope"},
+		{"server", "show"},
+		{"server", "list", "-o", "xml"},
+		{"server", "list", "--bogus"},
+		{"volume", "create"},
+	} {
+		if _, stderr, code := c.run(args...); code != exitUsage || !strings.Contains(stderr, "Usage:") {
+			t.Errorf("conoha %s: exit %d, want %d with usage:\n%s", strings.Join(args, " "), code, exitUsage, stderr)
+		}
+	}
+
+	for _, args := range [][]string{{}, {"help"}, {"help", "server", "list"}, {"server", "list", "-h"}} {
+		if out, _, code := c.run(args...); code != exitOK || !strings.Contains(out, "Usage:") {
+			t.Errorf("conoha %s: exit %d:\n%s", strings.Join(args, " "), code, out)
+		}
+	}
+}
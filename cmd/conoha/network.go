@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strconv"
+	"strings"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+var networkColumns = []column[conoha.Network]{
+	col("ID", func(n conoha.Network) string { return n.ID }),
+	col("NAME", func(n conoha.Network) string { return n.Name }),
+	col("STATUS", func(n conoha.Network) string { return n.Status }),
+	col("SUBNETS", func(n conoha.Network) string { return strings.Join(n.Subnets, ",") }),
+}
+
+func networkCommand() *command {
+	return &command{
+		name:    "network",
+		summary: "Manage private networks",
+		subs: []*command{
+			{
+				name: "list", summary: "List networks",
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					networks, err := c.ListNetworks(ctx, nil)
+					if err != nil {
+						return err
+					}
+					return printList(a, networks, networkColumns...)
+				},
+			},
+			{
+				name: "show", args: "<network>", summary: "Show a network", nargs: 1,
+				complete: []lister{listNetworks},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					n, err := c.ResolveNetwork(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return printItem(a, *n, networkColumns...)
+				},
+			},
+			{
+				name: "create", summary: "Create a private network",
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					n, err := c.CreateNetwork(ctx)
+					if err != nil {
+						return err
+					}
+					return printItem(a, *n, networkColumns...)
+				},
+			},
+			{
+				name: "delete", args: "<network>", summary: "Delete a network", nargs: 1,
+				complete: []lister{listNetworks},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					n, err := c.ResolveNetwork(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return c.DeleteNetwork(ctx, n.ID)
+				},
+			},
+		},
+	}
+}
+
+var securityGroupColumns = []column[conoha.SecurityGroup]{
+	col("ID", func(sg conoha.SecurityGroup) string { return sg.ID }),
+	col("NAME", func(sg conoha.SecurityGroup) string { return sg.Name }),
+	col("DESCRIPTION", func(sg conoha.SecurityGroup) string { return sg.Description }),
+	col("RULES", func(sg conoha.SecurityGroup) string { return itoa(len(sg.Rules)) }),
+}
+
+var ruleColumns = []column[conoha.SecurityGroupRule]{
+	col("ID", func(r conoha.SecurityGroupRule) string { return r.ID }),
+	col("DIRECTION", func(r conoha.SecurityGroupRule) string { return r.Direction }),
+	col("ETHERTYPE", func(r conoha.SecurityGroupRule) string { return r.EtherType }),
+	col("PROTOCOL", func(r conoha.SecurityGroupRule) string { return deref(r.Protocol) }),
+	col("PORTS", func(r conoha.SecurityGroupRule) string {
+		if r.PortRangeMin == nil {
+			return ""
+		}
+		if r.PortRangeMax == nil || *r.PortRangeMax == *r.PortRangeMin {
+			return itoa(*r.PortRangeMin)
+		}
+		return itoa(*r.PortRangeMin) + "-" + itoa(*r.PortRangeMax)
+	}),
+	col("REMOTE", func(r conoha.SecurityGroupRule) string {
+		if r.RemoteGroupID != nil {
+			return *r.RemoteGroupID
+		}
+		return deref(r.RemoteIPPrefix)
+	}),
+}
+
+func sgCommand() *command {
+	var description string
+	var rule struct {
+		direction, ethertype, protocol, ports, remoteIP, remoteGroup string
+	}
+
+	return &command{
+		name:    "sg",
+		summary: "Manage security groups and their rules",
+		subs: []*command{
+			{
+				name: "list", summary: "List security groups",
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					groups, err := c.ListSecurityGroups(ctx, nil)
+					if err != nil {
+						return err
+					}
+					return printList(a, groups, securityGroupColumns...)
+				},
+			},
+			{
+				name: "show", args: "<group>", summary: "Show a security group and its rules", nargs: 1,
+				complete: []lister{listSecurityGroups},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					sg, err := c.ResolveSecurityGroup(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					if a.output != "table" {
+						return a.encode(sg)
+					}
+					if err := printItem(a, *sg, securityGroupColumns...); err != nil {
+						return err
+					}
+					a.stdout.Write([]byte("\n"))
+					return printList(a, sg.Rules, ruleColumns...)
+				},
+			},
+			{
+				name: "create", args: "<name>", summary: "Create a security group", nargs: 1,
+				flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&description, "description", "", "group `description`")
+				},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					sg, err := c.CreateSecurityGroup(ctx, args[0], description)
+					if err != nil {
+						return err
+					}
+					return printItem(a, *sg, securityGroupColumns...)
+				},
+			},
+			{
+				name: "delete", args: "<group>", summary: "Delete a security group", nargs: 1,
+				complete: []lister{listSecurityGroups},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					sg, err := c.ResolveSecurityGroup(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return c.DeleteSecurityGroup(ctx, sg.ID)
+				},
+			},
+			{
+				name: "rule-add", args: "<group>", summary: "Add a rule to a security group", nargs: 1,
+				flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&rule.direction, "direction", "ingress", "`ingress|egress`")
+					fs.StringVar(&rule.ethertype, "ethertype", "IPv4", "`IPv4|IPv6`")
+					fs.StringVar(&rule.protocol, "protocol", "", "`protocol`: tcp, udp or icmp; any if empty")
+					fs.StringVar(&rule.ports, "port", "", "`port` or range, e.g. 22 or 8000-8080")
+					fs.StringVar(&rule.remoteIP, "remote-ip", "", "allowed `CIDR`, e.g. 0.0.0.0/0")
+					fs.StringVar(&rule.remoteGroup, "remote-group", "", "allowed security `group` name or ID")
+				},
+				complete:      []lister{listSecurityGroups},
+				completeFlags: map[string]lister{"remote-group": listSecurityGroups},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					sg, err := c.ResolveSecurityGroup(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					req := conoha.CreateSecurityGroupRuleRequest{
+						SecurityGroupID: sg.ID,
+						Direction:       rule.direction,
+						EtherType:       rule.ethertype,
+					}
+					if rule.protocol != "" {
+						req.Protocol = &rule.protocol
+					}
+					if rule.ports != "" {
+						lo, hi, _ := strings.Cut(rule.ports, "-")
+						if hi == "" {
+							hi = lo
+						}
+						min, err1 := strconv.Atoi(lo)
+						max, err2 := strconv.Atoi(hi)
+						if err1 != nil || err2 != nil {
+							return usageError("invalid --port %q", rule.ports)
+						}
+						req.PortRangeMin, req.PortRangeMax = &min, &max
+					}
+					if rule.remoteIP != "" {
+						req.RemoteIPPrefix = &rule.remoteIP
+					}
+					if rule.remoteGroup != "" {
+						remote, err := c.ResolveSecurityGroup(ctx, rule.remoteGroup)
+						if err != nil {
+							return err
+						}
+						req.RemoteGroupID = &remote.ID
+					}
+					r, err := c.CreateSecurityGroupRule(ctx, req)
+					if err != nil {
+						return err
+					}
+					return printItem(a, *r, ruleColumns...)
+				},
+			},
+			{
+				name: "rule-delete", args: "<rule-id>", summary: "Delete a security group rule", nargs: 1,
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					return c.DeleteSecurityGroupRule(ctx, args[0])
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+func objectCommand() *command {
+	var prefix, objectName, outFile string
+
+	return &command{
+		name:    "object",
+		summary: "Manage object storage containers and objects",
+		subs: []*command{
+			{
+				name: "list", args: "[<container>]", nargs: -1,
+				summary: "List containers, or the objects of a container",
+				flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&prefix, "prefix", "", "list only objects whose name starts with `prefix`")
+				},
+				complete: []lister{listContainers},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					switch len(args) {
+					case 0:
+						containers, err := c.ListContainers(ctx)
+						if err != nil {
+							return err
+						}
+						return printList(a, containers,
+							col("NAME", func(ct conoha.Container) string { return ct.Name }),
+							col("OBJECTS", func(ct conoha.Container) string { return itoa(ct.Count) }),
+							col("BYTES", func(ct conoha.Container) string { return strconv.FormatInt(ct.Bytes, 10) }))
+					case 1:
+						objects, err := c.ListAllObjects(ctx, args[0], &conoha.ListObjectsOptions{Prefix: prefix})
+						if err != nil {
+							return err
+						}
+						return printList(a, objects,
+							col("NAME", func(o conoha.Object) string { return o.Name }),
+							col("BYTES", func(o conoha.Object) string { return strconv.FormatInt(o.Bytes, 10) }),
+							col("TYPE", func(o conoha.Object) string { return o.ContentType }),
+							col("MODIFIED", func(o conoha.Object) string { return o.LastModified }))
+					}
+					return usageError("object list takes at most one container")
+				},
+			},
+			{
+				name: "create", args: "<container>", summary: "Create a container", nargs: 1,
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					return c.CreateContainer(ctx, args[0])
+				},
+			},
+			{
+				name: "put", args: "<container> <file>", summary: "Upload a file; - reads standard input", nargs: 2,
+				flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&objectName, "name", "", "object `name`; defaults to the base name of the file")
+				},
+				complete: []lister{listContainers},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					name := objectName
+					var r io.Reader = os.Stdin
+					if args[1] != "-" {
+						f, err := os.Open(args[1])
+						if err != nil {
+							return err
+						}
+						defer f.Close()
+						r = f
+						if name == "" {
+							name = filepath.Base(args[1])
+						}
+					}
+					if name == "" {
+						return usageError("--name is required to upload standard input")
+					}
+					return c.UploadObject(ctx, args[0], name, r)
+				},
+			},
+			{
+				name: "get", args: "<container> <object>", summary: "Download an object", nargs: 2,
+				flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&outFile, "file", "-", "`file` to write; - writes standard output")
+				},
+				complete: []lister{listContainers},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					body, err := c.DownloadObject(ctx, args[0], args[1])
+					if err != nil {
+						return err
+					}
+					defer body.Close()
+					if outFile == "-" {
+						_, err = io.Copy(a.stdout, body)
+						return err
+					}
+					f, err := os.Create(outFile)
+					if err != nil {
+						return err
+					}
+					if _, err := io.Copy(f, body); err != nil {
+						f.Close()
+						return err
+					}
+					return f.Close()
+				},
+			},
+			{
+				name: "delete", args: "<container> [<object>]", nargs: -1,
+				summary:  "Delete an object, or an empty container",
+				complete: []lister{listContainers},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					switch len(args) {
+					case 1:
+						return c.DeleteContainer(ctx, args[0])
+					case 2:
+						return c.DeleteObject(ctx, args[0], args[1])
+					}
+					return usageError("object delete takes a container and an optional object")
+				},
+			},
+		},
+	}
+}
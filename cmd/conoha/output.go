@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// column is a column of table output.
+type column[T any] struct {
+	name  string
+	value func(T) string
+}
+
+func col[T any](name string, value func(T) string) column[T] {
+	return column[T]{name, value}
+}
+
+// checkOutput validates the -o flag.
+func (a *app) checkOutput() error {
+	switch a.output {
+	case "table", "json", "yaml":
+		return nil
+	}
+	return usageError("unknown output format %q: want table, json or yaml", a.output)
+}
+
+// printList prints items as a table with one row per item, or as a JSON
+// or YAML list.
+func printList[T any](a *app, items []T, cols ...column[T]) error {
+	if items == nil {
+		items = []T{}
+	}
+	if a.output != "table" {
+		return a.encode(items)
+	}
+	tw := tabwriter.NewWriter(a.stdout, 0, 0, 3, ' ', 0)
+	for i, c := range cols {
+		if i > 0 {
+			fmt.Fprint(tw, "\t")
+		}
+		fmt.Fprint(tw, c.name)
+	}
+	fmt.Fprintln(tw)
+	for _, item := range items {
+		for i, c := range cols {
+			if i > 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, c.value(item))
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// printItem prints item as a table with one row per column, or as a JSON
+// or YAML object.
+func printItem[T any](a *app, item T, cols ...column[T]) error {
+	if a.output != "table" {
+		return a.encode(item)
+	}
+	tw := tabwriter.NewWriter(a.stdout, 0, 0, 3, ' ', 0)
+	for _, c := range cols {
+		fmt.Fprintf(tw, "%s:\t%s\n", c.name, c.value(item))
+	}
+	return tw.Flush()
+}
+
+// encode prints v in the JSON or YAML output format.
+func (a *app) encode(v any) error {
+	if a.output == "yaml" {
+		return writeYAML(a.stdout, v)
+	}
+	enc := json.NewEncoder(a.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeYAML writes v, which must be JSON-encodable, as a YAML document.
+// Object keys are sorted.
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return err
+	}
+	var b strings.Builder
+	for _, line := range yamlLines(generic) {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// yamlLines returns the block-style YAML lines of a decoded JSON value.
+func yamlLines(v any) []string {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			return []string{"{}"}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var lines []string
+		for _, k := range keys {
+			lines = append(lines, nested(yamlScalar(k)+":", v[k], "  ")...)
+		}
+		return lines
+	case []any:
+		if len(v) == 0 {
+			return []string{"[]"}
+		}
+		var lines []string
+		for _, item := range v {
+			sub := yamlLines(item)
+			lines = append(lines, "- "+sub[0])
+			for _, l := range sub[1:] {
+				lines = append(lines, "  "+l)
+			}
+		}
+		return lines
+	}
+	return []string{yamlScalar(v)}
+}
+
+// nested returns the lines of a mapping entry: on one line for scalars and
+// empty collections, and indented under the key otherwise.
+func nested(key string, v any, indent string) []string {
+	sub := yamlLines(v)
+	if !isCollection(v) || sub[0] == "{}" || sub[0] == "[]" {
+		return []string{key + " " + sub[0]}
+	}
+	lines := []string{key}
+	for _, l := range sub {
+		lines = append(lines, indent+l)
+	}
+	return lines
+}
+
+func isCollection(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+// yamlScalar formats a scalar, quoting strings that YAML would read as
+// something else.
+func yamlScalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		if needsQuotes(v) {
+			return strconv.Quote(v)
+		}
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+func needsQuotes(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// Table cell helpers.
+
+func deref[T any](p *T) string {
+	if p == nil {
+		return ""
+	}
+	return fmt.Sprint(*p)
+}
+
+func itoa(n int) string {
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+// ref names a resource for completion and for name lookup. A resource can
+// have several refs, e.g. a server by its name tag and by its API name.
+type ref struct {
+	id, name string
+}
+
+// lister lists the resources an argument can name.
+type lister func(ctx context.Context, c *conoha.Client) ([]ref, error)
+
+// getter gets a resource by ID, to check that it exists.
+type getter func(ctx context.Context, c *conoha.Client, id string) error
+
+// idPattern matches resource IDs: UUIDs, with or without dashes.
+var idPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`)
+
+// resolve returns the ID of the resource of the given kind that arg names
+// by ID or by name, like the Resolve* methods of the SDK do for the kinds
+// they cover: an ID is looked up with get before the listing is searched,
+// a name that matches nothing is an error matching conoha.ErrNotFound, and
+// one that matches several a *conoha.AmbiguousNameError.
+func resolve(ctx context.Context, c *conoha.Client, list lister, get getter, kind, arg string) (string, error) {
+	if idPattern.MatchString(arg) {
+		err := get(ctx, c, arg)
+		if err == nil {
+			return arg, nil
+		}
+		if !errors.Is(err, conoha.ErrNotFound) {
+			return "", err
+		}
+	}
+	refs, err := list(ctx, c)
+	if err != nil {
+		return "", fmt.Errorf("looking up %s %q: %w", kind, arg, err)
+	}
+	var ids []string
+	seen := make(map[string]bool)
+	for _, r := range refs {
+		if r.id == arg {
+			return arg, nil
+		}
+		if r.name == arg && !seen[r.id] {
+			seen[r.id] = true
+			ids = append(ids, r.id)
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("%w: no %s has the name or ID %q", conoha.ErrNotFound, kind, arg)
+	case 1:
+		return ids[0], nil
+	}
+	return "", &conoha.AmbiguousNameError{Resource: kind, Name: arg, IDs: ids}
+}
+
+func listServers(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	servers, err := c.ListAllServersDetail(ctx, nil)
+	var refs []ref
+	for _, s := range servers {
+		if tag := s.Metadata["instance_name_tag"]; tag != "" {
+			refs = append(refs, ref{s.ID, tag})
+		}
+		refs = append(refs, ref{s.ID, s.Name})
+	}
+	return refs, err
+}
+
+func listFlavors(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	flavors, err := c.ListFlavors(ctx)
+	var refs []ref
+	for _, f := range flavors {
+		refs = append(refs, ref{f.ID, f.Name})
+	}
+	return refs, err
+}
+
+func listImages(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	images, err := c.ListAllImages(ctx, nil)
+	var refs []ref
+	for _, img := range images {
+		refs = append(refs, ref{img.ID, img.Name})
+	}
+	return refs, err
+}
+
+func listVolumes(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	volumes, err := c.ListAllVolumes(ctx, nil)
+	var refs []ref
+	for _, v := range volumes {
+		refs = append(refs, ref{v.ID, v.Name})
+	}
+	return refs, err
+}
+
+func listVolumeTypes(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	types, err := c.ListVolumeTypes(ctx)
+	var refs []ref
+	for _, t := range types {
+		refs = append(refs, ref{t.Name, t.Name})
+	}
+	return refs, err
+}
+
+func listKeypairs(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	keypairs, err := c.ListAllKeypairs(ctx, nil)
+	var refs []ref
+	for _, kp := range keypairs {
+		refs = append(refs, ref{kp.Name, kp.Name})
+	}
+	return refs, err
+}
+
+func listNetworks(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	networks, err := c.ListNetworks(ctx, nil)
+	var refs []ref
+	for _, n := range networks {
+		refs = append(refs, ref{n.ID, n.Name})
+	}
+	return refs, err
+}
+
+func listSecurityGroups(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	groups, err := c.ListSecurityGroups(ctx, nil)
+	var refs []ref
+	for _, sg := range groups {
+		refs = append(refs, ref{sg.ID, sg.Name})
+	}
+	return refs, err
+}
+
+// listSecurityGroupNames lists security groups by name only, for the
+// server options that take names.
+func listSecurityGroupNames(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	groups, err := c.ListSecurityGroups(ctx, nil)
+	var refs []ref
+	for _, sg := range groups {
+		refs = append(refs, ref{sg.Name, sg.Name})
+	}
+	return refs, err
+}
+
+func listLoadBalancers(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	lbs, err := c.ListLoadBalancers(ctx)
+	var refs []ref
+	for _, lb := range lbs {
+		refs = append(refs, ref{lb.ID, lb.Name})
+	}
+	return refs, err
+}
+
+func listDomains(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	domains, err := c.ListAllDomains(ctx, nil)
+	var refs []ref
+	for _, d := range domains {
+		refs = append(refs, ref{d.UUID, d.Name})
+	}
+	return refs, err
+}
+
+func listContainers(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	containers, err := c.ListContainers(ctx)
+	var refs []ref
+	for _, ct := range containers {
+		refs = append(refs, ref{ct.Name, ct.Name})
+	}
+	return refs, err
+}
+
+func listSubUsers(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	users, err := c.ListSubUsers(ctx)
+	var refs []ref
+	for _, u := range users {
+		refs = append(refs, ref{u.ID, u.Name})
+	}
+	return refs, err
+}
+
+func listRoles(ctx context.Context, c *conoha.Client) ([]ref, error) {
+	roles, err := c.ListRoles(ctx)
+	var refs []ref
+	for _, r := range roles {
+		refs = append(refs, ref{r.ID, r.Name})
+	}
+	return refs, err
+}
+
+func getLoadBalancer(ctx context.Context, c *conoha.Client, id string) error {
+	_, err := c.GetLoadBalancer(ctx, id)
+	return err
+}
+
+func getSubUser(ctx context.Context, c *conoha.Client, id string) error {
+	_, err := c.GetSubUser(ctx, id)
+	return err
+}
+
+func getRole(ctx context.Context, c *conoha.Client, id string) error {
+	_, err := c.GetRole(ctx, id)
+	return err
+}
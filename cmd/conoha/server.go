@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+var serverColumns = []column[conoha.ServerDetail]{
+	col("ID", func(s conoha.ServerDetail) string { return s.ID }),
+	col("NAME", serverName),
+	col("STATUS", func(s conoha.ServerDetail) string { return s.Status }),
+	col("FLAVOR", func(s conoha.ServerDetail) string { return s.Flavor.ID }),
+	col("ADDRESSES", serverAddresses),
+}
+
+var serverDetailColumns = append(serverColumns[:len(serverColumns):len(serverColumns)],
+	col("KEY", func(s conoha.ServerDetail) string { return deref(s.KeyName) }),
+	col("SECURITY GROUPS", func(s conoha.ServerDetail) string {
+		var names []string
+		for _, sg := range s.SecurityGroups {
+			names = append(names, sg.Name)
+		}
+		return strings.Join(names, ",")
+	}),
+	col("CREATED", func(s conoha.ServerDetail) string { return s.Created }),
+)
+
+// serverName returns the name tag shown in the control panel, or the API
+// name of servers without one.
+func serverName(s conoha.ServerDetail) string {
+	if tag := s.Metadata["instance_name_tag"]; tag != "" {
+		return tag
+	}
+	return s.Name
+}
+
+// serverAddresses returns the IPv4 addresses of s.
+func serverAddresses(s conoha.ServerDetail) string {
+	var addrs []string
+	for _, list := range s.Addresses {
+		for _, addr := range list {
+			if addr.Version == 4 {
+				addrs = append(addrs, addr.Addr)
+			}
+		}
+	}
+	sort.Strings(addrs)
+	return strings.Join(addrs, ",")
+}
+
+func serverCommand() *command {
+	var create struct {
+		flavor, volume, name, key, adminPass, userData string
+		securityGroups                                 stringList
+	}
+	var resizeFlavor string
+
+	// action returns a command that runs a server action and waits for
+	// status with --wait.
+	action := func(name, summary, status string, do func(c *conoha.Client, ctx context.Context, id string) error) *command {
+		return &command{
+			name: name, args: "<server>", summary: summary, nargs: 1, wait: true,
+			complete: []lister{listServers},
+			run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+				server, err := c.ResolveServer(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				id := server.ID
+				if err := do(c, ctx, id); err != nil {
+					return err
+				}
+				if !a.wait {
+					return nil
+				}
+				s, err := c.WaitForServerStatus(ctx, id, status, a.waitOptions(id))
+				if err != nil {
+					return err
+				}
+				return printItem(a, *s, serverDetailColumns...)
+			},
+		}
+	}
+
+	return &command{
+		name:    "server",
+		summary: "Manage servers",
+		subs: []*command{
+			{
+				name: "list", summary: "List servers",
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					servers, err := c.ListAllServersDetail(ctx, nil)
+					if err != nil {
+						return err
+					}
+					return printList(a, servers, serverColumns...)
+				},
+			},
+			{
+				name: "show", args: "<server>", summary: "Show a server", nargs: 1,
+				complete: []lister{listServers},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					s, err := c.ResolveServer(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return printItem(a, *s, serverDetailColumns...)
+				},
+			},
+			{
+				name: "create", summary: "Create a server from a boot volume", wait: true,
+				flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&create.flavor, "flavor", "", "`flavor` name or ID (required)")
+					fs.StringVar(&create.volume, "volume", "", "boot `volume` name or ID (required)")
+					fs.StringVar(&create.name, "name", "", "server `name` shown in the control panel")
+					fs.StringVar(&create.key, "key", "", "SSH `keypair` name")
+					fs.Var(&create.securityGroups, "sg", "security group `name` (repeatable)")
+					fs.StringVar(&create.adminPass, "admin-pass", "", "root `password`")
+					fs.StringVar(&create.userData, "user-data", "", "cloud-init user data `file`")
+				},
+				completeFlags: map[string]lister{
+					"flavor": listFlavors,
+					"volume": listVolumes,
+					"key":    listKeypairs,
+					"sg":     listSecurityGroupNames,
+				},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					if create.flavor == "" || create.volume == "" {
+						return usageError("--flavor and --volume are required")
+					}
+					flavor, err := c.ResolveFlavor(ctx, create.flavor)
+					if err != nil {
+						return err
+					}
+					volume, err := c.ResolveVolume(ctx, create.volume)
+					if err != nil {
+						return err
+					}
+					req := conoha.CreateServerRequest{
+						FlavorRef:          flavor.ID,
+						AdminPass:          create.adminPass,
+						BlockDeviceMapping: []conoha.BlockDeviceMap{{UUID: volume.ID}},
+						KeyName:            create.key,
+					}
+					if create.name != "" {
+						req.Metadata = map[string]string{"instance_name_tag": create.name}
+					}
+					for _, name := range create.securityGroups {
+						req.SecurityGroups = append(req.SecurityGroups, conoha.SecurityGroupRef{Name: name})
+					}
+					if create.userData != "" {
+						data, err := os.ReadFile(create.userData)
+						if err != nil {
+							return err
+						}
+						if req.UserData, err = conoha.EncodeUserData(data); err != nil {
+							return err
+						}
+					}
+					resp, err := c.CreateServer(ctx, req)
+					if err != nil {
+						return err
+					}
+					if !a.wait {
+						return printItem(a, *resp,
+							col("ID", func(r conoha.CreateServerResponse) string { return r.ID }),
+							col("ADMIN PASS", func(r conoha.CreateServerResponse) string { return r.AdminPass }))
+					}
+					s, err := c.WaitForServerStatus(ctx, resp.ID, "ACTIVE", a.waitOptions(resp.ID))
+					if err != nil {
+						return err
+					}
+					return printItem(a, *s, serverDetailColumns...)
+				},
+			},
+			{
+				name: "delete", args: "<server>", summary: "Delete a server", nargs: 1, wait: true,
+				complete: []lister{listServers},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					server, err := c.ResolveServer(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					id := server.ID
+					if err := c.DeleteServer(ctx, id); err != nil {
+						return err
+					}
+					if a.wait {
+						return c.WaitForServerDeleted(ctx, id, a.waitOptions(id))
+					}
+					return nil
+				},
+			},
+			action("start", "Start a server", "ACTIVE", (*conoha.Client).StartServer),
+			action("stop", "Stop a server", "SHUTOFF", (*conoha.Client).StopServer),
+			action("reboot", "Reboot a server", "ACTIVE", (*conoha.Client).RebootServer),
+			{
+				name: "resize", args: "<server>", nargs: 1, wait: true,
+				summary: "Change the flavor of a server; with --wait, also confirm the resize",
+				flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&resizeFlavor, "flavor", "", "new `flavor` name or ID (required)")
+				},
+				complete:      []lister{listServers},
+				completeFlags: map[string]lister{"flavor": listFlavors},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					if resizeFlavor == "" {
+						return usageError("--flavor is required")
+					}
+					server, err := c.ResolveServer(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					id := server.ID
+					flavor, err := c.ResolveFlavor(ctx, resizeFlavor)
+					if err != nil {
+						return err
+					}
+					if err := c.ResizeServer(ctx, id, flavor.ID); err != nil {
+						return err
+					}
+					if !a.wait {
+						fmt.Fprintf(a.stderr, "Resizing %s; run \"conoha server confirm-resize %s\" once it is VERIFY_RESIZE.\n", id, args[0])
+						return nil
+					}
+					if _, err := c.WaitForServerStatus(ctx, id, "VERIFY_RESIZE", a.waitOptions(id)); err != nil {
+						return err
+					}
+					if err := c.ConfirmResize(ctx, id); err != nil {
+						return err
+					}
+					s, err := c.WaitForServerStatus(ctx, id, "ACTIVE", a.waitOptions(id))
+					if err != nil {
+						return err
+					}
+					return printItem(a, *s, serverDetailColumns...)
+				},
+			},
+			action("confirm-resize", "Confirm the resize of a server", "ACTIVE", (*conoha.Client).ConfirmResize),
+			action("revert-resize", "Revert the resize of a server", "ACTIVE", (*conoha.Client).RevertResize),
+			{
+				name: "flavors", summary: "List flavors",
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					flavors, err := c.ListFlavorsDetail(ctx)
+					if err != nil {
+						return err
+					}
+					return printList(a, flavors,
+						col("ID", func(f conoha.FlavorDetail) string { return f.ID }),
+						col("NAME", func(f conoha.FlavorDetail) string { return f.Name }),
+						col("VCPUS", func(f conoha.FlavorDetail) string { return itoa(f.VCPUs) }),
+						col("RAM (MB)", func(f conoha.FlavorDetail) string { return itoa(f.RAM) }),
+						col("DISK (GB)", func(f conoha.FlavorDetail) string { return itoa(f.Disk) }))
+				},
+			},
+		},
+	}
+}
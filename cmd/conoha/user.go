@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+var subUserColumns = []column[conoha.SubUser]{
+	col("ID", func(u conoha.SubUser) string { return u.ID }),
+	col("NAME", func(u conoha.SubUser) string { return u.Name }),
+	col("ROLES", func(u conoha.SubUser) string {
+		names := make([]string, len(u.Roles))
+		for i, r := range u.Roles {
+			names[i] = r.Name
+		}
+		return strings.Join(names, ",")
+	}),
+}
+
+func userCommand() *command {
+	var password string
+	var roles stringList
+
+	return &command{
+		name:    "user",
+		summary: "Manage sub-users and roles",
+		subs: []*command{
+			{
+				name: "list", summary: "List sub-users",
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					users, err := c.ListSubUsers(ctx)
+					if err != nil {
+						return err
+					}
+					return printList(a, users, subUserColumns...)
+				},
+			},
+			{
+				name: "create", summary: "Create a sub-user",
+				flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&password, "password", "", "`password` of the sub-user (required)")
+					fs.Var(&roles, "role", "`role` name or ID to assign (repeatable)")
+				},
+				completeFlags: map[string]lister{"role": listRoles},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					if password == "" {
+						return usageError("--password is required")
+					}
+					roleIDs := make([]string, len(roles))
+					for i, role := range roles {
+						id, err := resolve(ctx, c, listRoles, getRole, "role", role)
+						if err != nil {
+							return err
+						}
+						roleIDs[i] = id
+					}
+					u, err := c.CreateSubUser(ctx, password, roleIDs)
+					if err != nil {
+						return err
+					}
+					return printItem(a, *u, subUserColumns...)
+				},
+			},
+			{
+				name: "delete", args: "<user>", summary: "Delete a sub-user", nargs: 1,
+				complete: []lister{listSubUsers},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					id, err := resolve(ctx, c, listSubUsers, getSubUser, "sub-user", args[0])
+					if err != nil {
+						return err
+					}
+					return c.DeleteSubUser(ctx, id)
+				},
+			},
+			{
+				name: "roles", summary: "List roles",
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					list, err := c.ListRoles(ctx)
+					if err != nil {
+						return err
+					}
+					return printList(a, list,
+						col("ID", func(r conoha.RoleDetail) string { return r.ID }),
+						col("NAME", func(r conoha.RoleDetail) string { return r.Name }),
+						col("PERMISSIONS", func(r conoha.RoleDetail) string { return strings.Join(r.Permissions, ",") }))
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+var volumeColumns = []column[conoha.Volume]{
+	col("ID", func(v conoha.Volume) string { return v.ID }),
+	col("NAME", func(v conoha.Volume) string { return v.Name }),
+	col("STATUS", func(v conoha.Volume) string { return v.Status }),
+	col("SIZE (GB)", func(v conoha.Volume) string { return itoa(v.Size) }),
+	col("TYPE", func(v conoha.Volume) string { return v.VolumeType }),
+	col("BOOTABLE", func(v conoha.Volume) string { return v.Bootable }),
+}
+
+func volumeCommand() *command {
+	var create struct {
+		name, volumeType, image, description string
+		size                                 int
+	}
+	var force bool
+
+	return &command{
+		name:    "volume",
+		summary: "Manage block storage volumes",
+		subs: []*command{
+			{
+				name: "list", summary: "List volumes",
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					volumes, err := c.ListVolumesDetail(ctx, nil)
+					if err != nil {
+						return err
+					}
+					return printList(a, volumes, volumeColumns...)
+				},
+			},
+			{
+				name: "show", args: "<volume>", summary: "Show a volume", nargs: 1,
+				complete: []lister{listVolumes},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					v, err := c.ResolveVolume(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return printItem(a, *v, volumeColumns...)
+				},
+			},
+			{
+				name: "create", summary: "Create a volume, empty or from an image", wait: true,
+				flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&create.name, "name", "", "volume `name`")
+					fs.IntVar(&create.size, "size", 0, "`size` in GB (required)")
+					fs.StringVar(&create.volumeType, "type", "", "volume `type`, e.g. c3j1-ds02-boot for boot volumes")
+					fs.StringVar(&create.image, "image", "", "`image` name or ID to copy onto the volume")
+					fs.StringVar(&create.description, "description", "", "volume `description`")
+				},
+				completeFlags: map[string]lister{
+					"type":  listVolumeTypes,
+					"image": listImages,
+				},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					if create.size <= 0 {
+						return usageError("--size is required")
+					}
+					req := conoha.CreateVolumeRequest{
+						Size:       create.size,
+						Name:       create.name,
+						VolumeType: create.volumeType,
+					}
+					if create.description != "" {
+						req.Description = &create.description
+					}
+					if create.image != "" {
+						image, err := c.ResolveImage(ctx, create.image)
+						if err != nil {
+							return err
+						}
+						req.ImageRef = image.ID
+					}
+					v, err := c.CreateVolume(ctx, req)
+					if err != nil {
+						return err
+					}
+					if a.wait {
+						if v, err = c.WaitForVolumeStatus(ctx, v.ID, "available", a.waitOptions(v.ID)); err != nil {
+							return err
+						}
+					}
+					return printItem(a, *v, volumeColumns...)
+				},
+			},
+			{
+				name: "delete", args: "<volume>", summary: "Delete a volume", nargs: 1, wait: true,
+				flags: func(fs *flag.FlagSet) {
+					fs.BoolVar(&force, "force", false, "delete the volume whatever its status")
+				},
+				complete: []lister{listVolumes},
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					volume, err := c.ResolveVolume(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					id := volume.ID
+					if err := c.DeleteVolume(ctx, id, force); err != nil {
+						return err
+					}
+					if a.wait {
+						return c.WaitForVolumeDeleted(ctx, id, a.waitOptions(id))
+					}
+					return nil
+				},
+			},
+			{
+				name: "types", summary: "List volume types",
+				run: func(ctx context.Context, a *app, c *conoha.Client, args []string) error {
+					types, err := c.ListVolumeTypes(ctx)
+					if err != nil {
+						return err
+					}
+					return printList(a, types,
+						col("ID", func(t conoha.VolumeType) string { return t.ID }),
+						col("NAME", func(t conoha.VolumeType) string { return t.Name }),
+						col("DESCRIPTION", func(t conoha.VolumeType) string { return t.Description }))
+				},
+			},
+		},
+	}
+}
@@ -108,6 +108,7 @@ type RebuildServerRequest struct {
 	ImageRef  string `json:"imageRef"`
 	AdminPass string `json:"adminPass"`
 	KeyName   string `json:"key_name,omitempty"`
+	UserData  string `json:"user_data,omitempty"`
 }
 
 // RemoteConsoleRequest is the request for a console URL.
@@ -157,7 +158,7 @@ type remoteConsoleResponse struct {
 
 // ListServers lists servers (basic).
 func (c *Client) ListServers(ctx context.Context, opts *ListServersOptions) ([]Server, error) {
-	url := c.ComputeURL + "/servers"
+	url := c.computeURL() + "/servers"
 	if opts != nil {
 		params := map[string]string{}
 		if opts.Limit > 0 {
@@ -187,7 +188,7 @@ func (c *Client) ListServers(ctx context.Context, opts *ListServersOptions) ([]S
 
 // ListServersDetail lists servers with full details.
 func (c *Client) ListServersDetail(ctx context.Context, opts *ListServersOptions) ([]ServerDetail, error) {
-	url := c.ComputeURL + "/servers/detail"
+	url := c.computeURL() + "/servers/detail"
 	if opts != nil {
 		params := map[string]string{}
 		if opts.Limit > 0 {
@@ -217,7 +218,7 @@ func (c *Client) ListServersDetail(ctx context.Context, opts *ListServersOptions
 
 // GetServer gets a server's details.
 func (c *Client) GetServer(ctx context.Context, serverID string) (*ServerDetail, error) {
-	url := fmt.Sprintf("%s/servers/%s", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s", c.computeURL(), serverID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -229,9 +230,14 @@ func (c *Client) GetServer(ctx context.Context, serverID string) (*ServerDetail,
 	return &result.Server, nil
 }
 
-// CreateServer creates a new server.
+// CreateServer creates a new server. It returns an error wrapping
+// ErrUserDataTooLarge, without calling the API, when opts.UserData exceeds
+// MaxUserDataSize.
 func (c *Client) CreateServer(ctx context.Context, opts CreateServerRequest) (*CreateServerResponse, error) {
-	url := c.ComputeURL + "/servers"
+	if err := checkUserData(opts.UserData); err != nil {
+		return nil, err
+	}
+	url := c.computeURL() + "/servers"
 	body := map[string]interface{}{"server": opts}
 	req, err := c.newRequest(ctx, http.MethodPost, url, body)
 	if err != nil {
@@ -246,7 +252,7 @@ func (c *Client) CreateServer(ctx context.Context, opts CreateServerRequest) (*C
 
 // DeleteServer deletes a server.
 func (c *Client) DeleteServer(ctx context.Context, serverID string) error {
-	url := fmt.Sprintf("%s/servers/%s", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s", c.computeURL(), serverID)
 	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
@@ -260,7 +266,7 @@ func (c *Client) DeleteServer(ctx context.Context, serverID string) error {
 // ------------------------------------------------------------
 
 func (c *Client) serverAction(ctx context.Context, serverID string, body interface{}) error {
-	url := fmt.Sprintf("%s/servers/%s/action", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/action", c.computeURL(), serverID)
 	req, err := c.newRequest(ctx, http.MethodPost, url, body)
 	if err != nil {
 		return err
@@ -293,8 +299,12 @@ func (c *Client) ForceStopServer(ctx context.Context, serverID string) error {
 	})
 }
 
-// RebuildServer reinstalls the server OS.
+// RebuildServer reinstalls the server OS. Like CreateServer, it checks
+// the size of opts.UserData first.
 func (c *Client) RebuildServer(ctx context.Context, serverID string, opts RebuildServerRequest) error {
+	if err := checkUserData(opts.UserData); err != nil {
+		return err
+	}
 	return c.serverAction(ctx, serverID, map[string]interface{}{"rebuild": opts})
 }
 
@@ -332,7 +342,7 @@ func (c *Client) SetStorageController(ctx context.Context, serverID, bus string)
 
 // MountISO mounts an ISO image (enters rescue mode).
 func (c *Client) MountISO(ctx context.Context, serverID, imageRef string) (string, error) {
-	url := fmt.Sprintf("%s/servers/%s/action", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/action", c.computeURL(), serverID)
 	body := map[string]interface{}{
 		"rescue": map[string]string{"rescue_image_ref": imageRef},
 	}
@@ -362,7 +372,7 @@ type addressesResponse struct {
 
 // GetServerAddresses gets all IP addresses of a server.
 func (c *Client) GetServerAddresses(ctx context.Context, serverID string) (map[string][]Address, error) {
-	url := fmt.Sprintf("%s/servers/%s/ips", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/ips", c.computeURL(), serverID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -376,7 +386,7 @@ func (c *Client) GetServerAddresses(ctx context.Context, serverID string) (map[s
 
 // GetServerAddressesByNetwork gets IP addresses of a server for a specific network.
 func (c *Client) GetServerAddressesByNetwork(ctx context.Context, serverID, networkName string) ([]Address, error) {
-	url := fmt.Sprintf("%s/servers/%s/ips/%s", c.ComputeURL, serverID, networkName)
+	url := fmt.Sprintf("%s/servers/%s/ips/%s", c.computeURL(), serverID, networkName)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -418,7 +428,7 @@ type serverSecurityGroupsResponse struct {
 
 // GetServerSecurityGroups gets security groups of a server.
 func (c *Client) GetServerSecurityGroups(ctx context.Context, serverID string) ([]ServerSecurityGroup, error) {
-	url := fmt.Sprintf("%s/servers/%s/os-security-groups", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/os-security-groups", c.computeURL(), serverID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -436,7 +446,7 @@ func (c *Client) GetServerSecurityGroups(ctx context.Context, serverID string) (
 
 // GetConsoleURL gets a remote console URL for a server.
 func (c *Client) GetConsoleURL(ctx context.Context, serverID string, opts RemoteConsoleRequest) (*RemoteConsole, error) {
-	url := fmt.Sprintf("%s/servers/%s/remote-consoles", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/remote-consoles", c.computeURL(), serverID)
 	body := map[string]interface{}{"remote_console": opts}
 	req, err := c.newRequest(ctx, http.MethodPost, url, body)
 	if err != nil {
@@ -471,7 +481,7 @@ type metadataResponse struct {
 
 // GetServerMetadata gets a server's metadata.
 func (c *Client) GetServerMetadata(ctx context.Context, serverID string) (map[string]string, error) {
-	url := fmt.Sprintf("%s/servers/%s/metadata", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/metadata", c.computeURL(), serverID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -485,7 +495,7 @@ func (c *Client) GetServerMetadata(ctx context.Context, serverID string) (map[st
 
 // UpdateServerMetadata updates a server's metadata.
 func (c *Client) UpdateServerMetadata(ctx context.Context, serverID string, metadata map[string]string) (map[string]string, error) {
-	url := fmt.Sprintf("%s/servers/%s/metadata", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/metadata", c.computeURL(), serverID)
 	body := map[string]interface{}{"metadata": metadata}
 	req, err := c.newRequest(ctx, http.MethodPost, url, body)
 	if err != nil {
@@ -538,7 +548,7 @@ type flavorDetailResponse struct {
 
 // ListFlavors lists available flavors (basic).
 func (c *Client) ListFlavors(ctx context.Context) ([]Flavor, error) {
-	url := c.ComputeURL + "/flavors"
+	url := c.computeURL() + "/flavors"
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -552,7 +562,7 @@ func (c *Client) ListFlavors(ctx context.Context) ([]Flavor, error) {
 
 // ListFlavorsDetail lists available flavors with full details.
 func (c *Client) ListFlavorsDetail(ctx context.Context) ([]FlavorDetail, error) {
-	url := c.ComputeURL + "/flavors/detail"
+	url := c.computeURL() + "/flavors/detail"
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -566,7 +576,7 @@ func (c *Client) ListFlavorsDetail(ctx context.Context) ([]FlavorDetail, error)
 
 // GetFlavor gets a flavor's details.
 func (c *Client) GetFlavor(ctx context.Context, flavorID string) (*FlavorDetail, error) {
-	url := fmt.Sprintf("%s/flavors/%s", c.ComputeURL, flavorID)
+	url := fmt.Sprintf("%s/flavors/%s", c.computeURL(), flavorID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -616,7 +626,7 @@ type ListKeypairsOptions struct {
 
 // ListKeypairs lists SSH keypairs.
 func (c *Client) ListKeypairs(ctx context.Context, opts *ListKeypairsOptions) ([]Keypair, error) {
-	url := c.ComputeURL + "/os-keypairs"
+	url := c.computeURL() + "/os-keypairs"
 	if opts != nil {
 		params := map[string]string{}
 		if opts.Limit > 0 {
@@ -644,7 +654,7 @@ func (c *Client) ListKeypairs(ctx context.Context, opts *ListKeypairsOptions) ([
 
 // CreateKeypair generates a new SSH keypair.
 func (c *Client) CreateKeypair(ctx context.Context, name string) (*Keypair, error) {
-	url := c.ComputeURL + "/os-keypairs"
+	url := c.computeURL() + "/os-keypairs"
 	body := map[string]interface{}{
 		"keypair": map[string]string{"name": name},
 	}
@@ -661,7 +671,7 @@ func (c *Client) CreateKeypair(ctx context.Context, name string) (*Keypair, erro
 
 // ImportKeypair imports an existing public key.
 func (c *Client) ImportKeypair(ctx context.Context, name, publicKey string) (*Keypair, error) {
-	url := c.ComputeURL + "/os-keypairs"
+	url := c.computeURL() + "/os-keypairs"
 	body := map[string]interface{}{
 		"keypair": map[string]string{
 			"name":       name,
@@ -681,7 +691,7 @@ func (c *Client) ImportKeypair(ctx context.Context, name, publicKey string) (*Ke
 
 // GetKeypair gets a keypair detail.
 func (c *Client) GetKeypair(ctx context.Context, name string) (*Keypair, error) {
-	url := fmt.Sprintf("%s/os-keypairs/%s", c.ComputeURL, name)
+	url := fmt.Sprintf("%s/os-keypairs/%s", c.computeURL(), name)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -695,7 +705,7 @@ func (c *Client) GetKeypair(ctx context.Context, name string) (*Keypair, error)
 
 // DeleteKeypair deletes an SSH keypair.
 func (c *Client) DeleteKeypair(ctx context.Context, name string) error {
-	url := fmt.Sprintf("%s/os-keypairs/%s", c.ComputeURL, name)
+	url := fmt.Sprintf("%s/os-keypairs/%s", c.computeURL(), name)
 	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
@@ -733,7 +743,7 @@ type interfaceResponse struct {
 
 // ListServerInterfaces lists ports attached to a server.
 func (c *Client) ListServerInterfaces(ctx context.Context, serverID string) ([]InterfaceAttachment, error) {
-	url := fmt.Sprintf("%s/servers/%s/os-interface", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/os-interface", c.computeURL(), serverID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -747,7 +757,7 @@ func (c *Client) ListServerInterfaces(ctx context.Context, serverID string) ([]I
 
 // GetServerInterface gets a specific port attachment.
 func (c *Client) GetServerInterface(ctx context.Context, serverID, portID string) (*InterfaceAttachment, error) {
-	url := fmt.Sprintf("%s/servers/%s/os-interface/%s", c.ComputeURL, serverID, portID)
+	url := fmt.Sprintf("%s/servers/%s/os-interface/%s", c.computeURL(), serverID, portID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -761,7 +771,7 @@ func (c *Client) GetServerInterface(ctx context.Context, serverID, portID string
 
 // AttachPort attaches a port to a server.
 func (c *Client) AttachPort(ctx context.Context, serverID, portID string) (*InterfaceAttachment, error) {
-	url := fmt.Sprintf("%s/servers/%s/os-interface", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/os-interface", c.computeURL(), serverID)
 	body := map[string]interface{}{
 		"interfaceAttachment": map[string]string{"port_id": portID},
 	}
@@ -778,7 +788,7 @@ func (c *Client) AttachPort(ctx context.Context, serverID, portID string) (*Inte
 
 // DetachPort detaches a port from a server.
 func (c *Client) DetachPort(ctx context.Context, serverID, portID string) error {
-	url := fmt.Sprintf("%s/servers/%s/os-interface/%s", c.ComputeURL, serverID, portID)
+	url := fmt.Sprintf("%s/servers/%s/os-interface/%s", c.computeURL(), serverID, portID)
 	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
@@ -809,7 +819,7 @@ type volumeAttachmentResponse struct {
 
 // ListServerVolumes lists volumes attached to a server.
 func (c *Client) ListServerVolumes(ctx context.Context, serverID string) ([]ServerVolumeAttachment, error) {
-	url := fmt.Sprintf("%s/servers/%s/os-volume_attachments", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/os-volume_attachments", c.computeURL(), serverID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -823,7 +833,7 @@ func (c *Client) ListServerVolumes(ctx context.Context, serverID string) ([]Serv
 
 // GetServerVolume gets a specific volume attachment.
 func (c *Client) GetServerVolume(ctx context.Context, serverID, volumeID string) (*ServerVolumeAttachment, error) {
-	url := fmt.Sprintf("%s/servers/%s/os-volume_attachments/%s", c.ComputeURL, serverID, volumeID)
+	url := fmt.Sprintf("%s/servers/%s/os-volume_attachments/%s", c.computeURL(), serverID, volumeID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -837,7 +847,7 @@ func (c *Client) GetServerVolume(ctx context.Context, serverID, volumeID string)
 
 // AttachVolume attaches a volume to a server.
 func (c *Client) AttachVolume(ctx context.Context, serverID, volumeID string) (*ServerVolumeAttachment, error) {
-	url := fmt.Sprintf("%s/servers/%s/os-volume_attachments", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/os-volume_attachments", c.computeURL(), serverID)
 	body := map[string]interface{}{
 		"volumeAttachment": map[string]string{"volumeId": volumeID},
 	}
@@ -854,7 +864,7 @@ func (c *Client) AttachVolume(ctx context.Context, serverID, volumeID string) (*
 
 // DetachVolume detaches a volume from a server.
 func (c *Client) DetachVolume(ctx context.Context, serverID, volumeID string) error {
-	url := fmt.Sprintf("%s/servers/%s/os-volume_attachments/%s", c.ComputeURL, serverID, volumeID)
+	url := fmt.Sprintf("%s/servers/%s/os-volume_attachments/%s", c.computeURL(), serverID, volumeID)
 	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
@@ -882,7 +892,7 @@ type MonitoringOptions struct {
 
 // GetCPUUsage gets CPU usage data for a server.
 func (c *Client) GetCPUUsage(ctx context.Context, serverID string, opts *MonitoringOptions) (*RRDData, error) {
-	url := fmt.Sprintf("%s/servers/%s/rrd/cpu", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/rrd/cpu", c.computeURL(), serverID)
 	if opts != nil {
 		params := map[string]string{}
 		if opts.StartDateRaw != "" {
@@ -916,7 +926,7 @@ type DiskMonitoringOptions struct {
 
 // GetDiskIO gets disk I/O data for a server.
 func (c *Client) GetDiskIO(ctx context.Context, serverID string, opts *DiskMonitoringOptions) (*RRDData, error) {
-	url := fmt.Sprintf("%s/servers/%s/rrd/disk", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/rrd/disk", c.computeURL(), serverID)
 	if opts != nil {
 		params := map[string]string{}
 		if opts.Device != "" {
@@ -956,7 +966,7 @@ func (c *Client) GetNetworkTraffic(ctx context.Context, serverID string, opts Ne
 	if opts.PortID == "" {
 		return nil, fmt.Errorf("conoha: PortID is required for GetNetworkTraffic")
 	}
-	url := fmt.Sprintf("%s/servers/%s/rrd/interface", c.ComputeURL, serverID)
+	url := fmt.Sprintf("%s/servers/%s/rrd/interface", c.computeURL(), serverID)
 	params := map[string]string{"port_id": opts.PortID}
 	if opts.StartDateRaw != "" {
 		params["start_date_raw"] = opts.StartDateRaw
@@ -0,0 +1,311 @@
+package conoha
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config holds the credentials, region and endpoints of a client, as read
+// from the environment by ConfigFromEnv or from a profile by LoadProfile.
+//
+// Credentials are either a user and tenant, both by ID or both by name,
+// with a password, or an API credential (AccessKey and SecretKey, see
+// AuthenticateWithCredential).
+type Config struct {
+	UserID     string
+	UserName   string
+	Password   string
+	TenantID   string
+	TenantName string
+	AccessKey  string
+	SecretKey  string
+
+	Region    string
+	Endpoints Endpoints
+}
+
+// configKeys maps profile keys to Config fields. The environment variable
+// of a key is "CONOHA_" followed by the key in upper case, e.g.
+// CONOHA_USER_ID or CONOHA_COMPUTE_URL.
+var configKeys = map[string]func(cfg *Config) *string{
+	"user_id":            func(cfg *Config) *string { return &cfg.UserID },
+	"user_name":          func(cfg *Config) *string { return &cfg.UserName },
+	"password":           func(cfg *Config) *string { return &cfg.Password },
+	"tenant_id":          func(cfg *Config) *string { return &cfg.TenantID },
+	"tenant_name":        func(cfg *Config) *string { return &cfg.TenantName },
+	"access_key":         func(cfg *Config) *string { return &cfg.AccessKey },
+	"secret_key":         func(cfg *Config) *string { return &cfg.SecretKey },
+	"region":             func(cfg *Config) *string { return &cfg.Region },
+	"identity_url":       func(cfg *Config) *string { return &cfg.Endpoints.Identity },
+	"compute_url":        func(cfg *Config) *string { return &cfg.Endpoints.Compute },
+	"block_storage_url":  func(cfg *Config) *string { return &cfg.Endpoints.BlockStorage },
+	"image_url":          func(cfg *Config) *string { return &cfg.Endpoints.ImageService },
+	"network_url":        func(cfg *Config) *string { return &cfg.Endpoints.Networking },
+	"lbaas_url":          func(cfg *Config) *string { return &cfg.Endpoints.LBaaS },
+	"object_storage_url": func(cfg *Config) *string { return &cfg.Endpoints.ObjectStore },
+	"dns_url":            func(cfg *Config) *string { return &cfg.Endpoints.DNS },
+}
+
+// ConfigFromEnv reads a Config from the CONOHA_* environment variables:
+//
+//	CONOHA_USER_ID, CONOHA_PASSWORD, CONOHA_TENANT_ID
+//	CONOHA_USER_NAME, CONOHA_TENANT_NAME        (instead of the IDs)
+//	CONOHA_ACCESS_KEY, CONOHA_SECRET_KEY        (instead of a password)
+//	CONOHA_REGION
+//	CONOHA_IDENTITY_URL, CONOHA_COMPUTE_URL, CONOHA_BLOCK_STORAGE_URL,
+//	CONOHA_IMAGE_URL, CONOHA_NETWORK_URL, CONOHA_LBAAS_URL,
+//	CONOHA_OBJECT_STORAGE_URL, CONOHA_DNS_URL
+func ConfigFromEnv() Config {
+	var cfg Config
+	for key, field := range configKeys {
+		*field(&cfg) = os.Getenv("CONOHA_" + strings.ToUpper(key))
+	}
+	return cfg
+}
+
+// DefaultProfile is the profile used when none is named.
+const DefaultProfile = "default"
+
+// DefaultConfigFile returns the profile file used when none is given:
+// $CONOHA_CONFIG_FILE if set, otherwise "conoha/config" under the user's
+// configuration directory (e.g. ~/.config/conoha/config on Linux).
+func DefaultConfigFile() (string, error) {
+	if path := os.Getenv("CONOHA_CONFIG_FILE"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "conoha", "config"), nil
+}
+
+// LoadProfile reads the named profile from an INI file. Each profile is a
+// section whose keys are the lower-case names of the CONOHA_* variables
+// without the prefix:
+//
+//	[default]
+//	user_id   = 0123456789abcdef
+//	password  = secret
+//	tenant_id = fedcba9876543210
+//
+//	[staging]
+//	region       = c3j2
+//	access_key   = ...
+//	secret_key   = ...
+//	identity_url = https://identity.c3j2.conoha.io
+//
+// An empty path selects DefaultConfigFile, and an empty name selects
+// $CONOHA_PROFILE or, if that is unset, DefaultProfile.
+func LoadProfile(path, name string) (*Config, error) {
+	if path == "" {
+		var err error
+		if path, err = DefaultConfigFile(); err != nil {
+			return nil, fmt.Errorf("locate config file: %w", err)
+		}
+	}
+	if name == "" {
+		name = os.Getenv("CONOHA_PROFILE")
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cfg Config
+	var section string
+	var found bool
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("%s:%d: malformed section header %q", path, n, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == name {
+				found = true
+			}
+			continue
+		}
+		if section != name {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value, got %q", path, n, line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		field, ok := configKeys[key]
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: unknown key %q", path, n, key)
+		}
+		*field(&cfg) = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("profile %q not found in %s", name, path)
+	}
+	return &cfg, nil
+}
+
+// validate checks that cfg holds one complete set of credentials.
+func (cfg *Config) validate() error {
+	switch {
+	case cfg.AccessKey != "" || cfg.SecretKey != "":
+		if cfg.AccessKey == "" || cfg.SecretKey == "" {
+			return errors.New("conoha: both access key and secret key are required")
+		}
+	case cfg.Password == "":
+		return errors.New("conoha: no credentials configured: set a password or an access and secret key")
+	case cfg.UserID != "" && cfg.TenantID != "":
+	case cfg.UserName != "" && cfg.TenantName != "":
+	default:
+		return errors.New("conoha: a user and tenant are required, both by ID or both by name")
+	}
+	return nil
+}
+
+// authenticate authenticates c with the credentials of cfg.
+func (cfg *Config) authenticate(ctx context.Context, c *Client) error {
+	var err error
+	switch {
+	case cfg.AccessKey != "":
+		_, err = c.AuthenticateWithCredential(ctx, cfg.AccessKey, cfg.SecretKey)
+	case cfg.UserID != "" && cfg.TenantID != "":
+		_, err = c.Authenticate(ctx, cfg.UserID, cfg.Password, cfg.TenantID)
+	default:
+		_, err = c.AuthenticateByName(ctx, cfg.UserName, cfg.Password, cfg.TenantName)
+	}
+	return err
+}
+
+// NewClientFromConfig creates a client for the region and endpoints of
+// cfg. The client authenticates with the credentials of cfg on its first
+// API call rather than here; errors from that surface from the call. opts
+// are applied after the configuration and may override it.
+func NewClientFromConfig(cfg Config, opts ...ClientOption) (*Client, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	var cfgOpts []ClientOption
+	if cfg.Region != "" {
+		cfgOpts = append(cfgOpts, WithRegion(cfg.Region))
+	}
+	cfgOpts = append(cfgOpts, WithEndpoints(cfg.Endpoints))
+
+	c := NewClient(append(cfgOpts, opts...)...)
+	c.TenantID = cfg.TenantID
+	c.reauth = func(ctx context.Context) error {
+		return cfg.authenticate(ctx, c)
+	}
+	c.lazyAuth = &lazyAuthState{endpoints: c.endpointBases(), tenantID: c.TenantID}
+	return c, nil
+}
+
+// NewClientFromEnv creates a client configured from the CONOHA_*
+// environment variables (see ConfigFromEnv). It authenticates lazily on
+// the first API call.
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	return NewClientFromConfig(ConfigFromEnv(), opts...)
+}
+
+// NewClientFromProfile creates a client configured from a profile of the
+// default config file (see LoadProfile). It authenticates lazily on the
+// first API call.
+func NewClientFromProfile(name string, opts ...ClientOption) (*Client, error) {
+	cfg, err := LoadProfile("", name)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientFromConfig(*cfg, opts...)
+}
+
+// authRequestKey marks the context of token requests, which must not wait
+// for lazy authentication.
+type authRequestKey struct{}
+
+// lazyAuthState records the endpoints and tenant ID of a client created by
+// NewClientFromConfig before it authenticated, which the URLs of requests
+// built without a token are based on.
+type lazyAuthState struct {
+	endpoints [len(endpointServices)]endpointBase
+	tenantID  string
+}
+
+// authenticateLazily performs the first authentication of a client created
+// by NewClientFromConfig when it sends a request built before it had a
+// token. The returned request carries the token and, if authentication
+// changed the endpoints or tenant ID the URL was built from, is pointed at
+// the new ones.
+func (c *Client) authenticateLazily(req *http.Request) (*http.Request, error) {
+	if c.lazyAuth == nil || req.Header.Get("X-Auth-Token") != "" || req.Context().Value(authRequestKey{}) != nil {
+		return req, nil
+	}
+	if !c.canReauth() {
+		return req, nil
+	}
+	// Concurrent first requests share one authentication; the others find
+	// the token already issued.
+	if err := c.refreshToken(req.Context(), ""); err != nil {
+		return nil, fmt.Errorf("authenticate: %w", err)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Auth-Token", c.currentToken())
+	if rebased := c.rebaseURL(req.URL.String(), c.lazyAuth.endpoints, c.lazyAuth.tenantID); rebased != req.URL.String() {
+		u, err := url.Parse(rebased)
+		if err != nil {
+			return nil, err
+		}
+		req.URL, req.Host = u, u.Host
+	}
+	return req, nil
+}
+
+// rebaseURL rewrites rawURL, built from the endpoints and tenant ID the
+// client had before authenticating, onto the current ones. Block Storage
+// and Object Storage URLs carry the tenant ID in their path.
+func (c *Client) rebaseURL(rawURL string, before [len(endpointServices)]endpointBase, oldTenantID string) string {
+	old := matchEndpoint(before, rawURL)
+	if old.service == "" {
+		return rawURL
+	}
+	rest := rawURL[len(old.base):]
+	if tenantID := c.tenantID(); tenantID != oldTenantID {
+		switch old.service {
+		case ServiceTypeBlockStorage:
+			if prefix := "/" + oldTenantID + "/"; strings.HasPrefix(rest, prefix) {
+				rest = "/" + tenantID + "/" + rest[len(prefix):]
+			}
+		case ServiceTypeObjectStore:
+			prefix := "/AUTH_" + oldTenantID
+			if strings.HasPrefix(rest, prefix) && (len(rest) == len(prefix) || strings.ContainsRune("/?", rune(rest[len(prefix)]))) {
+				rest = "/AUTH_" + tenantID + rest[len(prefix):]
+			}
+		}
+	}
+	for _, ep := range c.endpointBases() {
+		if ep.service == old.service {
+			return ep.base + rest
+		}
+	}
+	return rawURL
+}
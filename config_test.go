@@ -0,0 +1,161 @@
+package conoha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("CONOHA_USER_ID", "user-1")
+	t.Setenv("CONOHA_PASSWORD", "pass")
+	t.Setenv("CONOHA_TENANT_ID", "tenant-1")
+	t.Setenv("CONOHA_REGION", "c3j2")
+	t.Setenv("CONOHA_COMPUTE_URL", "https://compute.example.com/v2.1")
+	t.Setenv("CONOHA_DNS_URL", "https://dns.example.com")
+
+	cfg := ConfigFromEnv()
+	if cfg.UserID != "user-1" || cfg.Password != "pass" || cfg.TenantID != "tenant-1" || cfg.Region != "c3j2" {
+		t.Errorf("cfg = %+v", cfg)
+	}
+	if cfg.Endpoints.Compute != "https://compute.example.com/v2.1" || cfg.Endpoints.DNS != "https://dns.example.com" {
+		t.Errorf("Endpoints = %+v", cfg.Endpoints)
+	}
+
+	c, err := NewClientFromEnv()
+	assertNoError(t, err)
+	if c.Region != "c3j2" || c.ComputeURL != "https://compute.example.com/v2.1" {
+		t.Errorf("Region = %q, ComputeURL = %q", c.Region, c.ComputeURL)
+	}
+	if c.NetworkingURL != "https://networking.c3j2.conoha.io/v2.0" {
+		t.Errorf("NetworkingURL = %q, want region pattern", c.NetworkingURL)
+	}
+}
+
+const testProfiles = `
+# shared settings
+[default]
+user_id   = user-1
+password  = "p=ss"
+tenant_id = tenant-1
+
+; API credential for CI
+[ci]
+access_key   = AK
+secret_key   = SK
+region       = c3j2
+identity_url = https://identity.c3j2.conoha.io
+`
+
+func writeProfiles(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := writeProfiles(t, testProfiles)
+
+	cfg, err := LoadProfile(path, "")
+	assertNoError(t, err)
+	if cfg.UserID != "user-1" || cfg.Password != "p=ss" || cfg.TenantID != "tenant-1" {
+		t.Errorf("default = %+v", cfg)
+	}
+
+	t.Setenv("CONOHA_PROFILE", "ci")
+	cfg, err = LoadProfile(path, "")
+	assertNoError(t, err)
+	if cfg.AccessKey != "AK" || cfg.SecretKey != "SK" || cfg.Region != "c3j2" || cfg.UserID != "" {
+		t.Errorf("ci = %+v", cfg)
+	}
+	if cfg.Endpoints.Identity != "https://identity.c3j2.conoha.io" {
+		t.Errorf("Identity = %q", cfg.Endpoints.Identity)
+	}
+
+	if _, err := LoadProfile(path, "missing"); err == nil || !strings.Contains(err.Error(), `profile "missing" not found`) {
+		t.Errorf("err = %v", err)
+	}
+	bad := writeProfiles(t, "[default]\nuser = x\n")
+	if _, err := LoadProfile(bad, "default"); err == nil || !strings.Contains(err.Error(), `unknown key "user"`) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestNewClientFromConfig_RequiresCredentials(t *testing.T) {
+	for _, cfg := range []Config{
+		{},
+		{UserID: "u", Password: "p"},
+		{UserID: "u", TenantName: "t", Password: "p"},
+		{AccessKey: "AK"},
+	} {
+		if _, err := NewClientFromConfig(cfg); err == nil {
+			t.Errorf("NewClientFromConfig(%+v) succeeded", cfg)
+		}
+	}
+}
+
+func TestNewClientFromConfig_AuthenticatesLazily(t *testing.T) {
+	var mu sync.Mutex
+	var authCalls int
+	var paths []string
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, "/auth/tokens") {
+			authCalls++
+			w.Header().Set("X-Subject-Token", "lazy-token")
+			w.WriteHeader(201)
+			w.Write([]byte(`{"token":{"project":{"id":"tenant-from-token"},"catalog":[
+				{"type":"volumev3","endpoints":[{"interface":"public","region":"c3j1","url":"` + serverURL + `/discovered/v3/tenant-from-token"}]}
+			]}}`))
+			return
+		}
+		if r.Header.Get("X-Auth-Token") != "lazy-token" {
+			t.Errorf("X-Auth-Token = %q", r.Header.Get("X-Auth-Token"))
+		}
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"volume":{"id":"v1"}}`))
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	client, err := NewClientFromConfig(Config{
+		UserName:   "user",
+		Password:   "pass",
+		TenantName: "tenant",
+		Endpoints:  Endpoints{Identity: server.URL},
+	})
+	assertNoError(t, err)
+	if authCalls != 0 {
+		t.Fatal("client authenticated before the first API call")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetVolume(context.Background(), "v1"); err != nil {
+				t.Errorf("GetVolume: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if authCalls != 1 {
+		t.Errorf("auth calls = %d, want 1", authCalls)
+	}
+	for _, p := range paths {
+		if p != "/discovered/v3/tenant-from-token/volumes/v1" {
+			t.Errorf("path = %q, want the discovered endpoint and tenant", p)
+		}
+	}
+}
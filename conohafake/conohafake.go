@@ -0,0 +1,33 @@
+// Package conohafake provides fakes of the service interfaces of package
+// conoha (ComputeAPI, BlockStorageAPI and so on) for unit tests that should
+// not talk to an HTTP server.
+//
+// Each fake has a function field per method, named after the method with a
+// Func suffix. Set the fields the code under test uses; the other methods
+// fail with ErrNotImplemented.
+//
+//	compute := &conohafake.Compute{
+//	    GetServerFunc: func(ctx context.Context, id string) (*conoha.ServerDetail, error) {
+//	        return &conoha.ServerDetail{ID: id, Status: "ACTIVE"}, nil
+//	    },
+//	}
+//	err := restart(ctx, compute) // restart takes a conoha.ComputeAPI
+//
+// Client combines the fakes of every service, for code that takes a
+// *conoha.Client-like value through several of the interfaces.
+package conohafake
+
+//go:generate go run ../internal/genfake
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotImplemented is returned by the methods of a fake whose function
+// field is not set.
+var ErrNotImplemented = errors.New("conohafake: not implemented")
+
+func notImplemented(method string) error {
+	return fmt.Errorf("%s: %w", method, ErrNotImplemented)
+}
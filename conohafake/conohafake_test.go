@@ -0,0 +1,49 @@
+package conohafake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+// serverStatus is code under test that only needs the Compute API.
+func serverStatus(ctx context.Context, compute conoha.ComputeAPI, id string) (string, error) {
+	s, err := compute.GetServer(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return s.Status, nil
+}
+
+func TestCompute(t *testing.T) {
+	var gotID string
+	compute := &Compute{
+		GetServerFunc: func(ctx context.Context, serverID string) (*conoha.ServerDetail, error) {
+			gotID = serverID
+			return &conoha.ServerDetail{ID: serverID, Status: "ACTIVE"}, nil
+		},
+	}
+
+	status, err := serverStatus(context.Background(), compute, "srv-1")
+	if err != nil || status != "ACTIVE" || gotID != "srv-1" {
+		t.Errorf("serverStatus = %q, %v (id %q)", status, err, gotID)
+	}
+}
+
+func TestNotImplemented(t *testing.T) {
+	c := New()
+	servers, err := c.ListServers(context.Background(), nil)
+	if servers != nil || !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("ListServers = %v, %v", servers, err)
+	}
+	if err.Error() != "Compute.ListServers: conohafake: not implemented" {
+		t.Errorf("err = %q", err)
+	}
+
+	var dns conoha.DNSAPI = c
+	if err := dns.DeleteDomain(context.Background(), "d"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("DeleteDomain = %v", err)
+	}
+}
@@ -0,0 +1,2058 @@
+// Code generated by genfake from api.go; DO NOT EDIT.
+
+package conohafake
+
+import (
+	"context"
+	"io"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+// Compute is a fake conoha.ComputeAPI. Each method calls the field named
+// after it with a Func suffix, or fails with ErrNotImplemented if that
+// field is nil.
+type Compute struct {
+	ListServersFunc                 func(context.Context, *conoha.ListServersOptions) ([]conoha.Server, error)
+	ListServersDetailFunc           func(context.Context, *conoha.ListServersOptions) ([]conoha.ServerDetail, error)
+	GetServerFunc                   func(context.Context, string) (*conoha.ServerDetail, error)
+	CreateServerFunc                func(context.Context, conoha.CreateServerRequest) (*conoha.CreateServerResponse, error)
+	DeleteServerFunc                func(context.Context, string) error
+	StartServerFunc                 func(context.Context, string) error
+	StopServerFunc                  func(context.Context, string) error
+	RebootServerFunc                func(context.Context, string) error
+	ForceStopServerFunc             func(context.Context, string) error
+	RebuildServerFunc               func(context.Context, string, conoha.RebuildServerRequest) error
+	ResizeServerFunc                func(context.Context, string, string) error
+	ConfirmResizeFunc               func(context.Context, string) error
+	RevertResizeFunc                func(context.Context, string) error
+	SetVideoDeviceFunc              func(context.Context, string, string) error
+	SetNetworkAdapterFunc           func(context.Context, string, string) error
+	SetStorageControllerFunc        func(context.Context, string, string) error
+	MountISOFunc                    func(context.Context, string, string) (string, error)
+	UnmountISOFunc                  func(context.Context, string) error
+	GetServerAddressesFunc          func(context.Context, string) (map[string][]conoha.Address, error)
+	GetServerAddressesByNetworkFunc func(context.Context, string, string) ([]conoha.Address, error)
+	GetServerSecurityGroupsFunc     func(context.Context, string) ([]conoha.ServerSecurityGroup, error)
+	GetConsoleURLFunc               func(context.Context, string, conoha.RemoteConsoleRequest) (*conoha.RemoteConsole, error)
+	GetVNCConsoleURLFunc            func(context.Context, string) (string, error)
+	GetServerMetadataFunc           func(context.Context, string) (map[string]string, error)
+	UpdateServerMetadataFunc        func(context.Context, string, map[string]string) (map[string]string, error)
+	ListFlavorsFunc                 func(context.Context) ([]conoha.Flavor, error)
+	ListFlavorsDetailFunc           func(context.Context) ([]conoha.FlavorDetail, error)
+	GetFlavorFunc                   func(context.Context, string) (*conoha.FlavorDetail, error)
+	ListKeypairsFunc                func(context.Context, *conoha.ListKeypairsOptions) ([]conoha.Keypair, error)
+	CreateKeypairFunc               func(context.Context, string) (*conoha.Keypair, error)
+	ImportKeypairFunc               func(context.Context, string, string) (*conoha.Keypair, error)
+	GetKeypairFunc                  func(context.Context, string) (*conoha.Keypair, error)
+	DeleteKeypairFunc               func(context.Context, string) error
+	ListServerInterfacesFunc        func(context.Context, string) ([]conoha.InterfaceAttachment, error)
+	GetServerInterfaceFunc          func(context.Context, string, string) (*conoha.InterfaceAttachment, error)
+	AttachPortFunc                  func(context.Context, string, string) (*conoha.InterfaceAttachment, error)
+	DetachPortFunc                  func(context.Context, string, string) error
+	ListServerVolumesFunc           func(context.Context, string) ([]conoha.ServerVolumeAttachment, error)
+	GetServerVolumeFunc             func(context.Context, string, string) (*conoha.ServerVolumeAttachment, error)
+	AttachVolumeFunc                func(context.Context, string, string) (*conoha.ServerVolumeAttachment, error)
+	DetachVolumeFunc                func(context.Context, string, string) error
+	GetCPUUsageFunc                 func(context.Context, string, *conoha.MonitoringOptions) (*conoha.RRDData, error)
+	GetDiskIOFunc                   func(context.Context, string, *conoha.DiskMonitoringOptions) (*conoha.RRDData, error)
+	GetNetworkTrafficFunc           func(context.Context, string, conoha.NetworkMonitoringOptions) (*conoha.RRDData, error)
+	EachServerFunc                  func(context.Context, *conoha.ListServersOptions, func(conoha.Server) error) error
+	ListAllServersFunc              func(context.Context, *conoha.ListServersOptions) ([]conoha.Server, error)
+	EachServerDetailFunc            func(context.Context, *conoha.ListServersOptions, func(conoha.ServerDetail) error) error
+	ListAllServersDetailFunc        func(context.Context, *conoha.ListServersOptions) ([]conoha.ServerDetail, error)
+	EachKeypairFunc                 func(context.Context, *conoha.ListKeypairsOptions, func(conoha.Keypair) error) error
+	ListAllKeypairsFunc             func(context.Context, *conoha.ListKeypairsOptions) ([]conoha.Keypair, error)
+	ResolveServerFunc               func(context.Context, string) (*conoha.ServerDetail, error)
+	ResolveFlavorFunc               func(context.Context, string) (*conoha.FlavorDetail, error)
+	LaunchServerFunc                func(context.Context, conoha.ServerSpec) (*conoha.ServerDetail, error)
+	WaitForServerStatusFunc         func(context.Context, string, string, *conoha.WaitOptions) (*conoha.ServerDetail, error)
+	WaitForServerDeletedFunc        func(context.Context, string, *conoha.WaitOptions) error
+}
+
+// ListServers calls ListServersFunc.
+func (f *Compute) ListServers(ctx context.Context, opts *conoha.ListServersOptions) ([]conoha.Server, error) {
+	if f.ListServersFunc == nil {
+		return nil, notImplemented("Compute.ListServers")
+	}
+	return f.ListServersFunc(ctx, opts)
+}
+
+// ListServersDetail calls ListServersDetailFunc.
+func (f *Compute) ListServersDetail(ctx context.Context, opts *conoha.ListServersOptions) ([]conoha.ServerDetail, error) {
+	if f.ListServersDetailFunc == nil {
+		return nil, notImplemented("Compute.ListServersDetail")
+	}
+	return f.ListServersDetailFunc(ctx, opts)
+}
+
+// GetServer calls GetServerFunc.
+func (f *Compute) GetServer(ctx context.Context, serverID string) (*conoha.ServerDetail, error) {
+	if f.GetServerFunc == nil {
+		return nil, notImplemented("Compute.GetServer")
+	}
+	return f.GetServerFunc(ctx, serverID)
+}
+
+// CreateServer calls CreateServerFunc.
+func (f *Compute) CreateServer(ctx context.Context, opts conoha.CreateServerRequest) (*conoha.CreateServerResponse, error) {
+	if f.CreateServerFunc == nil {
+		return nil, notImplemented("Compute.CreateServer")
+	}
+	return f.CreateServerFunc(ctx, opts)
+}
+
+// DeleteServer calls DeleteServerFunc.
+func (f *Compute) DeleteServer(ctx context.Context, serverID string) error {
+	if f.DeleteServerFunc == nil {
+		return notImplemented("Compute.DeleteServer")
+	}
+	return f.DeleteServerFunc(ctx, serverID)
+}
+
+// StartServer calls StartServerFunc.
+func (f *Compute) StartServer(ctx context.Context, serverID string) error {
+	if f.StartServerFunc == nil {
+		return notImplemented("Compute.StartServer")
+	}
+	return f.StartServerFunc(ctx, serverID)
+}
+
+// StopServer calls StopServerFunc.
+func (f *Compute) StopServer(ctx context.Context, serverID string) error {
+	if f.StopServerFunc == nil {
+		return notImplemented("Compute.StopServer")
+	}
+	return f.StopServerFunc(ctx, serverID)
+}
+
+// RebootServer calls RebootServerFunc.
+func (f *Compute) RebootServer(ctx context.Context, serverID string) error {
+	if f.RebootServerFunc == nil {
+		return notImplemented("Compute.RebootServer")
+	}
+	return f.RebootServerFunc(ctx, serverID)
+}
+
+// ForceStopServer calls ForceStopServerFunc.
+func (f *Compute) ForceStopServer(ctx context.Context, serverID string) error {
+	if f.ForceStopServerFunc == nil {
+		return notImplemented("Compute.ForceStopServer")
+	}
+	return f.ForceStopServerFunc(ctx, serverID)
+}
+
+// RebuildServer calls RebuildServerFunc.
+func (f *Compute) RebuildServer(ctx context.Context, serverID string, opts conoha.RebuildServerRequest) error {
+	if f.RebuildServerFunc == nil {
+		return notImplemented("Compute.RebuildServer")
+	}
+	return f.RebuildServerFunc(ctx, serverID, opts)
+}
+
+// ResizeServer calls ResizeServerFunc.
+func (f *Compute) ResizeServer(ctx context.Context, serverID, flavorRef string) error {
+	if f.ResizeServerFunc == nil {
+		return notImplemented("Compute.ResizeServer")
+	}
+	return f.ResizeServerFunc(ctx, serverID, flavorRef)
+}
+
+// ConfirmResize calls ConfirmResizeFunc.
+func (f *Compute) ConfirmResize(ctx context.Context, serverID string) error {
+	if f.ConfirmResizeFunc == nil {
+		return notImplemented("Compute.ConfirmResize")
+	}
+	return f.ConfirmResizeFunc(ctx, serverID)
+}
+
+// RevertResize calls RevertResizeFunc.
+func (f *Compute) RevertResize(ctx context.Context, serverID string) error {
+	if f.RevertResizeFunc == nil {
+		return notImplemented("Compute.RevertResize")
+	}
+	return f.RevertResizeFunc(ctx, serverID)
+}
+
+// SetVideoDevice calls SetVideoDeviceFunc.
+func (f *Compute) SetVideoDevice(ctx context.Context, serverID, model string) error {
+	if f.SetVideoDeviceFunc == nil {
+		return notImplemented("Compute.SetVideoDevice")
+	}
+	return f.SetVideoDeviceFunc(ctx, serverID, model)
+}
+
+// SetNetworkAdapter calls SetNetworkAdapterFunc.
+func (f *Compute) SetNetworkAdapter(ctx context.Context, serverID, model string) error {
+	if f.SetNetworkAdapterFunc == nil {
+		return notImplemented("Compute.SetNetworkAdapter")
+	}
+	return f.SetNetworkAdapterFunc(ctx, serverID, model)
+}
+
+// SetStorageController calls SetStorageControllerFunc.
+func (f *Compute) SetStorageController(ctx context.Context, serverID, bus string) error {
+	if f.SetStorageControllerFunc == nil {
+		return notImplemented("Compute.SetStorageController")
+	}
+	return f.SetStorageControllerFunc(ctx, serverID, bus)
+}
+
+// MountISO calls MountISOFunc.
+func (f *Compute) MountISO(ctx context.Context, serverID, imageRef string) (string, error) {
+	if f.MountISOFunc == nil {
+		return "", notImplemented("Compute.MountISO")
+	}
+	return f.MountISOFunc(ctx, serverID, imageRef)
+}
+
+// UnmountISO calls UnmountISOFunc.
+func (f *Compute) UnmountISO(ctx context.Context, serverID string) error {
+	if f.UnmountISOFunc == nil {
+		return notImplemented("Compute.UnmountISO")
+	}
+	return f.UnmountISOFunc(ctx, serverID)
+}
+
+// GetServerAddresses calls GetServerAddressesFunc.
+func (f *Compute) GetServerAddresses(ctx context.Context, serverID string) (map[string][]conoha.Address, error) {
+	if f.GetServerAddressesFunc == nil {
+		return nil, notImplemented("Compute.GetServerAddresses")
+	}
+	return f.GetServerAddressesFunc(ctx, serverID)
+}
+
+// GetServerAddressesByNetwork calls GetServerAddressesByNetworkFunc.
+func (f *Compute) GetServerAddressesByNetwork(ctx context.Context, serverID, networkName string) ([]conoha.Address, error) {
+	if f.GetServerAddressesByNetworkFunc == nil {
+		return nil, notImplemented("Compute.GetServerAddressesByNetwork")
+	}
+	return f.GetServerAddressesByNetworkFunc(ctx, serverID, networkName)
+}
+
+// GetServerSecurityGroups calls GetServerSecurityGroupsFunc.
+func (f *Compute) GetServerSecurityGroups(ctx context.Context, serverID string) ([]conoha.ServerSecurityGroup, error) {
+	if f.GetServerSecurityGroupsFunc == nil {
+		return nil, notImplemented("Compute.GetServerSecurityGroups")
+	}
+	return f.GetServerSecurityGroupsFunc(ctx, serverID)
+}
+
+// GetConsoleURL calls GetConsoleURLFunc.
+func (f *Compute) GetConsoleURL(ctx context.Context, serverID string, opts conoha.RemoteConsoleRequest) (*conoha.RemoteConsole, error) {
+	if f.GetConsoleURLFunc == nil {
+		return nil, notImplemented("Compute.GetConsoleURL")
+	}
+	return f.GetConsoleURLFunc(ctx, serverID, opts)
+}
+
+// GetVNCConsoleURL calls GetVNCConsoleURLFunc.
+func (f *Compute) GetVNCConsoleURL(ctx context.Context, serverID string) (string, error) {
+	if f.GetVNCConsoleURLFunc == nil {
+		return "", notImplemented("Compute.GetVNCConsoleURL")
+	}
+	return f.GetVNCConsoleURLFunc(ctx, serverID)
+}
+
+// GetServerMetadata calls GetServerMetadataFunc.
+func (f *Compute) GetServerMetadata(ctx context.Context, serverID string) (map[string]string, error) {
+	if f.GetServerMetadataFunc == nil {
+		return nil, notImplemented("Compute.GetServerMetadata")
+	}
+	return f.GetServerMetadataFunc(ctx, serverID)
+}
+
+// UpdateServerMetadata calls UpdateServerMetadataFunc.
+func (f *Compute) UpdateServerMetadata(ctx context.Context, serverID string, metadata map[string]string) (map[string]string, error) {
+	if f.UpdateServerMetadataFunc == nil {
+		return nil, notImplemented("Compute.UpdateServerMetadata")
+	}
+	return f.UpdateServerMetadataFunc(ctx, serverID, metadata)
+}
+
+// ListFlavors calls ListFlavorsFunc.
+func (f *Compute) ListFlavors(ctx context.Context) ([]conoha.Flavor, error) {
+	if f.ListFlavorsFunc == nil {
+		return nil, notImplemented("Compute.ListFlavors")
+	}
+	return f.ListFlavorsFunc(ctx)
+}
+
+// ListFlavorsDetail calls ListFlavorsDetailFunc.
+func (f *Compute) ListFlavorsDetail(ctx context.Context) ([]conoha.FlavorDetail, error) {
+	if f.ListFlavorsDetailFunc == nil {
+		return nil, notImplemented("Compute.ListFlavorsDetail")
+	}
+	return f.ListFlavorsDetailFunc(ctx)
+}
+
+// GetFlavor calls GetFlavorFunc.
+func (f *Compute) GetFlavor(ctx context.Context, flavorID string) (*conoha.FlavorDetail, error) {
+	if f.GetFlavorFunc == nil {
+		return nil, notImplemented("Compute.GetFlavor")
+	}
+	return f.GetFlavorFunc(ctx, flavorID)
+}
+
+// ListKeypairs calls ListKeypairsFunc.
+func (f *Compute) ListKeypairs(ctx context.Context, opts *conoha.ListKeypairsOptions) ([]conoha.Keypair, error) {
+	if f.ListKeypairsFunc == nil {
+		return nil, notImplemented("Compute.ListKeypairs")
+	}
+	return f.ListKeypairsFunc(ctx, opts)
+}
+
+// CreateKeypair calls CreateKeypairFunc.
+func (f *Compute) CreateKeypair(ctx context.Context, name string) (*conoha.Keypair, error) {
+	if f.CreateKeypairFunc == nil {
+		return nil, notImplemented("Compute.CreateKeypair")
+	}
+	return f.CreateKeypairFunc(ctx, name)
+}
+
+// ImportKeypair calls ImportKeypairFunc.
+func (f *Compute) ImportKeypair(ctx context.Context, name, publicKey string) (*conoha.Keypair, error) {
+	if f.ImportKeypairFunc == nil {
+		return nil, notImplemented("Compute.ImportKeypair")
+	}
+	return f.ImportKeypairFunc(ctx, name, publicKey)
+}
+
+// GetKeypair calls GetKeypairFunc.
+func (f *Compute) GetKeypair(ctx context.Context, name string) (*conoha.Keypair, error) {
+	if f.GetKeypairFunc == nil {
+		return nil, notImplemented("Compute.GetKeypair")
+	}
+	return f.GetKeypairFunc(ctx, name)
+}
+
+// DeleteKeypair calls DeleteKeypairFunc.
+func (f *Compute) DeleteKeypair(ctx context.Context, name string) error {
+	if f.DeleteKeypairFunc == nil {
+		return notImplemented("Compute.DeleteKeypair")
+	}
+	return f.DeleteKeypairFunc(ctx, name)
+}
+
+// ListServerInterfaces calls ListServerInterfacesFunc.
+func (f *Compute) ListServerInterfaces(ctx context.Context, serverID string) ([]conoha.InterfaceAttachment, error) {
+	if f.ListServerInterfacesFunc == nil {
+		return nil, notImplemented("Compute.ListServerInterfaces")
+	}
+	return f.ListServerInterfacesFunc(ctx, serverID)
+}
+
+// GetServerInterface calls GetServerInterfaceFunc.
+func (f *Compute) GetServerInterface(ctx context.Context, serverID, portID string) (*conoha.InterfaceAttachment, error) {
+	if f.GetServerInterfaceFunc == nil {
+		return nil, notImplemented("Compute.GetServerInterface")
+	}
+	return f.GetServerInterfaceFunc(ctx, serverID, portID)
+}
+
+// AttachPort calls AttachPortFunc.
+func (f *Compute) AttachPort(ctx context.Context, serverID, portID string) (*conoha.InterfaceAttachment, error) {
+	if f.AttachPortFunc == nil {
+		return nil, notImplemented("Compute.AttachPort")
+	}
+	return f.AttachPortFunc(ctx, serverID, portID)
+}
+
+// DetachPort calls DetachPortFunc.
+func (f *Compute) DetachPort(ctx context.Context, serverID, portID string) error {
+	if f.DetachPortFunc == nil {
+		return notImplemented("Compute.DetachPort")
+	}
+	return f.DetachPortFunc(ctx, serverID, portID)
+}
+
+// ListServerVolumes calls ListServerVolumesFunc.
+func (f *Compute) ListServerVolumes(ctx context.Context, serverID string) ([]conoha.ServerVolumeAttachment, error) {
+	if f.ListServerVolumesFunc == nil {
+		return nil, notImplemented("Compute.ListServerVolumes")
+	}
+	return f.ListServerVolumesFunc(ctx, serverID)
+}
+
+// GetServerVolume calls GetServerVolumeFunc.
+func (f *Compute) GetServerVolume(ctx context.Context, serverID, volumeID string) (*conoha.ServerVolumeAttachment, error) {
+	if f.GetServerVolumeFunc == nil {
+		return nil, notImplemented("Compute.GetServerVolume")
+	}
+	return f.GetServerVolumeFunc(ctx, serverID, volumeID)
+}
+
+// AttachVolume calls AttachVolumeFunc.
+func (f *Compute) AttachVolume(ctx context.Context, serverID, volumeID string) (*conoha.ServerVolumeAttachment, error) {
+	if f.AttachVolumeFunc == nil {
+		return nil, notImplemented("Compute.AttachVolume")
+	}
+	return f.AttachVolumeFunc(ctx, serverID, volumeID)
+}
+
+// DetachVolume calls DetachVolumeFunc.
+func (f *Compute) DetachVolume(ctx context.Context, serverID, volumeID string) error {
+	if f.DetachVolumeFunc == nil {
+		return notImplemented("Compute.DetachVolume")
+	}
+	return f.DetachVolumeFunc(ctx, serverID, volumeID)
+}
+
+// GetCPUUsage calls GetCPUUsageFunc.
+func (f *Compute) GetCPUUsage(ctx context.Context, serverID string, opts *conoha.MonitoringOptions) (*conoha.RRDData, error) {
+	if f.GetCPUUsageFunc == nil {
+		return nil, notImplemented("Compute.GetCPUUsage")
+	}
+	return f.GetCPUUsageFunc(ctx, serverID, opts)
+}
+
+// GetDiskIO calls GetDiskIOFunc.
+func (f *Compute) GetDiskIO(ctx context.Context, serverID string, opts *conoha.DiskMonitoringOptions) (*conoha.RRDData, error) {
+	if f.GetDiskIOFunc == nil {
+		return nil, notImplemented("Compute.GetDiskIO")
+	}
+	return f.GetDiskIOFunc(ctx, serverID, opts)
+}
+
+// GetNetworkTraffic calls GetNetworkTrafficFunc.
+func (f *Compute) GetNetworkTraffic(ctx context.Context, serverID string, opts conoha.NetworkMonitoringOptions) (*conoha.RRDData, error) {
+	if f.GetNetworkTrafficFunc == nil {
+		return nil, notImplemented("Compute.GetNetworkTraffic")
+	}
+	return f.GetNetworkTrafficFunc(ctx, serverID, opts)
+}
+
+// EachServer calls EachServerFunc.
+func (f *Compute) EachServer(ctx context.Context, opts *conoha.ListServersOptions, fn func(conoha.Server) error) error {
+	if f.EachServerFunc == nil {
+		return notImplemented("Compute.EachServer")
+	}
+	return f.EachServerFunc(ctx, opts, fn)
+}
+
+// ListAllServers calls ListAllServersFunc.
+func (f *Compute) ListAllServers(ctx context.Context, opts *conoha.ListServersOptions) ([]conoha.Server, error) {
+	if f.ListAllServersFunc == nil {
+		return nil, notImplemented("Compute.ListAllServers")
+	}
+	return f.ListAllServersFunc(ctx, opts)
+}
+
+// EachServerDetail calls EachServerDetailFunc.
+func (f *Compute) EachServerDetail(ctx context.Context, opts *conoha.ListServersOptions, fn func(conoha.ServerDetail) error) error {
+	if f.EachServerDetailFunc == nil {
+		return notImplemented("Compute.EachServerDetail")
+	}
+	return f.EachServerDetailFunc(ctx, opts, fn)
+}
+
+// ListAllServersDetail calls ListAllServersDetailFunc.
+func (f *Compute) ListAllServersDetail(ctx context.Context, opts *conoha.ListServersOptions) ([]conoha.ServerDetail, error) {
+	if f.ListAllServersDetailFunc == nil {
+		return nil, notImplemented("Compute.ListAllServersDetail")
+	}
+	return f.ListAllServersDetailFunc(ctx, opts)
+}
+
+// EachKeypair calls EachKeypairFunc.
+func (f *Compute) EachKeypair(ctx context.Context, opts *conoha.ListKeypairsOptions, fn func(conoha.Keypair) error) error {
+	if f.EachKeypairFunc == nil {
+		return notImplemented("Compute.EachKeypair")
+	}
+	return f.EachKeypairFunc(ctx, opts, fn)
+}
+
+// ListAllKeypairs calls ListAllKeypairsFunc.
+func (f *Compute) ListAllKeypairs(ctx context.Context, opts *conoha.ListKeypairsOptions) ([]conoha.Keypair, error) {
+	if f.ListAllKeypairsFunc == nil {
+		return nil, notImplemented("Compute.ListAllKeypairs")
+	}
+	return f.ListAllKeypairsFunc(ctx, opts)
+}
+
+// ResolveServer calls ResolveServerFunc.
+func (f *Compute) ResolveServer(ctx context.Context, nameOrID string) (*conoha.ServerDetail, error) {
+	if f.ResolveServerFunc == nil {
+		return nil, notImplemented("Compute.ResolveServer")
+	}
+	return f.ResolveServerFunc(ctx, nameOrID)
+}
+
+// ResolveFlavor calls ResolveFlavorFunc.
+func (f *Compute) ResolveFlavor(ctx context.Context, nameOrID string) (*conoha.FlavorDetail, error) {
+	if f.ResolveFlavorFunc == nil {
+		return nil, notImplemented("Compute.ResolveFlavor")
+	}
+	return f.ResolveFlavorFunc(ctx, nameOrID)
+}
+
+// LaunchServer calls LaunchServerFunc.
+func (f *Compute) LaunchServer(ctx context.Context, spec conoha.ServerSpec) (*conoha.ServerDetail, error) {
+	if f.LaunchServerFunc == nil {
+		return nil, notImplemented("Compute.LaunchServer")
+	}
+	return f.LaunchServerFunc(ctx, spec)
+}
+
+// WaitForServerStatus calls WaitForServerStatusFunc.
+func (f *Compute) WaitForServerStatus(ctx context.Context, serverID, status string, opts *conoha.WaitOptions) (*conoha.ServerDetail, error) {
+	if f.WaitForServerStatusFunc == nil {
+		return nil, notImplemented("Compute.WaitForServerStatus")
+	}
+	return f.WaitForServerStatusFunc(ctx, serverID, status, opts)
+}
+
+// WaitForServerDeleted calls WaitForServerDeletedFunc.
+func (f *Compute) WaitForServerDeleted(ctx context.Context, serverID string, opts *conoha.WaitOptions) error {
+	if f.WaitForServerDeletedFunc == nil {
+		return notImplemented("Compute.WaitForServerDeleted")
+	}
+	return f.WaitForServerDeletedFunc(ctx, serverID, opts)
+}
+
+// BlockStorage is a fake conoha.BlockStorageAPI. Each method calls the field named
+// after it with a Func suffix, or fails with ErrNotImplemented if that
+// field is nil.
+type BlockStorage struct {
+	ListVolumesFunc           func(context.Context, *conoha.ListVolumesOptions) ([]conoha.Volume, error)
+	ListVolumesDetailFunc     func(context.Context, *conoha.ListVolumesOptions) ([]conoha.Volume, error)
+	GetVolumeFunc             func(context.Context, string) (*conoha.Volume, error)
+	CreateVolumeFunc          func(context.Context, conoha.CreateVolumeRequest) (*conoha.Volume, error)
+	DeleteVolumeFunc          func(context.Context, string, bool) error
+	UpdateVolumeFunc          func(context.Context, string, string, *string) (*conoha.Volume, error)
+	SaveVolumeAsImageFunc     func(context.Context, string, string) (*conoha.VolumeImageSaveResponse, error)
+	ListVolumeTypesFunc       func(context.Context) ([]conoha.VolumeType, error)
+	GetVolumeTypeFunc         func(context.Context, string) (*conoha.VolumeType, error)
+	ListBackupsFunc           func(context.Context, *conoha.ListBackupsOptions) ([]conoha.Backup, error)
+	ListBackupsDetailFunc     func(context.Context, *conoha.ListBackupsOptions) ([]conoha.Backup, error)
+	GetBackupFunc             func(context.Context, string) (*conoha.Backup, error)
+	EnableAutoBackupFunc      func(context.Context, string, *conoha.EnableAutoBackupOptions) (*conoha.Backup, error)
+	UpdateBackupRetentionFunc func(context.Context, string, int) (*conoha.Backup, error)
+	DisableAutoBackupFunc     func(context.Context, string) error
+	RestoreBackupFunc         func(context.Context, string, string) (*conoha.BackupRestoreResponse, error)
+	EachVolumeFunc            func(context.Context, *conoha.ListVolumesOptions, func(conoha.Volume) error) error
+	ListAllVolumesFunc        func(context.Context, *conoha.ListVolumesOptions) ([]conoha.Volume, error)
+	EachBackupFunc            func(context.Context, *conoha.ListBackupsOptions, func(conoha.Backup) error) error
+	ListAllBackupsFunc        func(context.Context, *conoha.ListBackupsOptions) ([]conoha.Backup, error)
+	ResolveVolumeFunc         func(context.Context, string) (*conoha.Volume, error)
+	WaitForVolumeStatusFunc   func(context.Context, string, string, *conoha.WaitOptions) (*conoha.Volume, error)
+	WaitForVolumeDeletedFunc  func(context.Context, string, *conoha.WaitOptions) error
+	WaitForBackupFunc         func(context.Context, string, *conoha.WaitOptions) (*conoha.Backup, error)
+	WaitForBackupDeletedFunc  func(context.Context, string, *conoha.WaitOptions) error
+}
+
+// ListVolumes calls ListVolumesFunc.
+func (f *BlockStorage) ListVolumes(ctx context.Context, opts *conoha.ListVolumesOptions) ([]conoha.Volume, error) {
+	if f.ListVolumesFunc == nil {
+		return nil, notImplemented("BlockStorage.ListVolumes")
+	}
+	return f.ListVolumesFunc(ctx, opts)
+}
+
+// ListVolumesDetail calls ListVolumesDetailFunc.
+func (f *BlockStorage) ListVolumesDetail(ctx context.Context, opts *conoha.ListVolumesOptions) ([]conoha.Volume, error) {
+	if f.ListVolumesDetailFunc == nil {
+		return nil, notImplemented("BlockStorage.ListVolumesDetail")
+	}
+	return f.ListVolumesDetailFunc(ctx, opts)
+}
+
+// GetVolume calls GetVolumeFunc.
+func (f *BlockStorage) GetVolume(ctx context.Context, volumeID string) (*conoha.Volume, error) {
+	if f.GetVolumeFunc == nil {
+		return nil, notImplemented("BlockStorage.GetVolume")
+	}
+	return f.GetVolumeFunc(ctx, volumeID)
+}
+
+// CreateVolume calls CreateVolumeFunc.
+func (f *BlockStorage) CreateVolume(ctx context.Context, opts conoha.CreateVolumeRequest) (*conoha.Volume, error) {
+	if f.CreateVolumeFunc == nil {
+		return nil, notImplemented("BlockStorage.CreateVolume")
+	}
+	return f.CreateVolumeFunc(ctx, opts)
+}
+
+// DeleteVolume calls DeleteVolumeFunc.
+func (f *BlockStorage) DeleteVolume(ctx context.Context, volumeID string, force bool) error {
+	if f.DeleteVolumeFunc == nil {
+		return notImplemented("BlockStorage.DeleteVolume")
+	}
+	return f.DeleteVolumeFunc(ctx, volumeID, force)
+}
+
+// UpdateVolume calls UpdateVolumeFunc.
+func (f *BlockStorage) UpdateVolume(ctx context.Context, volumeID, name string, description *string) (*conoha.Volume, error) {
+	if f.UpdateVolumeFunc == nil {
+		return nil, notImplemented("BlockStorage.UpdateVolume")
+	}
+	return f.UpdateVolumeFunc(ctx, volumeID, name, description)
+}
+
+// SaveVolumeAsImage calls SaveVolumeAsImageFunc.
+func (f *BlockStorage) SaveVolumeAsImage(ctx context.Context, volumeID, imageName string) (*conoha.VolumeImageSaveResponse, error) {
+	if f.SaveVolumeAsImageFunc == nil {
+		return nil, notImplemented("BlockStorage.SaveVolumeAsImage")
+	}
+	return f.SaveVolumeAsImageFunc(ctx, volumeID, imageName)
+}
+
+// ListVolumeTypes calls ListVolumeTypesFunc.
+func (f *BlockStorage) ListVolumeTypes(ctx context.Context) ([]conoha.VolumeType, error) {
+	if f.ListVolumeTypesFunc == nil {
+		return nil, notImplemented("BlockStorage.ListVolumeTypes")
+	}
+	return f.ListVolumeTypesFunc(ctx)
+}
+
+// GetVolumeType calls GetVolumeTypeFunc.
+func (f *BlockStorage) GetVolumeType(ctx context.Context, volumeTypeID string) (*conoha.VolumeType, error) {
+	if f.GetVolumeTypeFunc == nil {
+		return nil, notImplemented("BlockStorage.GetVolumeType")
+	}
+	return f.GetVolumeTypeFunc(ctx, volumeTypeID)
+}
+
+// ListBackups calls ListBackupsFunc.
+func (f *BlockStorage) ListBackups(ctx context.Context, opts *conoha.ListBackupsOptions) ([]conoha.Backup, error) {
+	if f.ListBackupsFunc == nil {
+		return nil, notImplemented("BlockStorage.ListBackups")
+	}
+	return f.ListBackupsFunc(ctx, opts)
+}
+
+// ListBackupsDetail calls ListBackupsDetailFunc.
+func (f *BlockStorage) ListBackupsDetail(ctx context.Context, opts *conoha.ListBackupsOptions) ([]conoha.Backup, error) {
+	if f.ListBackupsDetailFunc == nil {
+		return nil, notImplemented("BlockStorage.ListBackupsDetail")
+	}
+	return f.ListBackupsDetailFunc(ctx, opts)
+}
+
+// GetBackup calls GetBackupFunc.
+func (f *BlockStorage) GetBackup(ctx context.Context, backupID string) (*conoha.Backup, error) {
+	if f.GetBackupFunc == nil {
+		return nil, notImplemented("BlockStorage.GetBackup")
+	}
+	return f.GetBackupFunc(ctx, backupID)
+}
+
+// EnableAutoBackup calls EnableAutoBackupFunc.
+func (f *BlockStorage) EnableAutoBackup(ctx context.Context, serverID string, opts *conoha.EnableAutoBackupOptions) (*conoha.Backup, error) {
+	if f.EnableAutoBackupFunc == nil {
+		return nil, notImplemented("BlockStorage.EnableAutoBackup")
+	}
+	return f.EnableAutoBackupFunc(ctx, serverID, opts)
+}
+
+// UpdateBackupRetention calls UpdateBackupRetentionFunc.
+func (f *BlockStorage) UpdateBackupRetention(ctx context.Context, serverID string, retention int) (*conoha.Backup, error) {
+	if f.UpdateBackupRetentionFunc == nil {
+		return nil, notImplemented("BlockStorage.UpdateBackupRetention")
+	}
+	return f.UpdateBackupRetentionFunc(ctx, serverID, retention)
+}
+
+// DisableAutoBackup calls DisableAutoBackupFunc.
+func (f *BlockStorage) DisableAutoBackup(ctx context.Context, serverID string) error {
+	if f.DisableAutoBackupFunc == nil {
+		return notImplemented("BlockStorage.DisableAutoBackup")
+	}
+	return f.DisableAutoBackupFunc(ctx, serverID)
+}
+
+// RestoreBackup calls RestoreBackupFunc.
+func (f *BlockStorage) RestoreBackup(ctx context.Context, backupID, volumeID string) (*conoha.BackupRestoreResponse, error) {
+	if f.RestoreBackupFunc == nil {
+		return nil, notImplemented("BlockStorage.RestoreBackup")
+	}
+	return f.RestoreBackupFunc(ctx, backupID, volumeID)
+}
+
+// EachVolume calls EachVolumeFunc.
+func (f *BlockStorage) EachVolume(ctx context.Context, opts *conoha.ListVolumesOptions, fn func(conoha.Volume) error) error {
+	if f.EachVolumeFunc == nil {
+		return notImplemented("BlockStorage.EachVolume")
+	}
+	return f.EachVolumeFunc(ctx, opts, fn)
+}
+
+// ListAllVolumes calls ListAllVolumesFunc.
+func (f *BlockStorage) ListAllVolumes(ctx context.Context, opts *conoha.ListVolumesOptions) ([]conoha.Volume, error) {
+	if f.ListAllVolumesFunc == nil {
+		return nil, notImplemented("BlockStorage.ListAllVolumes")
+	}
+	return f.ListAllVolumesFunc(ctx, opts)
+}
+
+// EachBackup calls EachBackupFunc.
+func (f *BlockStorage) EachBackup(ctx context.Context, opts *conoha.ListBackupsOptions, fn func(conoha.Backup) error) error {
+	if f.EachBackupFunc == nil {
+		return notImplemented("BlockStorage.EachBackup")
+	}
+	return f.EachBackupFunc(ctx, opts, fn)
+}
+
+// ListAllBackups calls ListAllBackupsFunc.
+func (f *BlockStorage) ListAllBackups(ctx context.Context, opts *conoha.ListBackupsOptions) ([]conoha.Backup, error) {
+	if f.ListAllBackupsFunc == nil {
+		return nil, notImplemented("BlockStorage.ListAllBackups")
+	}
+	return f.ListAllBackupsFunc(ctx, opts)
+}
+
+// ResolveVolume calls ResolveVolumeFunc.
+func (f *BlockStorage) ResolveVolume(ctx context.Context, nameOrID string) (*conoha.Volume, error) {
+	if f.ResolveVolumeFunc == nil {
+		return nil, notImplemented("BlockStorage.ResolveVolume")
+	}
+	return f.ResolveVolumeFunc(ctx, nameOrID)
+}
+
+// WaitForVolumeStatus calls WaitForVolumeStatusFunc.
+func (f *BlockStorage) WaitForVolumeStatus(ctx context.Context, volumeID, status string, opts *conoha.WaitOptions) (*conoha.Volume, error) {
+	if f.WaitForVolumeStatusFunc == nil {
+		return nil, notImplemented("BlockStorage.WaitForVolumeStatus")
+	}
+	return f.WaitForVolumeStatusFunc(ctx, volumeID, status, opts)
+}
+
+// WaitForVolumeDeleted calls WaitForVolumeDeletedFunc.
+func (f *BlockStorage) WaitForVolumeDeleted(ctx context.Context, volumeID string, opts *conoha.WaitOptions) error {
+	if f.WaitForVolumeDeletedFunc == nil {
+		return notImplemented("BlockStorage.WaitForVolumeDeleted")
+	}
+	return f.WaitForVolumeDeletedFunc(ctx, volumeID, opts)
+}
+
+// WaitForBackup calls WaitForBackupFunc.
+func (f *BlockStorage) WaitForBackup(ctx context.Context, backupID string, opts *conoha.WaitOptions) (*conoha.Backup, error) {
+	if f.WaitForBackupFunc == nil {
+		return nil, notImplemented("BlockStorage.WaitForBackup")
+	}
+	return f.WaitForBackupFunc(ctx, backupID, opts)
+}
+
+// WaitForBackupDeleted calls WaitForBackupDeletedFunc.
+func (f *BlockStorage) WaitForBackupDeleted(ctx context.Context, backupID string, opts *conoha.WaitOptions) error {
+	if f.WaitForBackupDeletedFunc == nil {
+		return notImplemented("BlockStorage.WaitForBackupDeleted")
+	}
+	return f.WaitForBackupDeletedFunc(ctx, backupID, opts)
+}
+
+// Image is a fake conoha.ImageAPI. Each method calls the field named
+// after it with a Func suffix, or fails with ErrNotImplemented if that
+// field is nil.
+type Image struct {
+	ListImagesFunc          func(context.Context, *conoha.ListImagesOptions) ([]conoha.Image, error)
+	GetImageFunc            func(context.Context, string) (*conoha.Image, error)
+	DeleteImageFunc         func(context.Context, string) error
+	GetImageQuotaFunc       func(context.Context) (*conoha.ImageQuota, error)
+	GetImageUsageFunc       func(context.Context) (*conoha.ImageUsage, error)
+	SetImageQuotaFunc       func(context.Context, string) (*conoha.ImageQuota, error)
+	CreateISOImageFunc      func(context.Context, string) (*conoha.Image, error)
+	UploadISOImageFunc      func(context.Context, string, io.Reader) error
+	EachImageFunc           func(context.Context, *conoha.ListImagesOptions, func(conoha.Image) error) error
+	ListAllImagesFunc       func(context.Context, *conoha.ListImagesOptions) ([]conoha.Image, error)
+	ResolveImageFunc        func(context.Context, string) (*conoha.Image, error)
+	WaitForImageActiveFunc  func(context.Context, string, *conoha.WaitOptions) (*conoha.Image, error)
+	WaitForImageDeletedFunc func(context.Context, string, *conoha.WaitOptions) error
+}
+
+// ListImages calls ListImagesFunc.
+func (f *Image) ListImages(ctx context.Context, opts *conoha.ListImagesOptions) ([]conoha.Image, error) {
+	if f.ListImagesFunc == nil {
+		return nil, notImplemented("Image.ListImages")
+	}
+	return f.ListImagesFunc(ctx, opts)
+}
+
+// GetImage calls GetImageFunc.
+func (f *Image) GetImage(ctx context.Context, imageID string) (*conoha.Image, error) {
+	if f.GetImageFunc == nil {
+		return nil, notImplemented("Image.GetImage")
+	}
+	return f.GetImageFunc(ctx, imageID)
+}
+
+// DeleteImage calls DeleteImageFunc.
+func (f *Image) DeleteImage(ctx context.Context, imageID string) error {
+	if f.DeleteImageFunc == nil {
+		return notImplemented("Image.DeleteImage")
+	}
+	return f.DeleteImageFunc(ctx, imageID)
+}
+
+// GetImageQuota calls GetImageQuotaFunc.
+func (f *Image) GetImageQuota(ctx context.Context) (*conoha.ImageQuota, error) {
+	if f.GetImageQuotaFunc == nil {
+		return nil, notImplemented("Image.GetImageQuota")
+	}
+	return f.GetImageQuotaFunc(ctx)
+}
+
+// GetImageUsage calls GetImageUsageFunc.
+func (f *Image) GetImageUsage(ctx context.Context) (*conoha.ImageUsage, error) {
+	if f.GetImageUsageFunc == nil {
+		return nil, notImplemented("Image.GetImageUsage")
+	}
+	return f.GetImageUsageFunc(ctx)
+}
+
+// SetImageQuota calls SetImageQuotaFunc.
+func (f *Image) SetImageQuota(ctx context.Context, imageSize string) (*conoha.ImageQuota, error) {
+	if f.SetImageQuotaFunc == nil {
+		return nil, notImplemented("Image.SetImageQuota")
+	}
+	return f.SetImageQuotaFunc(ctx, imageSize)
+}
+
+// CreateISOImage calls CreateISOImageFunc.
+func (f *Image) CreateISOImage(ctx context.Context, name string) (*conoha.Image, error) {
+	if f.CreateISOImageFunc == nil {
+		return nil, notImplemented("Image.CreateISOImage")
+	}
+	return f.CreateISOImageFunc(ctx, name)
+}
+
+// UploadISOImage calls UploadISOImageFunc.
+func (f *Image) UploadISOImage(ctx context.Context, imageID string, data io.Reader) error {
+	if f.UploadISOImageFunc == nil {
+		return notImplemented("Image.UploadISOImage")
+	}
+	return f.UploadISOImageFunc(ctx, imageID, data)
+}
+
+// EachImage calls EachImageFunc.
+func (f *Image) EachImage(ctx context.Context, opts *conoha.ListImagesOptions, fn func(conoha.Image) error) error {
+	if f.EachImageFunc == nil {
+		return notImplemented("Image.EachImage")
+	}
+	return f.EachImageFunc(ctx, opts, fn)
+}
+
+// ListAllImages calls ListAllImagesFunc.
+func (f *Image) ListAllImages(ctx context.Context, opts *conoha.ListImagesOptions) ([]conoha.Image, error) {
+	if f.ListAllImagesFunc == nil {
+		return nil, notImplemented("Image.ListAllImages")
+	}
+	return f.ListAllImagesFunc(ctx, opts)
+}
+
+// ResolveImage calls ResolveImageFunc.
+func (f *Image) ResolveImage(ctx context.Context, nameOrID string) (*conoha.Image, error) {
+	if f.ResolveImageFunc == nil {
+		return nil, notImplemented("Image.ResolveImage")
+	}
+	return f.ResolveImageFunc(ctx, nameOrID)
+}
+
+// WaitForImageActive calls WaitForImageActiveFunc.
+func (f *Image) WaitForImageActive(ctx context.Context, imageID string, opts *conoha.WaitOptions) (*conoha.Image, error) {
+	if f.WaitForImageActiveFunc == nil {
+		return nil, notImplemented("Image.WaitForImageActive")
+	}
+	return f.WaitForImageActiveFunc(ctx, imageID, opts)
+}
+
+// WaitForImageDeleted calls WaitForImageDeletedFunc.
+func (f *Image) WaitForImageDeleted(ctx context.Context, imageID string, opts *conoha.WaitOptions) error {
+	if f.WaitForImageDeletedFunc == nil {
+		return notImplemented("Image.WaitForImageDeleted")
+	}
+	return f.WaitForImageDeletedFunc(ctx, imageID, opts)
+}
+
+// Network is a fake conoha.NetworkAPI. Each method calls the field named
+// after it with a Func suffix, or fails with ErrNotImplemented if that
+// field is nil.
+type Network struct {
+	ListQoSPoliciesFunc         func(context.Context, *conoha.ListQoSPoliciesOptions) ([]conoha.QoSPolicy, error)
+	GetQoSPolicyFunc            func(context.Context, string) (*conoha.QoSPolicy, error)
+	ListSubnetsFunc             func(context.Context, *conoha.ListSubnetsOptions) ([]conoha.Subnet, error)
+	GetSubnetFunc               func(context.Context, string) (*conoha.Subnet, error)
+	CreateSubnetFunc            func(context.Context, string, string) (*conoha.Subnet, error)
+	DeleteSubnetFunc            func(context.Context, string) error
+	ListSecurityGroupsFunc      func(context.Context, *conoha.ListSecurityGroupsOptions) ([]conoha.SecurityGroup, error)
+	GetSecurityGroupFunc        func(context.Context, string) (*conoha.SecurityGroup, error)
+	CreateSecurityGroupFunc     func(context.Context, string, string) (*conoha.SecurityGroup, error)
+	UpdateSecurityGroupFunc     func(context.Context, string, string, string) (*conoha.SecurityGroup, error)
+	DeleteSecurityGroupFunc     func(context.Context, string) error
+	ListSecurityGroupRulesFunc  func(context.Context, *conoha.ListSecurityGroupRulesOptions) ([]conoha.SecurityGroupRule, error)
+	GetSecurityGroupRuleFunc    func(context.Context, string) (*conoha.SecurityGroupRule, error)
+	CreateSecurityGroupRuleFunc func(context.Context, conoha.CreateSecurityGroupRuleRequest) (*conoha.SecurityGroupRule, error)
+	DeleteSecurityGroupRuleFunc func(context.Context, string) error
+	ListNetworksFunc            func(context.Context, *conoha.ListNetworksOptions) ([]conoha.Network, error)
+	GetNetworkFunc              func(context.Context, string) (*conoha.Network, error)
+	CreateNetworkFunc           func(context.Context) (*conoha.Network, error)
+	DeleteNetworkFunc           func(context.Context, string) error
+	ListPortsFunc               func(context.Context, *conoha.ListPortsOptions) ([]conoha.Port, error)
+	GetPortFunc                 func(context.Context, string) (*conoha.Port, error)
+	CreatePortFunc              func(context.Context, conoha.CreatePortRequest) (*conoha.Port, error)
+	AllocateAdditionalIPFunc    func(context.Context, int, []string) (*conoha.Port, error)
+	UpdatePortFunc              func(context.Context, string, conoha.UpdatePortRequest) (*conoha.Port, error)
+	DeletePortFunc              func(context.Context, string) error
+	EachPortFunc                func(context.Context, *conoha.ListPortsOptions, func(conoha.Port) error) error
+	ListAllPortsFunc            func(context.Context, *conoha.ListPortsOptions) ([]conoha.Port, error)
+	EachSecurityGroupFunc       func(context.Context, *conoha.ListSecurityGroupsOptions, func(conoha.SecurityGroup) error) error
+	ListAllSecurityGroupsFunc   func(context.Context, *conoha.ListSecurityGroupsOptions) ([]conoha.SecurityGroup, error)
+	EachNetworkFunc             func(context.Context, *conoha.ListNetworksOptions, func(conoha.Network) error) error
+	ListAllNetworksFunc         func(context.Context, *conoha.ListNetworksOptions) ([]conoha.Network, error)
+	ResolveSecurityGroupFunc    func(context.Context, string) (*conoha.SecurityGroup, error)
+	ResolveNetworkFunc          func(context.Context, string) (*conoha.Network, error)
+}
+
+// ListQoSPolicies calls ListQoSPoliciesFunc.
+func (f *Network) ListQoSPolicies(ctx context.Context, opts *conoha.ListQoSPoliciesOptions) ([]conoha.QoSPolicy, error) {
+	if f.ListQoSPoliciesFunc == nil {
+		return nil, notImplemented("Network.ListQoSPolicies")
+	}
+	return f.ListQoSPoliciesFunc(ctx, opts)
+}
+
+// GetQoSPolicy calls GetQoSPolicyFunc.
+func (f *Network) GetQoSPolicy(ctx context.Context, policyID string) (*conoha.QoSPolicy, error) {
+	if f.GetQoSPolicyFunc == nil {
+		return nil, notImplemented("Network.GetQoSPolicy")
+	}
+	return f.GetQoSPolicyFunc(ctx, policyID)
+}
+
+// ListSubnets calls ListSubnetsFunc.
+func (f *Network) ListSubnets(ctx context.Context, opts *conoha.ListSubnetsOptions) ([]conoha.Subnet, error) {
+	if f.ListSubnetsFunc == nil {
+		return nil, notImplemented("Network.ListSubnets")
+	}
+	return f.ListSubnetsFunc(ctx, opts)
+}
+
+// GetSubnet calls GetSubnetFunc.
+func (f *Network) GetSubnet(ctx context.Context, subnetID string) (*conoha.Subnet, error) {
+	if f.GetSubnetFunc == nil {
+		return nil, notImplemented("Network.GetSubnet")
+	}
+	return f.GetSubnetFunc(ctx, subnetID)
+}
+
+// CreateSubnet calls CreateSubnetFunc.
+func (f *Network) CreateSubnet(ctx context.Context, networkID, cidr string) (*conoha.Subnet, error) {
+	if f.CreateSubnetFunc == nil {
+		return nil, notImplemented("Network.CreateSubnet")
+	}
+	return f.CreateSubnetFunc(ctx, networkID, cidr)
+}
+
+// DeleteSubnet calls DeleteSubnetFunc.
+func (f *Network) DeleteSubnet(ctx context.Context, subnetID string) error {
+	if f.DeleteSubnetFunc == nil {
+		return notImplemented("Network.DeleteSubnet")
+	}
+	return f.DeleteSubnetFunc(ctx, subnetID)
+}
+
+// ListSecurityGroups calls ListSecurityGroupsFunc.
+func (f *Network) ListSecurityGroups(ctx context.Context, opts *conoha.ListSecurityGroupsOptions) ([]conoha.SecurityGroup, error) {
+	if f.ListSecurityGroupsFunc == nil {
+		return nil, notImplemented("Network.ListSecurityGroups")
+	}
+	return f.ListSecurityGroupsFunc(ctx, opts)
+}
+
+// GetSecurityGroup calls GetSecurityGroupFunc.
+func (f *Network) GetSecurityGroup(ctx context.Context, sgID string) (*conoha.SecurityGroup, error) {
+	if f.GetSecurityGroupFunc == nil {
+		return nil, notImplemented("Network.GetSecurityGroup")
+	}
+	return f.GetSecurityGroupFunc(ctx, sgID)
+}
+
+// CreateSecurityGroup calls CreateSecurityGroupFunc.
+func (f *Network) CreateSecurityGroup(ctx context.Context, name, description string) (*conoha.SecurityGroup, error) {
+	if f.CreateSecurityGroupFunc == nil {
+		return nil, notImplemented("Network.CreateSecurityGroup")
+	}
+	return f.CreateSecurityGroupFunc(ctx, name, description)
+}
+
+// UpdateSecurityGroup calls UpdateSecurityGroupFunc.
+func (f *Network) UpdateSecurityGroup(ctx context.Context, sgID, name, description string) (*conoha.SecurityGroup, error) {
+	if f.UpdateSecurityGroupFunc == nil {
+		return nil, notImplemented("Network.UpdateSecurityGroup")
+	}
+	return f.UpdateSecurityGroupFunc(ctx, sgID, name, description)
+}
+
+// DeleteSecurityGroup calls DeleteSecurityGroupFunc.
+func (f *Network) DeleteSecurityGroup(ctx context.Context, sgID string) error {
+	if f.DeleteSecurityGroupFunc == nil {
+		return notImplemented("Network.DeleteSecurityGroup")
+	}
+	return f.DeleteSecurityGroupFunc(ctx, sgID)
+}
+
+// ListSecurityGroupRules calls ListSecurityGroupRulesFunc.
+func (f *Network) ListSecurityGroupRules(ctx context.Context, opts *conoha.ListSecurityGroupRulesOptions) ([]conoha.SecurityGroupRule, error) {
+	if f.ListSecurityGroupRulesFunc == nil {
+		return nil, notImplemented("Network.ListSecurityGroupRules")
+	}
+	return f.ListSecurityGroupRulesFunc(ctx, opts)
+}
+
+// GetSecurityGroupRule calls GetSecurityGroupRuleFunc.
+func (f *Network) GetSecurityGroupRule(ctx context.Context, ruleID string) (*conoha.SecurityGroupRule, error) {
+	if f.GetSecurityGroupRuleFunc == nil {
+		return nil, notImplemented("Network.GetSecurityGroupRule")
+	}
+	return f.GetSecurityGroupRuleFunc(ctx, ruleID)
+}
+
+// CreateSecurityGroupRule calls CreateSecurityGroupRuleFunc.
+func (f *Network) CreateSecurityGroupRule(ctx context.Context, opts conoha.CreateSecurityGroupRuleRequest) (*conoha.SecurityGroupRule, error) {
+	if f.CreateSecurityGroupRuleFunc == nil {
+		return nil, notImplemented("Network.CreateSecurityGroupRule")
+	}
+	return f.CreateSecurityGroupRuleFunc(ctx, opts)
+}
+
+// DeleteSecurityGroupRule calls DeleteSecurityGroupRuleFunc.
+func (f *Network) DeleteSecurityGroupRule(ctx context.Context, ruleID string) error {
+	if f.DeleteSecurityGroupRuleFunc == nil {
+		return notImplemented("Network.DeleteSecurityGroupRule")
+	}
+	return f.DeleteSecurityGroupRuleFunc(ctx, ruleID)
+}
+
+// ListNetworks calls ListNetworksFunc.
+func (f *Network) ListNetworks(ctx context.Context, opts *conoha.ListNetworksOptions) ([]conoha.Network, error) {
+	if f.ListNetworksFunc == nil {
+		return nil, notImplemented("Network.ListNetworks")
+	}
+	return f.ListNetworksFunc(ctx, opts)
+}
+
+// GetNetwork calls GetNetworkFunc.
+func (f *Network) GetNetwork(ctx context.Context, networkID string) (*conoha.Network, error) {
+	if f.GetNetworkFunc == nil {
+		return nil, notImplemented("Network.GetNetwork")
+	}
+	return f.GetNetworkFunc(ctx, networkID)
+}
+
+// CreateNetwork calls CreateNetworkFunc.
+func (f *Network) CreateNetwork(ctx context.Context) (*conoha.Network, error) {
+	if f.CreateNetworkFunc == nil {
+		return nil, notImplemented("Network.CreateNetwork")
+	}
+	return f.CreateNetworkFunc(ctx)
+}
+
+// DeleteNetwork calls DeleteNetworkFunc.
+func (f *Network) DeleteNetwork(ctx context.Context, networkID string) error {
+	if f.DeleteNetworkFunc == nil {
+		return notImplemented("Network.DeleteNetwork")
+	}
+	return f.DeleteNetworkFunc(ctx, networkID)
+}
+
+// ListPorts calls ListPortsFunc.
+func (f *Network) ListPorts(ctx context.Context, opts *conoha.ListPortsOptions) ([]conoha.Port, error) {
+	if f.ListPortsFunc == nil {
+		return nil, notImplemented("Network.ListPorts")
+	}
+	return f.ListPortsFunc(ctx, opts)
+}
+
+// GetPort calls GetPortFunc.
+func (f *Network) GetPort(ctx context.Context, portID string) (*conoha.Port, error) {
+	if f.GetPortFunc == nil {
+		return nil, notImplemented("Network.GetPort")
+	}
+	return f.GetPortFunc(ctx, portID)
+}
+
+// CreatePort calls CreatePortFunc.
+func (f *Network) CreatePort(ctx context.Context, opts conoha.CreatePortRequest) (*conoha.Port, error) {
+	if f.CreatePortFunc == nil {
+		return nil, notImplemented("Network.CreatePort")
+	}
+	return f.CreatePortFunc(ctx, opts)
+}
+
+// AllocateAdditionalIP calls AllocateAdditionalIPFunc.
+func (f *Network) AllocateAdditionalIP(ctx context.Context, count int, securityGroups []string) (*conoha.Port, error) {
+	if f.AllocateAdditionalIPFunc == nil {
+		return nil, notImplemented("Network.AllocateAdditionalIP")
+	}
+	return f.AllocateAdditionalIPFunc(ctx, count, securityGroups)
+}
+
+// UpdatePort calls UpdatePortFunc.
+func (f *Network) UpdatePort(ctx context.Context, portID string, opts conoha.UpdatePortRequest) (*conoha.Port, error) {
+	if f.UpdatePortFunc == nil {
+		return nil, notImplemented("Network.UpdatePort")
+	}
+	return f.UpdatePortFunc(ctx, portID, opts)
+}
+
+// DeletePort calls DeletePortFunc.
+func (f *Network) DeletePort(ctx context.Context, portID string) error {
+	if f.DeletePortFunc == nil {
+		return notImplemented("Network.DeletePort")
+	}
+	return f.DeletePortFunc(ctx, portID)
+}
+
+// EachPort calls EachPortFunc.
+func (f *Network) EachPort(ctx context.Context, opts *conoha.ListPortsOptions, fn func(conoha.Port) error) error {
+	if f.EachPortFunc == nil {
+		return notImplemented("Network.EachPort")
+	}
+	return f.EachPortFunc(ctx, opts, fn)
+}
+
+// ListAllPorts calls ListAllPortsFunc.
+func (f *Network) ListAllPorts(ctx context.Context, opts *conoha.ListPortsOptions) ([]conoha.Port, error) {
+	if f.ListAllPortsFunc == nil {
+		return nil, notImplemented("Network.ListAllPorts")
+	}
+	return f.ListAllPortsFunc(ctx, opts)
+}
+
+// EachSecurityGroup calls EachSecurityGroupFunc.
+func (f *Network) EachSecurityGroup(ctx context.Context, opts *conoha.ListSecurityGroupsOptions, fn func(conoha.SecurityGroup) error) error {
+	if f.EachSecurityGroupFunc == nil {
+		return notImplemented("Network.EachSecurityGroup")
+	}
+	return f.EachSecurityGroupFunc(ctx, opts, fn)
+}
+
+// ListAllSecurityGroups calls ListAllSecurityGroupsFunc.
+func (f *Network) ListAllSecurityGroups(ctx context.Context, opts *conoha.ListSecurityGroupsOptions) ([]conoha.SecurityGroup, error) {
+	if f.ListAllSecurityGroupsFunc == nil {
+		return nil, notImplemented("Network.ListAllSecurityGroups")
+	}
+	return f.ListAllSecurityGroupsFunc(ctx, opts)
+}
+
+// EachNetwork calls EachNetworkFunc.
+func (f *Network) EachNetwork(ctx context.Context, opts *conoha.ListNetworksOptions, fn func(conoha.Network) error) error {
+	if f.EachNetworkFunc == nil {
+		return notImplemented("Network.EachNetwork")
+	}
+	return f.EachNetworkFunc(ctx, opts, fn)
+}
+
+// ListAllNetworks calls ListAllNetworksFunc.
+func (f *Network) ListAllNetworks(ctx context.Context, opts *conoha.ListNetworksOptions) ([]conoha.Network, error) {
+	if f.ListAllNetworksFunc == nil {
+		return nil, notImplemented("Network.ListAllNetworks")
+	}
+	return f.ListAllNetworksFunc(ctx, opts)
+}
+
+// ResolveSecurityGroup calls ResolveSecurityGroupFunc.
+func (f *Network) ResolveSecurityGroup(ctx context.Context, nameOrID string) (*conoha.SecurityGroup, error) {
+	if f.ResolveSecurityGroupFunc == nil {
+		return nil, notImplemented("Network.ResolveSecurityGroup")
+	}
+	return f.ResolveSecurityGroupFunc(ctx, nameOrID)
+}
+
+// ResolveNetwork calls ResolveNetworkFunc.
+func (f *Network) ResolveNetwork(ctx context.Context, nameOrID string) (*conoha.Network, error) {
+	if f.ResolveNetworkFunc == nil {
+		return nil, notImplemented("Network.ResolveNetwork")
+	}
+	return f.ResolveNetworkFunc(ctx, nameOrID)
+}
+
+// LoadBalancer is a fake conoha.LoadBalancerAPI. Each method calls the field named
+// after it with a Func suffix, or fails with ErrNotImplemented if that
+// field is nil.
+type LoadBalancer struct {
+	ListLoadBalancersFunc          func(context.Context) ([]conoha.LoadBalancer, error)
+	GetLoadBalancerFunc            func(context.Context, string) (*conoha.LoadBalancer, error)
+	CreateLoadBalancerFunc         func(context.Context, string) (*conoha.LoadBalancer, error)
+	UpdateLoadBalancerFunc         func(context.Context, string, string) (*conoha.LoadBalancer, error)
+	DeleteLoadBalancerFunc         func(context.Context, string) error
+	ListListenersFunc              func(context.Context) ([]conoha.Listener, error)
+	GetListenerFunc                func(context.Context, string) (*conoha.Listener, error)
+	CreateListenerFunc             func(context.Context, string, string, int, string) (*conoha.Listener, error)
+	UpdateListenerFunc             func(context.Context, string, string) (*conoha.Listener, error)
+	DeleteListenerFunc             func(context.Context, string) error
+	ListPoolsFunc                  func(context.Context) ([]conoha.Pool, error)
+	GetPoolFunc                    func(context.Context, string) (*conoha.Pool, error)
+	CreatePoolFunc                 func(context.Context, string, string, string, string) (*conoha.Pool, error)
+	UpdatePoolFunc                 func(context.Context, string, string, string) (*conoha.Pool, error)
+	DeletePoolFunc                 func(context.Context, string) error
+	ListMembersFunc                func(context.Context, string) ([]conoha.Member, error)
+	GetMemberFunc                  func(context.Context, string, string) (*conoha.Member, error)
+	AddMemberFunc                  func(context.Context, string, string, string, int) (*conoha.Member, error)
+	UpdateMemberFunc               func(context.Context, string, string, bool) (*conoha.Member, error)
+	DeleteMemberFunc               func(context.Context, string, string) error
+	ListHealthMonitorsFunc         func(context.Context) ([]conoha.HealthMonitor, error)
+	GetHealthMonitorFunc           func(context.Context, string) (*conoha.HealthMonitor, error)
+	CreateHealthMonitorFunc        func(context.Context, conoha.CreateHealthMonitorRequest) (*conoha.HealthMonitor, error)
+	UpdateHealthMonitorFunc        func(context.Context, string, string) (*conoha.HealthMonitor, error)
+	DeleteHealthMonitorFunc        func(context.Context, string) error
+	WaitForLoadBalancerActiveFunc  func(context.Context, string, *conoha.WaitOptions) (*conoha.LoadBalancer, error)
+	WaitForLoadBalancerDeletedFunc func(context.Context, string, *conoha.WaitOptions) error
+}
+
+// ListLoadBalancers calls ListLoadBalancersFunc.
+func (f *LoadBalancer) ListLoadBalancers(ctx context.Context) ([]conoha.LoadBalancer, error) {
+	if f.ListLoadBalancersFunc == nil {
+		return nil, notImplemented("LoadBalancer.ListLoadBalancers")
+	}
+	return f.ListLoadBalancersFunc(ctx)
+}
+
+// GetLoadBalancer calls GetLoadBalancerFunc.
+func (f *LoadBalancer) GetLoadBalancer(ctx context.Context, lbID string) (*conoha.LoadBalancer, error) {
+	if f.GetLoadBalancerFunc == nil {
+		return nil, notImplemented("LoadBalancer.GetLoadBalancer")
+	}
+	return f.GetLoadBalancerFunc(ctx, lbID)
+}
+
+// CreateLoadBalancer calls CreateLoadBalancerFunc.
+func (f *LoadBalancer) CreateLoadBalancer(ctx context.Context, name string) (*conoha.LoadBalancer, error) {
+	if f.CreateLoadBalancerFunc == nil {
+		return nil, notImplemented("LoadBalancer.CreateLoadBalancer")
+	}
+	return f.CreateLoadBalancerFunc(ctx, name)
+}
+
+// UpdateLoadBalancer calls UpdateLoadBalancerFunc.
+func (f *LoadBalancer) UpdateLoadBalancer(ctx context.Context, lbID, name string) (*conoha.LoadBalancer, error) {
+	if f.UpdateLoadBalancerFunc == nil {
+		return nil, notImplemented("LoadBalancer.UpdateLoadBalancer")
+	}
+	return f.UpdateLoadBalancerFunc(ctx, lbID, name)
+}
+
+// DeleteLoadBalancer calls DeleteLoadBalancerFunc.
+func (f *LoadBalancer) DeleteLoadBalancer(ctx context.Context, lbID string) error {
+	if f.DeleteLoadBalancerFunc == nil {
+		return notImplemented("LoadBalancer.DeleteLoadBalancer")
+	}
+	return f.DeleteLoadBalancerFunc(ctx, lbID)
+}
+
+// ListListeners calls ListListenersFunc.
+func (f *LoadBalancer) ListListeners(ctx context.Context) ([]conoha.Listener, error) {
+	if f.ListListenersFunc == nil {
+		return nil, notImplemented("LoadBalancer.ListListeners")
+	}
+	return f.ListListenersFunc(ctx)
+}
+
+// GetListener calls GetListenerFunc.
+func (f *LoadBalancer) GetListener(ctx context.Context, listenerID string) (*conoha.Listener, error) {
+	if f.GetListenerFunc == nil {
+		return nil, notImplemented("LoadBalancer.GetListener")
+	}
+	return f.GetListenerFunc(ctx, listenerID)
+}
+
+// CreateListener calls CreateListenerFunc.
+func (f *LoadBalancer) CreateListener(ctx context.Context, name, protocol string, port int, lbID string) (*conoha.Listener, error) {
+	if f.CreateListenerFunc == nil {
+		return nil, notImplemented("LoadBalancer.CreateListener")
+	}
+	return f.CreateListenerFunc(ctx, name, protocol, port, lbID)
+}
+
+// UpdateListener calls UpdateListenerFunc.
+func (f *LoadBalancer) UpdateListener(ctx context.Context, listenerID, name string) (*conoha.Listener, error) {
+	if f.UpdateListenerFunc == nil {
+		return nil, notImplemented("LoadBalancer.UpdateListener")
+	}
+	return f.UpdateListenerFunc(ctx, listenerID, name)
+}
+
+// DeleteListener calls DeleteListenerFunc.
+func (f *LoadBalancer) DeleteListener(ctx context.Context, listenerID string) error {
+	if f.DeleteListenerFunc == nil {
+		return notImplemented("LoadBalancer.DeleteListener")
+	}
+	return f.DeleteListenerFunc(ctx, listenerID)
+}
+
+// ListPools calls ListPoolsFunc.
+func (f *LoadBalancer) ListPools(ctx context.Context) ([]conoha.Pool, error) {
+	if f.ListPoolsFunc == nil {
+		return nil, notImplemented("LoadBalancer.ListPools")
+	}
+	return f.ListPoolsFunc(ctx)
+}
+
+// GetPool calls GetPoolFunc.
+func (f *LoadBalancer) GetPool(ctx context.Context, poolID string) (*conoha.Pool, error) {
+	if f.GetPoolFunc == nil {
+		return nil, notImplemented("LoadBalancer.GetPool")
+	}
+	return f.GetPoolFunc(ctx, poolID)
+}
+
+// CreatePool calls CreatePoolFunc.
+func (f *LoadBalancer) CreatePool(ctx context.Context, name, protocol, lbAlgorithm, listenerID string) (*conoha.Pool, error) {
+	if f.CreatePoolFunc == nil {
+		return nil, notImplemented("LoadBalancer.CreatePool")
+	}
+	return f.CreatePoolFunc(ctx, name, protocol, lbAlgorithm, listenerID)
+}
+
+// UpdatePool calls UpdatePoolFunc.
+func (f *LoadBalancer) UpdatePool(ctx context.Context, poolID string, name, lbAlgorithm string) (*conoha.Pool, error) {
+	if f.UpdatePoolFunc == nil {
+		return nil, notImplemented("LoadBalancer.UpdatePool")
+	}
+	return f.UpdatePoolFunc(ctx, poolID, name, lbAlgorithm)
+}
+
+// DeletePool calls DeletePoolFunc.
+func (f *LoadBalancer) DeletePool(ctx context.Context, poolID string) error {
+	if f.DeletePoolFunc == nil {
+		return notImplemented("LoadBalancer.DeletePool")
+	}
+	return f.DeletePoolFunc(ctx, poolID)
+}
+
+// ListMembers calls ListMembersFunc.
+func (f *LoadBalancer) ListMembers(ctx context.Context, poolID string) ([]conoha.Member, error) {
+	if f.ListMembersFunc == nil {
+		return nil, notImplemented("LoadBalancer.ListMembers")
+	}
+	return f.ListMembersFunc(ctx, poolID)
+}
+
+// GetMember calls GetMemberFunc.
+func (f *LoadBalancer) GetMember(ctx context.Context, poolID, memberID string) (*conoha.Member, error) {
+	if f.GetMemberFunc == nil {
+		return nil, notImplemented("LoadBalancer.GetMember")
+	}
+	return f.GetMemberFunc(ctx, poolID, memberID)
+}
+
+// AddMember calls AddMemberFunc.
+func (f *LoadBalancer) AddMember(ctx context.Context, poolID, name, address string, port int) (*conoha.Member, error) {
+	if f.AddMemberFunc == nil {
+		return nil, notImplemented("LoadBalancer.AddMember")
+	}
+	return f.AddMemberFunc(ctx, poolID, name, address, port)
+}
+
+// UpdateMember calls UpdateMemberFunc.
+func (f *LoadBalancer) UpdateMember(ctx context.Context, poolID, memberID string, adminStateUp bool) (*conoha.Member, error) {
+	if f.UpdateMemberFunc == nil {
+		return nil, notImplemented("LoadBalancer.UpdateMember")
+	}
+	return f.UpdateMemberFunc(ctx, poolID, memberID, adminStateUp)
+}
+
+// DeleteMember calls DeleteMemberFunc.
+func (f *LoadBalancer) DeleteMember(ctx context.Context, poolID, memberID string) error {
+	if f.DeleteMemberFunc == nil {
+		return notImplemented("LoadBalancer.DeleteMember")
+	}
+	return f.DeleteMemberFunc(ctx, poolID, memberID)
+}
+
+// ListHealthMonitors calls ListHealthMonitorsFunc.
+func (f *LoadBalancer) ListHealthMonitors(ctx context.Context) ([]conoha.HealthMonitor, error) {
+	if f.ListHealthMonitorsFunc == nil {
+		return nil, notImplemented("LoadBalancer.ListHealthMonitors")
+	}
+	return f.ListHealthMonitorsFunc(ctx)
+}
+
+// GetHealthMonitor calls GetHealthMonitorFunc.
+func (f *LoadBalancer) GetHealthMonitor(ctx context.Context, hmID string) (*conoha.HealthMonitor, error) {
+	if f.GetHealthMonitorFunc == nil {
+		return nil, notImplemented("LoadBalancer.GetHealthMonitor")
+	}
+	return f.GetHealthMonitorFunc(ctx, hmID)
+}
+
+// CreateHealthMonitor calls CreateHealthMonitorFunc.
+func (f *LoadBalancer) CreateHealthMonitor(ctx context.Context, opts conoha.CreateHealthMonitorRequest) (*conoha.HealthMonitor, error) {
+	if f.CreateHealthMonitorFunc == nil {
+		return nil, notImplemented("LoadBalancer.CreateHealthMonitor")
+	}
+	return f.CreateHealthMonitorFunc(ctx, opts)
+}
+
+// UpdateHealthMonitor calls UpdateHealthMonitorFunc.
+func (f *LoadBalancer) UpdateHealthMonitor(ctx context.Context, hmID, name string) (*conoha.HealthMonitor, error) {
+	if f.UpdateHealthMonitorFunc == nil {
+		return nil, notImplemented("LoadBalancer.UpdateHealthMonitor")
+	}
+	return f.UpdateHealthMonitorFunc(ctx, hmID, name)
+}
+
+// DeleteHealthMonitor calls DeleteHealthMonitorFunc.
+func (f *LoadBalancer) DeleteHealthMonitor(ctx context.Context, hmID string) error {
+	if f.DeleteHealthMonitorFunc == nil {
+		return notImplemented("LoadBalancer.DeleteHealthMonitor")
+	}
+	return f.DeleteHealthMonitorFunc(ctx, hmID)
+}
+
+// WaitForLoadBalancerActive calls WaitForLoadBalancerActiveFunc.
+func (f *LoadBalancer) WaitForLoadBalancerActive(ctx context.Context, lbID string, opts *conoha.WaitOptions) (*conoha.LoadBalancer, error) {
+	if f.WaitForLoadBalancerActiveFunc == nil {
+		return nil, notImplemented("LoadBalancer.WaitForLoadBalancerActive")
+	}
+	return f.WaitForLoadBalancerActiveFunc(ctx, lbID, opts)
+}
+
+// WaitForLoadBalancerDeleted calls WaitForLoadBalancerDeletedFunc.
+func (f *LoadBalancer) WaitForLoadBalancerDeleted(ctx context.Context, lbID string, opts *conoha.WaitOptions) error {
+	if f.WaitForLoadBalancerDeletedFunc == nil {
+		return notImplemented("LoadBalancer.WaitForLoadBalancerDeleted")
+	}
+	return f.WaitForLoadBalancerDeletedFunc(ctx, lbID, opts)
+}
+
+// ObjectStorage is a fake conoha.ObjectStorageAPI. Each method calls the field named
+// after it with a Func suffix, or fails with ErrNotImplemented if that
+// field is nil.
+type ObjectStorage struct {
+	GetAccountInfoFunc              func(context.Context) (*conoha.AccountInfo, error)
+	SetAccountQuotaFunc             func(context.Context, string) error
+	ListContainersFunc              func(context.Context) ([]conoha.Container, error)
+	CreateContainerFunc             func(context.Context, string) error
+	DeleteContainerFunc             func(context.Context, string) error
+	GetContainerInfoFunc            func(context.Context, string) (*conoha.ContainerInfo, error)
+	ListObjectsFunc                 func(context.Context, string, *conoha.ListObjectsOptions) ([]conoha.Object, error)
+	UploadObjectFunc                func(context.Context, string, string, io.Reader) error
+	DownloadObjectFunc              func(context.Context, string, string) (io.ReadCloser, error)
+	DeleteObjectFunc                func(context.Context, string, string) error
+	GetObjectInfoFunc               func(context.Context, string, string) (*conoha.ObjectInfo, error)
+	CopyObjectFunc                  func(context.Context, string, string, string, string) error
+	ScheduleObjectDeletionFunc      func(context.Context, string, string, int64) error
+	ScheduleObjectDeletionAfterFunc func(context.Context, string, string, int64) error
+	EnableVersioningFunc            func(context.Context, string, string) error
+	DisableVersioningFunc           func(context.Context, string) error
+	EnableWebPublishingFunc         func(context.Context, string) error
+	DisableWebPublishingFunc        func(context.Context, string) error
+	SetTempURLKeyFunc               func(context.Context, string) error
+	RemoveTempURLKeyFunc            func(context.Context) error
+	GenerateTempURLFunc             func(string, string, string, string, int64) (string, error)
+	CreateDLOManifestFunc           func(context.Context, string, string, string, string) error
+	CreateSLOManifestFunc           func(context.Context, string, string, []conoha.SLOSegment) error
+	EachObjectFunc                  func(context.Context, string, *conoha.ListObjectsOptions, func(conoha.Object) error) error
+	ListAllObjectsFunc              func(context.Context, string, *conoha.ListObjectsOptions) ([]conoha.Object, error)
+}
+
+// GetAccountInfo calls GetAccountInfoFunc.
+func (f *ObjectStorage) GetAccountInfo(ctx context.Context) (*conoha.AccountInfo, error) {
+	if f.GetAccountInfoFunc == nil {
+		return nil, notImplemented("ObjectStorage.GetAccountInfo")
+	}
+	return f.GetAccountInfoFunc(ctx)
+}
+
+// SetAccountQuota calls SetAccountQuotaFunc.
+func (f *ObjectStorage) SetAccountQuota(ctx context.Context, gigaBytes string) error {
+	if f.SetAccountQuotaFunc == nil {
+		return notImplemented("ObjectStorage.SetAccountQuota")
+	}
+	return f.SetAccountQuotaFunc(ctx, gigaBytes)
+}
+
+// ListContainers calls ListContainersFunc.
+func (f *ObjectStorage) ListContainers(ctx context.Context) ([]conoha.Container, error) {
+	if f.ListContainersFunc == nil {
+		return nil, notImplemented("ObjectStorage.ListContainers")
+	}
+	return f.ListContainersFunc(ctx)
+}
+
+// CreateContainer calls CreateContainerFunc.
+func (f *ObjectStorage) CreateContainer(ctx context.Context, name string) error {
+	if f.CreateContainerFunc == nil {
+		return notImplemented("ObjectStorage.CreateContainer")
+	}
+	return f.CreateContainerFunc(ctx, name)
+}
+
+// DeleteContainer calls DeleteContainerFunc.
+func (f *ObjectStorage) DeleteContainer(ctx context.Context, name string) error {
+	if f.DeleteContainerFunc == nil {
+		return notImplemented("ObjectStorage.DeleteContainer")
+	}
+	return f.DeleteContainerFunc(ctx, name)
+}
+
+// GetContainerInfo calls GetContainerInfoFunc.
+func (f *ObjectStorage) GetContainerInfo(ctx context.Context, name string) (*conoha.ContainerInfo, error) {
+	if f.GetContainerInfoFunc == nil {
+		return nil, notImplemented("ObjectStorage.GetContainerInfo")
+	}
+	return f.GetContainerInfoFunc(ctx, name)
+}
+
+// ListObjects calls ListObjectsFunc.
+func (f *ObjectStorage) ListObjects(ctx context.Context, container string, opts *conoha.ListObjectsOptions) ([]conoha.Object, error) {
+	if f.ListObjectsFunc == nil {
+		return nil, notImplemented("ObjectStorage.ListObjects")
+	}
+	return f.ListObjectsFunc(ctx, container, opts)
+}
+
+// UploadObject calls UploadObjectFunc.
+func (f *ObjectStorage) UploadObject(ctx context.Context, container, objectName string, data io.Reader) error {
+	if f.UploadObjectFunc == nil {
+		return notImplemented("ObjectStorage.UploadObject")
+	}
+	return f.UploadObjectFunc(ctx, container, objectName, data)
+}
+
+// DownloadObject calls DownloadObjectFunc.
+func (f *ObjectStorage) DownloadObject(ctx context.Context, container, objectName string) (io.ReadCloser, error) {
+	if f.DownloadObjectFunc == nil {
+		return nil, notImplemented("ObjectStorage.DownloadObject")
+	}
+	return f.DownloadObjectFunc(ctx, container, objectName)
+}
+
+// DeleteObject calls DeleteObjectFunc.
+func (f *ObjectStorage) DeleteObject(ctx context.Context, container, objectName string) error {
+	if f.DeleteObjectFunc == nil {
+		return notImplemented("ObjectStorage.DeleteObject")
+	}
+	return f.DeleteObjectFunc(ctx, container, objectName)
+}
+
+// GetObjectInfo calls GetObjectInfoFunc.
+func (f *ObjectStorage) GetObjectInfo(ctx context.Context, container, objectName string) (*conoha.ObjectInfo, error) {
+	if f.GetObjectInfoFunc == nil {
+		return nil, notImplemented("ObjectStorage.GetObjectInfo")
+	}
+	return f.GetObjectInfoFunc(ctx, container, objectName)
+}
+
+// CopyObject calls CopyObjectFunc.
+func (f *ObjectStorage) CopyObject(ctx context.Context, srcContainer, srcObject, dstContainer, dstObject string) error {
+	if f.CopyObjectFunc == nil {
+		return notImplemented("ObjectStorage.CopyObject")
+	}
+	return f.CopyObjectFunc(ctx, srcContainer, srcObject, dstContainer, dstObject)
+}
+
+// ScheduleObjectDeletion calls ScheduleObjectDeletionFunc.
+func (f *ObjectStorage) ScheduleObjectDeletion(ctx context.Context, container, objectName string, deleteAt int64) error {
+	if f.ScheduleObjectDeletionFunc == nil {
+		return notImplemented("ObjectStorage.ScheduleObjectDeletion")
+	}
+	return f.ScheduleObjectDeletionFunc(ctx, container, objectName, deleteAt)
+}
+
+// ScheduleObjectDeletionAfter calls ScheduleObjectDeletionAfterFunc.
+func (f *ObjectStorage) ScheduleObjectDeletionAfter(ctx context.Context, container, objectName string, deleteAfterSeconds int64) error {
+	if f.ScheduleObjectDeletionAfterFunc == nil {
+		return notImplemented("ObjectStorage.ScheduleObjectDeletionAfter")
+	}
+	return f.ScheduleObjectDeletionAfterFunc(ctx, container, objectName, deleteAfterSeconds)
+}
+
+// EnableVersioning calls EnableVersioningFunc.
+func (f *ObjectStorage) EnableVersioning(ctx context.Context, container, versionsContainer string) error {
+	if f.EnableVersioningFunc == nil {
+		return notImplemented("ObjectStorage.EnableVersioning")
+	}
+	return f.EnableVersioningFunc(ctx, container, versionsContainer)
+}
+
+// DisableVersioning calls DisableVersioningFunc.
+func (f *ObjectStorage) DisableVersioning(ctx context.Context, container string) error {
+	if f.DisableVersioningFunc == nil {
+		return notImplemented("ObjectStorage.DisableVersioning")
+	}
+	return f.DisableVersioningFunc(ctx, container)
+}
+
+// EnableWebPublishing calls EnableWebPublishingFunc.
+func (f *ObjectStorage) EnableWebPublishing(ctx context.Context, container string) error {
+	if f.EnableWebPublishingFunc == nil {
+		return notImplemented("ObjectStorage.EnableWebPublishing")
+	}
+	return f.EnableWebPublishingFunc(ctx, container)
+}
+
+// DisableWebPublishing calls DisableWebPublishingFunc.
+func (f *ObjectStorage) DisableWebPublishing(ctx context.Context, container string) error {
+	if f.DisableWebPublishingFunc == nil {
+		return notImplemented("ObjectStorage.DisableWebPublishing")
+	}
+	return f.DisableWebPublishingFunc(ctx, container)
+}
+
+// SetTempURLKey calls SetTempURLKeyFunc.
+func (f *ObjectStorage) SetTempURLKey(ctx context.Context, key string) error {
+	if f.SetTempURLKeyFunc == nil {
+		return notImplemented("ObjectStorage.SetTempURLKey")
+	}
+	return f.SetTempURLKeyFunc(ctx, key)
+}
+
+// RemoveTempURLKey calls RemoveTempURLKeyFunc.
+func (f *ObjectStorage) RemoveTempURLKey(ctx context.Context) error {
+	if f.RemoveTempURLKeyFunc == nil {
+		return notImplemented("ObjectStorage.RemoveTempURLKey")
+	}
+	return f.RemoveTempURLKeyFunc(ctx)
+}
+
+// GenerateTempURL calls GenerateTempURLFunc.
+func (f *ObjectStorage) GenerateTempURL(method, container, objectName, key string, expires int64) (string, error) {
+	if f.GenerateTempURLFunc == nil {
+		return "", notImplemented("ObjectStorage.GenerateTempURL")
+	}
+	return f.GenerateTempURLFunc(method, container, objectName, key, expires)
+}
+
+// CreateDLOManifest calls CreateDLOManifestFunc.
+func (f *ObjectStorage) CreateDLOManifest(ctx context.Context, container, manifestName, segmentContainer, segmentPrefix string) error {
+	if f.CreateDLOManifestFunc == nil {
+		return notImplemented("ObjectStorage.CreateDLOManifest")
+	}
+	return f.CreateDLOManifestFunc(ctx, container, manifestName, segmentContainer, segmentPrefix)
+}
+
+// CreateSLOManifest calls CreateSLOManifestFunc.
+func (f *ObjectStorage) CreateSLOManifest(ctx context.Context, container, manifestName string, segments []conoha.SLOSegment) error {
+	if f.CreateSLOManifestFunc == nil {
+		return notImplemented("ObjectStorage.CreateSLOManifest")
+	}
+	return f.CreateSLOManifestFunc(ctx, container, manifestName, segments)
+}
+
+// EachObject calls EachObjectFunc.
+func (f *ObjectStorage) EachObject(ctx context.Context, container string, opts *conoha.ListObjectsOptions, fn func(conoha.Object) error) error {
+	if f.EachObjectFunc == nil {
+		return notImplemented("ObjectStorage.EachObject")
+	}
+	return f.EachObjectFunc(ctx, container, opts, fn)
+}
+
+// ListAllObjects calls ListAllObjectsFunc.
+func (f *ObjectStorage) ListAllObjects(ctx context.Context, container string, opts *conoha.ListObjectsOptions) ([]conoha.Object, error) {
+	if f.ListAllObjectsFunc == nil {
+		return nil, notImplemented("ObjectStorage.ListAllObjects")
+	}
+	return f.ListAllObjectsFunc(ctx, container, opts)
+}
+
+// DNS is a fake conoha.DNSAPI. Each method calls the field named
+// after it with a Func suffix, or fails with ErrNotImplemented if that
+// field is nil.
+type DNS struct {
+	ListDomainsFunc       func(context.Context, *conoha.ListDomainsOptions) ([]conoha.Domain, error)
+	GetDomainFunc         func(context.Context, string) (*conoha.Domain, error)
+	CreateDomainFunc      func(context.Context, conoha.CreateDomainRequest) (*conoha.Domain, error)
+	UpdateDomainFunc      func(context.Context, string, conoha.UpdateDomainRequest) (*conoha.Domain, error)
+	DeleteDomainFunc      func(context.Context, string) error
+	ListDNSRecordsFunc    func(context.Context, string, *conoha.ListDNSRecordsOptions) ([]conoha.DNSRecord, error)
+	GetDNSRecordFunc      func(context.Context, string, string) (*conoha.DNSRecord, error)
+	CreateDNSRecordFunc   func(context.Context, string, conoha.CreateDNSRecordRequest) (*conoha.DNSRecord, error)
+	UpdateDNSRecordFunc   func(context.Context, string, string, conoha.UpdateDNSRecordRequest) (*conoha.DNSRecord, error)
+	DeleteDNSRecordFunc   func(context.Context, string, string) error
+	EachDomainFunc        func(context.Context, *conoha.ListDomainsOptions, func(conoha.Domain) error) error
+	ListAllDomainsFunc    func(context.Context, *conoha.ListDomainsOptions) ([]conoha.Domain, error)
+	EachDNSRecordFunc     func(context.Context, string, *conoha.ListDNSRecordsOptions, func(conoha.DNSRecord) error) error
+	ListAllDNSRecordsFunc func(context.Context, string, *conoha.ListDNSRecordsOptions) ([]conoha.DNSRecord, error)
+	ResolveDomainFunc     func(context.Context, string) (*conoha.Domain, error)
+}
+
+// ListDomains calls ListDomainsFunc.
+func (f *DNS) ListDomains(ctx context.Context, opts *conoha.ListDomainsOptions) ([]conoha.Domain, error) {
+	if f.ListDomainsFunc == nil {
+		return nil, notImplemented("DNS.ListDomains")
+	}
+	return f.ListDomainsFunc(ctx, opts)
+}
+
+// GetDomain calls GetDomainFunc.
+func (f *DNS) GetDomain(ctx context.Context, domainID string) (*conoha.Domain, error) {
+	if f.GetDomainFunc == nil {
+		return nil, notImplemented("DNS.GetDomain")
+	}
+	return f.GetDomainFunc(ctx, domainID)
+}
+
+// CreateDomain calls CreateDomainFunc.
+func (f *DNS) CreateDomain(ctx context.Context, opts conoha.CreateDomainRequest) (*conoha.Domain, error) {
+	if f.CreateDomainFunc == nil {
+		return nil, notImplemented("DNS.CreateDomain")
+	}
+	return f.CreateDomainFunc(ctx, opts)
+}
+
+// UpdateDomain calls UpdateDomainFunc.
+func (f *DNS) UpdateDomain(ctx context.Context, domainID string, opts conoha.UpdateDomainRequest) (*conoha.Domain, error) {
+	if f.UpdateDomainFunc == nil {
+		return nil, notImplemented("DNS.UpdateDomain")
+	}
+	return f.UpdateDomainFunc(ctx, domainID, opts)
+}
+
+// DeleteDomain calls DeleteDomainFunc.
+func (f *DNS) DeleteDomain(ctx context.Context, domainID string) error {
+	if f.DeleteDomainFunc == nil {
+		return notImplemented("DNS.DeleteDomain")
+	}
+	return f.DeleteDomainFunc(ctx, domainID)
+}
+
+// ListDNSRecords calls ListDNSRecordsFunc.
+func (f *DNS) ListDNSRecords(ctx context.Context, domainID string, opts *conoha.ListDNSRecordsOptions) ([]conoha.DNSRecord, error) {
+	if f.ListDNSRecordsFunc == nil {
+		return nil, notImplemented("DNS.ListDNSRecords")
+	}
+	return f.ListDNSRecordsFunc(ctx, domainID, opts)
+}
+
+// GetDNSRecord calls GetDNSRecordFunc.
+func (f *DNS) GetDNSRecord(ctx context.Context, domainID, recordID string) (*conoha.DNSRecord, error) {
+	if f.GetDNSRecordFunc == nil {
+		return nil, notImplemented("DNS.GetDNSRecord")
+	}
+	return f.GetDNSRecordFunc(ctx, domainID, recordID)
+}
+
+// CreateDNSRecord calls CreateDNSRecordFunc.
+func (f *DNS) CreateDNSRecord(ctx context.Context, domainID string, opts conoha.CreateDNSRecordRequest) (*conoha.DNSRecord, error) {
+	if f.CreateDNSRecordFunc == nil {
+		return nil, notImplemented("DNS.CreateDNSRecord")
+	}
+	return f.CreateDNSRecordFunc(ctx, domainID, opts)
+}
+
+// UpdateDNSRecord calls UpdateDNSRecordFunc.
+func (f *DNS) UpdateDNSRecord(ctx context.Context, domainID, recordID string, opts conoha.UpdateDNSRecordRequest) (*conoha.DNSRecord, error) {
+	if f.UpdateDNSRecordFunc == nil {
+		return nil, notImplemented("DNS.UpdateDNSRecord")
+	}
+	return f.UpdateDNSRecordFunc(ctx, domainID, recordID, opts)
+}
+
+// DeleteDNSRecord calls DeleteDNSRecordFunc.
+func (f *DNS) DeleteDNSRecord(ctx context.Context, domainID, recordID string) error {
+	if f.DeleteDNSRecordFunc == nil {
+		return notImplemented("DNS.DeleteDNSRecord")
+	}
+	return f.DeleteDNSRecordFunc(ctx, domainID, recordID)
+}
+
+// EachDomain calls EachDomainFunc.
+func (f *DNS) EachDomain(ctx context.Context, opts *conoha.ListDomainsOptions, fn func(conoha.Domain) error) error {
+	if f.EachDomainFunc == nil {
+		return notImplemented("DNS.EachDomain")
+	}
+	return f.EachDomainFunc(ctx, opts, fn)
+}
+
+// ListAllDomains calls ListAllDomainsFunc.
+func (f *DNS) ListAllDomains(ctx context.Context, opts *conoha.ListDomainsOptions) ([]conoha.Domain, error) {
+	if f.ListAllDomainsFunc == nil {
+		return nil, notImplemented("DNS.ListAllDomains")
+	}
+	return f.ListAllDomainsFunc(ctx, opts)
+}
+
+// EachDNSRecord calls EachDNSRecordFunc.
+func (f *DNS) EachDNSRecord(ctx context.Context, domainID string, opts *conoha.ListDNSRecordsOptions, fn func(conoha.DNSRecord) error) error {
+	if f.EachDNSRecordFunc == nil {
+		return notImplemented("DNS.EachDNSRecord")
+	}
+	return f.EachDNSRecordFunc(ctx, domainID, opts, fn)
+}
+
+// ListAllDNSRecords calls ListAllDNSRecordsFunc.
+func (f *DNS) ListAllDNSRecords(ctx context.Context, domainID string, opts *conoha.ListDNSRecordsOptions) ([]conoha.DNSRecord, error) {
+	if f.ListAllDNSRecordsFunc == nil {
+		return nil, notImplemented("DNS.ListAllDNSRecords")
+	}
+	return f.ListAllDNSRecordsFunc(ctx, domainID, opts)
+}
+
+// ResolveDomain calls ResolveDomainFunc.
+func (f *DNS) ResolveDomain(ctx context.Context, nameOrID string) (*conoha.Domain, error) {
+	if f.ResolveDomainFunc == nil {
+		return nil, notImplemented("DNS.ResolveDomain")
+	}
+	return f.ResolveDomainFunc(ctx, nameOrID)
+}
+
+// Identity is a fake conoha.IdentityAPI. Each method calls the field named
+// after it with a Func suffix, or fails with ErrNotImplemented if that
+// field is nil.
+type Identity struct {
+	AuthenticateFunc                func(context.Context, string, string, string) (*conoha.Token, error)
+	AuthenticateByNameFunc          func(context.Context, string, string, string) (*conoha.Token, error)
+	AuthenticateWithCredentialFunc  func(context.Context, string, string) (*conoha.Token, error)
+	ValidateTokenFunc               func(context.Context) (*conoha.Token, error)
+	RevokeTokenFunc                 func(context.Context) error
+	ListCredentialsFunc             func(context.Context, string) ([]conoha.Credential, error)
+	CreateCredentialFunc            func(context.Context, string, string) (*conoha.Credential, error)
+	GetCredentialFunc               func(context.Context, string, string) (*conoha.Credential, error)
+	DeleteCredentialFunc            func(context.Context, string, string) error
+	ListSubUsersFunc                func(context.Context) ([]conoha.SubUser, error)
+	CreateSubUserFunc               func(context.Context, string, []string) (*conoha.SubUser, error)
+	GetSubUserFunc                  func(context.Context, string) (*conoha.SubUser, error)
+	UpdateSubUserFunc               func(context.Context, string, string) (*conoha.SubUser, error)
+	DeleteSubUserFunc               func(context.Context, string) error
+	AssignRolesToSubUserFunc        func(context.Context, string, []string) (*conoha.SubUser, error)
+	UnassignRolesFromSubUserFunc    func(context.Context, string, []string) (*conoha.SubUser, error)
+	ListRolesFunc                   func(context.Context) ([]conoha.RoleDetail, error)
+	CreateRoleFunc                  func(context.Context, string, []string) (*conoha.RoleDetail, error)
+	GetRoleFunc                     func(context.Context, string) (*conoha.RoleDetail, error)
+	UpdateRoleFunc                  func(context.Context, string, string) (*conoha.RoleDetail, error)
+	DeleteRoleFunc                  func(context.Context, string) error
+	ListPermissionsFunc             func(context.Context) ([]conoha.Permission, error)
+	AssignPermissionsToRoleFunc     func(context.Context, string, []string) (*conoha.RoleDetail, error)
+	UnassignPermissionsFromRoleFunc func(context.Context, string, []string) (*conoha.RoleDetail, error)
+}
+
+// Authenticate calls AuthenticateFunc.
+func (f *Identity) Authenticate(ctx context.Context, userID, password, tenantID string) (*conoha.Token, error) {
+	if f.AuthenticateFunc == nil {
+		return nil, notImplemented("Identity.Authenticate")
+	}
+	return f.AuthenticateFunc(ctx, userID, password, tenantID)
+}
+
+// AuthenticateByName calls AuthenticateByNameFunc.
+func (f *Identity) AuthenticateByName(ctx context.Context, userName, password, tenantName string) (*conoha.Token, error) {
+	if f.AuthenticateByNameFunc == nil {
+		return nil, notImplemented("Identity.AuthenticateByName")
+	}
+	return f.AuthenticateByNameFunc(ctx, userName, password, tenantName)
+}
+
+// AuthenticateWithCredential calls AuthenticateWithCredentialFunc.
+func (f *Identity) AuthenticateWithCredential(ctx context.Context, access, secret string) (*conoha.Token, error) {
+	if f.AuthenticateWithCredentialFunc == nil {
+		return nil, notImplemented("Identity.AuthenticateWithCredential")
+	}
+	return f.AuthenticateWithCredentialFunc(ctx, access, secret)
+}
+
+// ValidateToken calls ValidateTokenFunc.
+func (f *Identity) ValidateToken(ctx context.Context) (*conoha.Token, error) {
+	if f.ValidateTokenFunc == nil {
+		return nil, notImplemented("Identity.ValidateToken")
+	}
+	return f.ValidateTokenFunc(ctx)
+}
+
+// RevokeToken calls RevokeTokenFunc.
+func (f *Identity) RevokeToken(ctx context.Context) error {
+	if f.RevokeTokenFunc == nil {
+		return notImplemented("Identity.RevokeToken")
+	}
+	return f.RevokeTokenFunc(ctx)
+}
+
+// ListCredentials calls ListCredentialsFunc.
+func (f *Identity) ListCredentials(ctx context.Context, userID string) ([]conoha.Credential, error) {
+	if f.ListCredentialsFunc == nil {
+		return nil, notImplemented("Identity.ListCredentials")
+	}
+	return f.ListCredentialsFunc(ctx, userID)
+}
+
+// CreateCredential calls CreateCredentialFunc.
+func (f *Identity) CreateCredential(ctx context.Context, userID, tenantID string) (*conoha.Credential, error) {
+	if f.CreateCredentialFunc == nil {
+		return nil, notImplemented("Identity.CreateCredential")
+	}
+	return f.CreateCredentialFunc(ctx, userID, tenantID)
+}
+
+// GetCredential calls GetCredentialFunc.
+func (f *Identity) GetCredential(ctx context.Context, userID, credentialID string) (*conoha.Credential, error) {
+	if f.GetCredentialFunc == nil {
+		return nil, notImplemented("Identity.GetCredential")
+	}
+	return f.GetCredentialFunc(ctx, userID, credentialID)
+}
+
+// DeleteCredential calls DeleteCredentialFunc.
+func (f *Identity) DeleteCredential(ctx context.Context, userID, credentialID string) error {
+	if f.DeleteCredentialFunc == nil {
+		return notImplemented("Identity.DeleteCredential")
+	}
+	return f.DeleteCredentialFunc(ctx, userID, credentialID)
+}
+
+// ListSubUsers calls ListSubUsersFunc.
+func (f *Identity) ListSubUsers(ctx context.Context) ([]conoha.SubUser, error) {
+	if f.ListSubUsersFunc == nil {
+		return nil, notImplemented("Identity.ListSubUsers")
+	}
+	return f.ListSubUsersFunc(ctx)
+}
+
+// CreateSubUser calls CreateSubUserFunc.
+func (f *Identity) CreateSubUser(ctx context.Context, password string, roles []string) (*conoha.SubUser, error) {
+	if f.CreateSubUserFunc == nil {
+		return nil, notImplemented("Identity.CreateSubUser")
+	}
+	return f.CreateSubUserFunc(ctx, password, roles)
+}
+
+// GetSubUser calls GetSubUserFunc.
+func (f *Identity) GetSubUser(ctx context.Context, subUserID string) (*conoha.SubUser, error) {
+	if f.GetSubUserFunc == nil {
+		return nil, notImplemented("Identity.GetSubUser")
+	}
+	return f.GetSubUserFunc(ctx, subUserID)
+}
+
+// UpdateSubUser calls UpdateSubUserFunc.
+func (f *Identity) UpdateSubUser(ctx context.Context, subUserID, password string) (*conoha.SubUser, error) {
+	if f.UpdateSubUserFunc == nil {
+		return nil, notImplemented("Identity.UpdateSubUser")
+	}
+	return f.UpdateSubUserFunc(ctx, subUserID, password)
+}
+
+// DeleteSubUser calls DeleteSubUserFunc.
+func (f *Identity) DeleteSubUser(ctx context.Context, subUserID string) error {
+	if f.DeleteSubUserFunc == nil {
+		return notImplemented("Identity.DeleteSubUser")
+	}
+	return f.DeleteSubUserFunc(ctx, subUserID)
+}
+
+// AssignRolesToSubUser calls AssignRolesToSubUserFunc.
+func (f *Identity) AssignRolesToSubUser(ctx context.Context, subUserID string, roleIDs []string) (*conoha.SubUser, error) {
+	if f.AssignRolesToSubUserFunc == nil {
+		return nil, notImplemented("Identity.AssignRolesToSubUser")
+	}
+	return f.AssignRolesToSubUserFunc(ctx, subUserID, roleIDs)
+}
+
+// UnassignRolesFromSubUser calls UnassignRolesFromSubUserFunc.
+func (f *Identity) UnassignRolesFromSubUser(ctx context.Context, subUserID string, roleIDs []string) (*conoha.SubUser, error) {
+	if f.UnassignRolesFromSubUserFunc == nil {
+		return nil, notImplemented("Identity.UnassignRolesFromSubUser")
+	}
+	return f.UnassignRolesFromSubUserFunc(ctx, subUserID, roleIDs)
+}
+
+// ListRoles calls ListRolesFunc.
+func (f *Identity) ListRoles(ctx context.Context) ([]conoha.RoleDetail, error) {
+	if f.ListRolesFunc == nil {
+		return nil, notImplemented("Identity.ListRoles")
+	}
+	return f.ListRolesFunc(ctx)
+}
+
+// CreateRole calls CreateRoleFunc.
+func (f *Identity) CreateRole(ctx context.Context, name string, permissions []string) (*conoha.RoleDetail, error) {
+	if f.CreateRoleFunc == nil {
+		return nil, notImplemented("Identity.CreateRole")
+	}
+	return f.CreateRoleFunc(ctx, name, permissions)
+}
+
+// GetRole calls GetRoleFunc.
+func (f *Identity) GetRole(ctx context.Context, roleID string) (*conoha.RoleDetail, error) {
+	if f.GetRoleFunc == nil {
+		return nil, notImplemented("Identity.GetRole")
+	}
+	return f.GetRoleFunc(ctx, roleID)
+}
+
+// UpdateRole calls UpdateRoleFunc.
+func (f *Identity) UpdateRole(ctx context.Context, roleID, name string) (*conoha.RoleDetail, error) {
+	if f.UpdateRoleFunc == nil {
+		return nil, notImplemented("Identity.UpdateRole")
+	}
+	return f.UpdateRoleFunc(ctx, roleID, name)
+}
+
+// DeleteRole calls DeleteRoleFunc.
+func (f *Identity) DeleteRole(ctx context.Context, roleID string) error {
+	if f.DeleteRoleFunc == nil {
+		return notImplemented("Identity.DeleteRole")
+	}
+	return f.DeleteRoleFunc(ctx, roleID)
+}
+
+// ListPermissions calls ListPermissionsFunc.
+func (f *Identity) ListPermissions(ctx context.Context) ([]conoha.Permission, error) {
+	if f.ListPermissionsFunc == nil {
+		return nil, notImplemented("Identity.ListPermissions")
+	}
+	return f.ListPermissionsFunc(ctx)
+}
+
+// AssignPermissionsToRole calls AssignPermissionsToRoleFunc.
+func (f *Identity) AssignPermissionsToRole(ctx context.Context, roleID string, permissions []string) (*conoha.RoleDetail, error) {
+	if f.AssignPermissionsToRoleFunc == nil {
+		return nil, notImplemented("Identity.AssignPermissionsToRole")
+	}
+	return f.AssignPermissionsToRoleFunc(ctx, roleID, permissions)
+}
+
+// UnassignPermissionsFromRole calls UnassignPermissionsFromRoleFunc.
+func (f *Identity) UnassignPermissionsFromRole(ctx context.Context, roleID string, permissions []string) (*conoha.RoleDetail, error) {
+	if f.UnassignPermissionsFromRoleFunc == nil {
+		return nil, notImplemented("Identity.UnassignPermissionsFromRole")
+	}
+	return f.UnassignPermissionsFromRoleFunc(ctx, roleID, permissions)
+}
+
+// Client fakes every service of *conoha.Client. Its services are nil
+// until set; use New for a Client with all of them allocated.
+type Client struct {
+	*Compute
+	*BlockStorage
+	*Image
+	*Network
+	*LoadBalancer
+	*ObjectStorage
+	*DNS
+	*Identity
+}
+
+// New returns a Client whose services are allocated but have no
+// functions set.
+func New() *Client {
+	return &Client{
+		Compute:       &Compute{},
+		BlockStorage:  &BlockStorage{},
+		Image:         &Image{},
+		Network:       &Network{},
+		LoadBalancer:  &LoadBalancer{},
+		ObjectStorage: &ObjectStorage{},
+		DNS:           &DNS{},
+		Identity:      &Identity{},
+	}
+}
+
+var (
+	_ conoha.ComputeAPI       = (*Compute)(nil)
+	_ conoha.ComputeAPI       = (*Client)(nil)
+	_ conoha.BlockStorageAPI  = (*BlockStorage)(nil)
+	_ conoha.BlockStorageAPI  = (*Client)(nil)
+	_ conoha.ImageAPI         = (*Image)(nil)
+	_ conoha.ImageAPI         = (*Client)(nil)
+	_ conoha.NetworkAPI       = (*Network)(nil)
+	_ conoha.NetworkAPI       = (*Client)(nil)
+	_ conoha.LoadBalancerAPI  = (*LoadBalancer)(nil)
+	_ conoha.LoadBalancerAPI  = (*Client)(nil)
+	_ conoha.ObjectStorageAPI = (*ObjectStorage)(nil)
+	_ conoha.ObjectStorageAPI = (*Client)(nil)
+	_ conoha.DNSAPI           = (*DNS)(nil)
+	_ conoha.DNSAPI           = (*Client)(nil)
+	_ conoha.IdentityAPI      = (*Identity)(nil)
+	_ conoha.IdentityAPI      = (*Client)(nil)
+)
@@ -0,0 +1,171 @@
+package conohatest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	domainKind = kind{singular: "domain", plural: "domains", idKey: "uuid"}
+	recordKind = kind{singular: "record", plural: "records", idKey: "uuid"}
+)
+
+// serveDNS serves domains and their records. Unlike the other services,
+// DNS resources are not wrapped in request and response bodies.
+func (s *Server) serveDNS(w http.ResponseWriter, r *http.Request, segs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(segs) == 0 || segs[0] != "domains" {
+		notImplemented(w, r)
+		return
+	}
+	switch {
+	case len(segs) == 1 && r.Method == http.MethodGet:
+		items, total := s.list(domainKind, r.URL.Query(), nil)
+		writeJSON(w, http.StatusOK, map[string]any{"domains": items, "total_count": total})
+	case len(segs) == 1 && r.Method == http.MethodPost:
+		body, err := readJSON(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "malformed request body: "+err.Error())
+			return
+		}
+		name := str(body, "name")
+		if !strings.HasSuffix(name, ".") || str(body, "email") == "" {
+			writeError(w, http.StatusBadRequest, "A fully qualified domain name ending with a dot and an email are required.")
+			return
+		}
+		for _, d := range s.collection(domainKind).items {
+			if d.fields["name"] == name {
+				writeError(w, http.StatusConflict, fmt.Sprintf("Domain %s already exists.", name))
+				return
+			}
+		}
+		ttl, _ := body["ttl"].(float64)
+		if ttl == 0 {
+			ttl = 3600
+		}
+		d := s.insert(domainKind, map[string]any{
+			"name":       name,
+			"project_id": TenantID,
+			"serial":     1,
+			"ttl":        int(ttl),
+			"email":      str(body, "email"),
+			"created_at": now(),
+			"updated_at": now(),
+		})
+		writeJSON(w, http.StatusCreated, clone(d.fields))
+	case len(segs) >= 2:
+		d := s.lookup(domainKind, segs[1])
+		if d == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Domain %s could not be found.", segs[1]))
+			return
+		}
+		if len(segs) > 2 && segs[2] == "records" {
+			s.serveRecords(w, r, d, segs[3:])
+			return
+		}
+		switch {
+		case len(segs) == 2 && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, clone(d.fields))
+		case len(segs) == 2 && r.Method == http.MethodPut:
+			body, err := readJSON(r)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "malformed request body: "+err.Error())
+				return
+			}
+			update := map[string]any{}
+			if ttl, _ := body["ttl"].(float64); ttl > 0 {
+				update["ttl"] = int(ttl)
+			}
+			if email := str(body, "email"); email != "" {
+				update["email"] = email
+			}
+			merge(d, update, "updated_at")
+			bumpSerial(d)
+			writeJSON(w, http.StatusOK, clone(d.fields))
+		case len(segs) == 2 && r.Method == http.MethodDelete:
+			id := segs[1]
+			records, _ := s.list(recordKind, nil, func(m map[string]any) bool { return m["domain_uuid"] == id })
+			for _, rec := range records {
+				s.remove(recordKind, rec["uuid"].(string))
+			}
+			s.remove(domainKind, id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			notImplemented(w, r)
+		}
+	default:
+		notImplemented(w, r)
+	}
+}
+
+func (s *Server) serveRecords(w http.ResponseWriter, r *http.Request, d *resource, segs []string) {
+	domainID := d.fields["uuid"].(string)
+	switch {
+	case len(segs) == 0 && r.Method == http.MethodGet:
+		items, total := s.list(recordKind, r.URL.Query(), func(m map[string]any) bool { return m["domain_uuid"] == domainID })
+		writeJSON(w, http.StatusOK, map[string]any{"records": items, "total_count": total})
+	case len(segs) == 0 && r.Method == http.MethodPost:
+		body, err := readJSON(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "malformed request body: "+err.Error())
+			return
+		}
+		if str(body, "name") == "" || str(body, "type") == "" || str(body, "data") == "" {
+			writeError(w, http.StatusBadRequest, "name, type and data are required.")
+			return
+		}
+		fields := map[string]any{
+			"domain_uuid": domainID,
+			"priority":    nil,
+			"weight":      nil,
+			"port":        nil,
+			"ttl":         d.fields["ttl"],
+			"created_at":  now(),
+			"updated_at":  now(),
+		}
+		for _, k := range []string{"name", "type", "data", "priority", "weight", "port", "ttl"} {
+			if v, ok := body[k]; ok {
+				fields[k] = v
+			}
+		}
+		rec := s.insert(recordKind, fields)
+		bumpSerial(d)
+		writeJSON(w, http.StatusCreated, clone(rec.fields))
+	case len(segs) == 1:
+		rec := s.lookup(recordKind, segs[0])
+		if rec == nil || rec.fields["domain_uuid"] != domainID {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Record %s could not be found.", segs[0]))
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, clone(rec.fields))
+		case http.MethodPut:
+			body, err := readJSON(r)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "malformed request body: "+err.Error())
+				return
+			}
+			merge(rec, body, "updated_at")
+			bumpSerial(d)
+			writeJSON(w, http.StatusOK, clone(rec.fields))
+		case http.MethodDelete:
+			s.remove(recordKind, segs[0])
+			bumpSerial(d)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			notImplemented(w, r)
+		}
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// bumpSerial increments the SOA serial of a changed domain.
+func bumpSerial(d *resource) {
+	d.fields["serial"] = d.fields["serial"].(int) + 1
+	d.fields["updated_at"] = now()
+}
@@ -0,0 +1,69 @@
+package conohatest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Fault makes the server misbehave on matching requests.
+//
+//	// The next two listings fail with 503, then the server recovers.
+//	srv.InjectFault(conohatest.Fault{Method: "GET", Path: "/servers", StatusCode: 503, Times: 2})
+type Fault struct {
+	// Method matches the request method. Empty matches every method.
+	Method string
+	// Path matches requests whose URL path contains it. Empty matches
+	// every path.
+	Path string
+
+	// Latency delays the response.
+	Latency time.Duration
+	// StatusCode, if set, is answered instead of handling the request,
+	// with an OpenStack-style error body.
+	StatusCode int
+	// RetryAfter, if set, is sent as the Retry-After header of the
+	// StatusCode response, in whole seconds.
+	RetryAfter time.Duration
+
+	// Times is the number of requests the fault applies to. Zero applies
+	// it until ClearFaults.
+	Times int
+}
+
+// InjectFault adds a fault. When several faults match a request, the one
+// injected first applies.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// matchFault returns the fault for r, if any, counting it as used. s.mu
+// must be held.
+func (s *Server) matchFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+			continue
+		}
+		if f.Path != "" && !strings.Contains(r.URL.Path, f.Path) {
+			continue
+		}
+		match := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return &match
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package conohatest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// serveIdentity serves the token API under /v3. Other Identity calls
+// (credentials, sub-users, roles) are not emulated.
+func (s *Server) serveIdentity(w http.ResponseWriter, r *http.Request, path string) {
+	switch {
+	case path == "/auth/tokens" && r.Method == http.MethodPost:
+		s.issuePasswordToken(w, r)
+	case path == "/ec2tokens" && r.Method == http.MethodPost:
+		s.issueEC2Token(w, r)
+	case path == "/auth/tokens" && (r.Method == http.MethodGet || r.Method == http.MethodDelete):
+		s.mu.Lock()
+		authorized := s.tokens[r.Header.Get("X-Auth-Token")]
+		subject := r.Header.Get("X-Subject-Token")
+		valid := s.tokens[subject]
+		if authorized && valid && r.Method == http.MethodDelete {
+			delete(s.tokens, subject)
+		}
+		s.mu.Unlock()
+		switch {
+		case !authorized:
+			writeError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+		case !valid:
+			writeError(w, http.StatusNotFound, "Could not find token.")
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeJSON(w, http.StatusOK, map[string]any{"token": s.tokenBody(r, "password")})
+		}
+	default:
+		notImplemented(w, r)
+	}
+}
+
+func (s *Server) issuePasswordToken(w http.ResponseWriter, r *http.Request) {
+	body, err := readJSON(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body: "+err.Error())
+		return
+	}
+	auth := object(body, "auth")
+	user := object(object(object(auth, "identity"), "password"), "user")
+	project := object(object(auth, "scope"), "project")
+
+	userOK := str(user, "id") == UserID || (str(user, "id") == "" && str(user, "name") == UserName)
+	projectOK := str(project, "id") == TenantID || (str(project, "id") == "" && str(project, "name") == TenantName)
+	if !userOK || !projectOK || str(user, "password") != Password {
+		writeError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+		return
+	}
+	s.writeToken(w, r, "password")
+}
+
+func (s *Server) issueEC2Token(w http.ResponseWriter, r *http.Request) {
+	body, err := readJSON(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body: "+err.Error())
+		return
+	}
+	creds := object(body, "credentials")
+	params := map[string]string{}
+	for k, v := range object(creds, "params") {
+		params[k], _ = v.(string)
+	}
+	want := ec2Signature(SecretKey, str(creds, "verb"), str(creds, "host"), str(creds, "path"), params)
+	if str(creds, "access") != AccessKey || !hmac.Equal([]byte(want), []byte(str(creds, "signature"))) {
+		writeError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+		return
+	}
+	s.writeToken(w, r, "ec2credential")
+}
+
+// ec2Signature computes an EC2 signature version 2, as the Identity
+// service checks it.
+func ec2Signature(secret, verb, host, path string, params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	escape := func(s string) string { return strings.ReplaceAll(url.QueryEscape(s), "+", "%20") }
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = escape(k) + "=" + escape(params[k])
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(verb + "\n" + host + "\n" + path + "\n" + strings.Join(pairs, "&")))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Server) writeToken(w http.ResponseWriter, r *http.Request, method string) {
+	s.mu.Lock()
+	s.nextID++
+	token := fmt.Sprintf("token-%d", s.nextID)
+	s.tokens[token] = true
+	s.mu.Unlock()
+
+	w.Header().Set("X-Subject-Token", token)
+	writeJSON(w, http.StatusCreated, map[string]any{"token": s.tokenBody(r, method)})
+}
+
+// tokenBody returns the token document, whose catalog points at the
+// server as reached by r.
+func (s *Server) tokenBody(r *http.Request, method string) map[string]any {
+	base := "http://" + r.Host
+	issued := time.Now().UTC()
+	return map[string]any{
+		"audit_ids":  []string{"audit"},
+		"methods":    []string{method},
+		"issued_at":  issued.Format(time.RFC3339Nano),
+		"expires_at": issued.Add(s.tokenTTL).Format(time.RFC3339Nano),
+		"project": map[string]any{
+			"id":     TenantID,
+			"name":   TenantName,
+			"domain": map[string]any{"id": "default", "name": "Default"},
+		},
+		"user": map[string]any{
+			"id":     UserID,
+			"name":   UserName,
+			"domain": map[string]any{"id": "default", "name": "Default"},
+		},
+		"roles": []map[string]any{{"id": "member", "name": "member"}},
+		"catalog": []map[string]any{
+			catalogEntry("identity", "keystone", base+"/v3"),
+			catalogEntry("compute", "nova", base+"/compute/v2.1"),
+			catalogEntry("volumev3", "cinderv3", base+"/volume/v3/"+TenantID),
+			catalogEntry("image", "glance", base+"/image"),
+			catalogEntry("network", "neutron", base+"/network"),
+			catalogEntry("load-balancer", "octavia", base+"/lbaas"),
+			catalogEntry("object-store", "swift", base+"/object/v1/AUTH_"+TenantID),
+			catalogEntry("dns", "designate", base+"/dns"),
+		},
+	}
+}
+
+func catalogEntry(typ, name, url string) map[string]any {
+	return map[string]any{
+		"id":   typ,
+		"type": typ,
+		"name": name,
+		"endpoints": []map[string]any{{
+			"id":        typ + "-public",
+			"interface": "public",
+			"region":    Region,
+			"region_id": Region,
+			"url":       url,
+		}},
+	}
+}
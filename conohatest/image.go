@@ -0,0 +1,142 @@
+package conohatest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var imageKind = kind{singular: "image", plural: "images", idKey: "id", statusKey: "status"}
+
+// serveImage serves images, ISO uploads and the image storage quota.
+func (s *Server) serveImage(w http.ResponseWriter, r *http.Request, segs []string) {
+	// Read uploads before locking, so that slow clients don't stall the
+	// server.
+	var upload []byte
+	if len(segs) == 3 && segs[0] == "images" && segs[2] == "file" && r.Method == http.MethodPut {
+		var err error
+		if upload, err = io.ReadAll(r.Body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case len(segs) == 1 && segs[0] == "images" && r.Method == http.MethodGet:
+		items, _ := s.list(imageKind, r.URL.Query(), nil)
+		writeJSON(w, http.StatusOK, map[string]any{"images": items})
+	case len(segs) == 1 && segs[0] == "images" && r.Method == http.MethodPost:
+		body, err := readJSON(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "malformed request body: "+err.Error())
+			return
+		}
+		if str(body, "name") == "" {
+			writeError(w, http.StatusBadRequest, "Image name is required.")
+			return
+		}
+		fields := newImage(str(body, "name"), "private")
+		for _, k := range []string{"disk_format", "container_format", "hw_rescue_bus", "hw_rescue_device"} {
+			if v := str(body, k); v != "" {
+				fields[k] = v
+			}
+		}
+		img := s.insert(imageKind, fields)
+		writeJSON(w, http.StatusCreated, clone(img.fields))
+	case len(segs) == 2 && segs[0] == "images" && segs[1] == "total" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"images": map[string]any{"size": s.imageUsage()}})
+	case len(segs) >= 2 && segs[0] == "images":
+		img := s.lookup(imageKind, segs[1])
+		if img == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("No image found with ID %s", segs[1]))
+			return
+		}
+		switch {
+		case len(segs) == 2 && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, clone(img.fields))
+		case len(segs) == 2 && r.Method == http.MethodDelete:
+			if img.fields["protected"] == true {
+				writeError(w, http.StatusForbidden, fmt.Sprintf("Image %s is protected and cannot be deleted.", segs[1]))
+				return
+			}
+			s.remove(imageKind, segs[1])
+			w.WriteHeader(http.StatusNoContent)
+		case len(segs) == 3 && segs[2] == "file" && r.Method == http.MethodPut:
+			if img.fields["status"] != "queued" {
+				writeError(w, http.StatusConflict, fmt.Sprintf("Image status transition from %s to saving is not allowed", img.fields["status"]))
+				return
+			}
+			sum := md5.Sum(upload)
+			img.fields["size"] = int64(len(upload))
+			img.fields["checksum"] = hex.EncodeToString(sum[:])
+			img.fields["updated_at"] = now()
+			s.transit(imageKind, img, "saving", "active", nil)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			notImplemented(w, r)
+		}
+	case len(segs) == 1 && segs[0] == "quota" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"quota": map[string]any{"image_size": s.imageQuota()}})
+	case len(segs) == 1 && segs[0] == "quota" && r.Method == http.MethodPut:
+		req := wrapped(w, r, "quota")
+		if req == nil {
+			return
+		}
+		s.quota = str(req, "image_size")
+		writeJSON(w, http.StatusOK, map[string]any{"quota": map[string]any{"image_size": s.quota}})
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// newImage returns the document of a new, queued image.
+func newImage(name, visibility string) map[string]any {
+	return map[string]any{
+		"name":             name,
+		"status":           "queued",
+		"visibility":       visibility,
+		"size":             0,
+		"disk_format":      "",
+		"container_format": "",
+		"min_disk":         0,
+		"min_ram":          0,
+		"created_at":       now(),
+		"updated_at":       now(),
+		"owner":            TenantID,
+		"protected":        false,
+		"tags":             []any{},
+		"os_hidden":        false,
+		"virtual_size":     nil,
+	}
+}
+
+// imageUsage returns the bytes used by the account's images. s.mu must be
+// held.
+func (s *Server) imageUsage() int64 {
+	var total int64
+	for _, img := range s.collection(imageKind).items {
+		if img.fields["owner"] != TenantID {
+			continue
+		}
+		switch size := img.fields["size"].(type) {
+		case int:
+			total += int64(size)
+		case int64:
+			total += size
+		}
+	}
+	return total
+}
+
+// imageQuota returns the image storage quota. s.mu must be held.
+func (s *Server) imageQuota() string {
+	if s.quota == "" {
+		return "50GB"
+	}
+	return s.quota
+}
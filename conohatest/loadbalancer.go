@@ -0,0 +1,247 @@
+package conohatest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+var (
+	lbKind       = kind{singular: "loadbalancer", plural: "loadbalancers", idKey: "id", statusKey: "provisioning_status", deleting: "PENDING_DELETE"}
+	listenerKind = kind{singular: "listener", plural: "listeners", idKey: "id", statusKey: "provisioning_status"}
+	poolKind     = kind{singular: "pool", plural: "pools", idKey: "id", statusKey: "provisioning_status"}
+	memberKind   = kind{singular: "member", plural: "members", idKey: "id", statusKey: "provisioning_status"}
+	monitorKind  = kind{singular: "healthmonitor", plural: "healthmonitors", idKey: "id", statusKey: "provisioning_status"}
+)
+
+// serveLoadBalancer serves load balancers, listeners, pools, members and
+// health monitors. As in Octavia, changing a child resource puts its load
+// balancer into PENDING_UPDATE, and a load balancer in a PENDING status
+// can't be changed.
+func (s *Server) serveLoadBalancer(w http.ResponseWriter, r *http.Request, segs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var k kind
+	var poolID string
+	switch {
+	case len(segs) >= 3 && segs[0] == "pools" && segs[2] == "members":
+		if s.lookup(poolKind, segs[1]) == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Pool %s could not be found.", segs[1]))
+			return
+		}
+		k, poolID, segs = memberKind, segs[1], segs[2:]
+	case len(segs) >= 1 && segs[0] == "loadbalancers":
+		k = lbKind
+	case len(segs) >= 1 && segs[0] == "listeners":
+		k = listenerKind
+	case len(segs) >= 1 && segs[0] == "pools":
+		k = poolKind
+	case len(segs) >= 1 && segs[0] == "healthmonitors":
+		k = monitorKind
+	default:
+		notImplemented(w, r)
+		return
+	}
+
+	switch {
+	case len(segs) == 1 && r.Method == http.MethodGet:
+		items, _ := s.list(k, r.URL.Query(), func(m map[string]any) bool {
+			return poolID == "" || m["pool_id"] == poolID
+		})
+		for _, item := range items {
+			s.renderLoadBalancerResource(k, item)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{k.plural: items})
+	case len(segs) == 1 && r.Method == http.MethodPost:
+		s.createLoadBalancerResource(w, r, k, poolID)
+	case len(segs) == 2:
+		res := s.lookup(k, segs[1])
+		if res == nil || (poolID != "" && res.fields["pool_id"] != poolID) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found.", k.singular, segs[1]))
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			out := clone(res.fields)
+			s.renderLoadBalancerResource(k, out)
+			writeJSON(w, http.StatusOK, map[string]any{k.singular: out})
+		case http.MethodPut:
+			req := wrapped(w, r, k.singular)
+			if req == nil {
+				return
+			}
+			lb := s.owningLoadBalancer(k, res.fields)
+			if !s.checkMutable(w, lb) {
+				return
+			}
+			merge(res, req, "")
+			s.touchLoadBalancer(lb)
+			out := clone(res.fields)
+			s.renderLoadBalancerResource(k, out)
+			writeJSON(w, http.StatusOK, map[string]any{k.singular: out})
+		case http.MethodDelete:
+			lb := s.owningLoadBalancer(k, res.fields)
+			if !s.checkMutable(w, lb) {
+				return
+			}
+			if k == lbKind {
+				out := clone(res.fields)
+				s.renderLoadBalancerResource(k, out)
+				if len(out["listeners"].([]any)) > 0 || len(out["pools"].([]any)) > 0 {
+					writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot delete Load Balancer %s - it has children", segs[1]))
+					return
+				}
+				s.destroy(lbKind, res)
+			} else {
+				s.remove(k, segs[1])
+				s.touchLoadBalancer(lb)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			notImplemented(w, r)
+		}
+	default:
+		notImplemented(w, r)
+	}
+}
+
+func (s *Server) createLoadBalancerResource(w http.ResponseWriter, r *http.Request, k kind, poolID string) {
+	req := wrapped(w, r, k.singular)
+	if req == nil {
+		return
+	}
+	fields := map[string]any{
+		"name":                "",
+		"description":         "",
+		"admin_state_up":      true,
+		"operating_status":    "ONLINE",
+		"provisioning_status": "ACTIVE",
+		"project_id":          TenantID,
+		"tenant_id":           TenantID,
+	}
+	for key, v := range req {
+		fields[key] = v
+	}
+
+	// Link the new resource to its parent, which must exist.
+	var parent *resource
+	var parentKind kind
+	var parentKey string
+	switch k {
+	case listenerKind:
+		parentKind, parentKey = lbKind, "loadbalancer_id"
+	case poolKind:
+		parentKind, parentKey = listenerKind, "listener_id"
+	case monitorKind:
+		parentKind, parentKey = poolKind, "pool_id"
+	case memberKind:
+		fields["pool_id"] = poolID
+		parentKind, parentKey = poolKind, "pool_id"
+	}
+	if parentKey != "" {
+		parent = s.lookup(parentKind, str(fields, parentKey))
+		if parent == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found.", parentKind.singular, str(fields, parentKey)))
+			return
+		}
+	}
+	var lb *resource
+	if k != lbKind {
+		lb = s.owningLoadBalancer(k, fields)
+		if !s.checkMutable(w, lb) {
+			return
+		}
+	}
+
+	switch k {
+	case lbKind:
+		s.nextID++
+		fields["vip_address"] = fmt.Sprintf("203.0.113.%d", s.nextID%250+1)
+		fields["vip_port_id"] = ""
+		fields["vip_subnet_id"] = ""
+		fields["vip_network_id"] = ""
+	case listenerKind:
+		fields["connection_limit"] = -1
+		fields["default_pool_id"] = nil
+		fields["loadbalancers"] = []any{map[string]any{"id": parent.fields["id"]}}
+	case poolKind:
+		fields["loadbalancer_id"] = parent.fields["loadbalancer_id"]
+		fields["loadbalancers"] = []any{map[string]any{"id": parent.fields["loadbalancer_id"]}}
+		fields["listeners"] = []any{map[string]any{"id": parent.fields["id"]}}
+	case monitorKind:
+		fields["pools"] = []any{map[string]any{"id": parent.fields["id"]}}
+	}
+
+	res := s.insert(k, fields)
+	switch k {
+	case lbKind:
+		s.transit(lbKind, res, "PENDING_CREATE", "ACTIVE", nil)
+	case poolKind:
+		if parent.fields["default_pool_id"] == nil {
+			parent.fields["default_pool_id"] = res.fields["id"]
+		}
+	}
+	s.touchLoadBalancer(lb)
+
+	out := clone(res.fields)
+	s.renderLoadBalancerResource(k, out)
+	writeJSON(w, http.StatusCreated, map[string]any{k.singular: out})
+}
+
+// owningLoadBalancer returns the load balancer of a resource of kind k
+// with the given fields, or nil. s.mu must be held.
+func (s *Server) owningLoadBalancer(k kind, fields map[string]any) *resource {
+	switch k {
+	case lbKind:
+		return s.lookup(lbKind, str(fields, "id"))
+	case listenerKind, poolKind:
+		return s.lookup(lbKind, str(fields, "loadbalancer_id"))
+	case memberKind, monitorKind:
+		if pool := s.lookup(poolKind, str(fields, "pool_id")); pool != nil {
+			return s.lookup(lbKind, str(pool.fields, "loadbalancer_id"))
+		}
+	}
+	return nil
+}
+
+// checkMutable writes a 409 response and returns false if lb is in a
+// PENDING status. s.mu must be held.
+func (s *Server) checkMutable(w http.ResponseWriter, lb *resource) bool {
+	if lb == nil {
+		return true
+	}
+	status := str(lb.fields, "provisioning_status")
+	if status == "ACTIVE" || status == "ERROR" {
+		return true
+	}
+	writeError(w, http.StatusConflict, fmt.Sprintf("Load Balancer %s is immutable and cannot be updated.", lb.fields["id"]))
+	return false
+}
+
+// touchLoadBalancer puts lb through PENDING_UPDATE after a change to it or
+// one of its children. s.mu must be held.
+func (s *Server) touchLoadBalancer(lb *resource) {
+	if lb != nil && lb.fields["provisioning_status"] == "ACTIVE" {
+		s.transit(lbKind, lb, "PENDING_UPDATE", "ACTIVE", nil)
+	}
+}
+
+// renderLoadBalancerResource adds the references to children to out. s.mu
+// must be held.
+func (s *Server) renderLoadBalancerResource(k kind, out map[string]any) {
+	refs := func(child kind, key string) []any {
+		items, _ := s.list(child, nil, func(m map[string]any) bool { return m[key] == out["id"] })
+		ids := []any{}
+		for _, item := range items {
+			ids = append(ids, map[string]any{"id": item["id"]})
+		}
+		return ids
+	}
+	switch k {
+	case lbKind:
+		out["listeners"] = refs(listenerKind, "loadbalancer_id")
+		out["pools"] = refs(poolKind, "loadbalancer_id")
+	case poolKind:
+		out["members"] = refs(memberKind, "pool_id")
+	}
+}
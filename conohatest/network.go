@@ -0,0 +1,305 @@
+package conohatest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+var (
+	networkKind   = kind{singular: "network", plural: "networks", idKey: "id"}
+	subnetKind    = kind{singular: "subnet", plural: "subnets", idKey: "id"}
+	portKind      = kind{singular: "port", plural: "ports", idKey: "id", statusKey: "status"}
+	secGroupKind  = kind{singular: "security_group", plural: "security_groups", idKey: "id"}
+	secRuleKind   = kind{singular: "security_group_rule", plural: "security_group_rules", idKey: "id"}
+	qosPolicyKind = kind{singular: "policy", plural: "policies", idKey: "id"}
+)
+
+// serveNetwork serves networks, subnets, ports, security groups and their
+// rules, additional IP allocation and QoS policies.
+func (s *Server) serveNetwork(w http.ResponseWriter, r *http.Request, segs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(segs) == 0 {
+		notImplemented(w, r)
+		return
+	}
+	var k kind
+	switch segs[0] {
+	case "networks":
+		k = networkKind
+	case "subnets":
+		k = subnetKind
+	case "ports":
+		k = portKind
+	case "security-groups":
+		k = secGroupKind
+	case "security-group-rules":
+		k = secRuleKind
+	case "qos":
+		if len(segs) < 2 || segs[1] != "policies" {
+			notImplemented(w, r)
+			return
+		}
+		k, segs = qosPolicyKind, segs[1:]
+	case "allocateips":
+		if len(segs) == 1 && r.Method == http.MethodPost {
+			s.allocateIPs(w, r)
+			return
+		}
+		notImplemented(w, r)
+		return
+	default:
+		notImplemented(w, r)
+		return
+	}
+
+	switch {
+	case len(segs) == 1 && r.Method == http.MethodGet:
+		items, _ := s.list(k, r.URL.Query(), nil)
+		for _, item := range items {
+			s.renderNetworkResource(k, item)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{k.plural: items})
+	case len(segs) == 1 && r.Method == http.MethodPost && k != qosPolicyKind:
+		s.createNetworkResource(w, r, k)
+	case len(segs) == 2:
+		res := s.lookup(k, segs[1])
+		if res == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s could not be found.", k.singular, segs[1]))
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet:
+			out := clone(res.fields)
+			s.renderNetworkResource(k, out)
+			writeJSON(w, http.StatusOK, map[string]any{k.singular: out})
+		case r.Method == http.MethodPut && (k == portKind || k == secGroupKind):
+			req := wrapped(w, r, k.singular)
+			if req == nil {
+				return
+			}
+			merge(res, req, "")
+			out := clone(res.fields)
+			s.renderNetworkResource(k, out)
+			writeJSON(w, http.StatusOK, map[string]any{k.singular: out})
+		case r.Method == http.MethodDelete && k != qosPolicyKind:
+			if msg := s.deleteNetworkResource(k, res); msg != "" {
+				writeError(w, http.StatusConflict, msg)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			notImplemented(w, r)
+		}
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// renderNetworkResource adds the fields derived from other resources to
+// out: the rules of a security group. s.mu must be held.
+func (s *Server) renderNetworkResource(k kind, out map[string]any) {
+	if k != secGroupKind {
+		return
+	}
+	rules, _ := s.list(secRuleKind, nil, func(rule map[string]any) bool {
+		return rule["security_group_id"] == out["id"]
+	})
+	out["security_group_rules"] = append([]map[string]any{}, rules...)
+}
+
+func (s *Server) createNetworkResource(w http.ResponseWriter, r *http.Request, k kind) {
+	var req map[string]any
+	if k == networkKind {
+		// ConoHa creates local networks without a request body.
+		req = map[string]any{}
+	} else if req = wrapped(w, r, k.singular); req == nil {
+		return
+	}
+
+	fields := map[string]any{"tenant_id": TenantID, "project_id": TenantID}
+	switch k {
+	case networkKind:
+		id := s.newID()
+		fields["id"] = id
+		fields["name"] = "local-" + id[:8]
+		fields["admin_state_up"] = true
+		fields["mtu"] = 1450
+		fields["status"] = "ACTIVE"
+		fields["subnets"] = []any{}
+		fields["shared"] = false
+		fields["router:external"] = false
+	case subnetKind:
+		network := s.lookup(networkKind, str(req, "network_id"))
+		if network == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Network %s could not be found.", str(req, "network_id")))
+			return
+		}
+		if str(req, "cidr") == "" {
+			writeError(w, http.StatusBadRequest, "cidr is required.")
+			return
+		}
+		id := s.newID()
+		fields["id"] = id
+		fields["name"] = ""
+		fields["network_id"] = network.fields["id"]
+		fields["ip_version"] = 4
+		fields["enable_dhcp"] = false
+		fields["ipv6_ra_mode"] = nil
+		fields["ipv6_address_mode"] = nil
+		fields["gateway_ip"] = nil
+		fields["cidr"] = str(req, "cidr")
+		fields["allocation_pools"] = []any{}
+		fields["host_routes"] = []any{}
+		fields["dns_nameservers"] = []any{}
+		network.fields["subnets"] = append(network.fields["subnets"].([]any), id)
+	case portKind:
+		network := s.lookup(networkKind, str(req, "network_id"))
+		if network == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Network %s could not be found.", str(req, "network_id")))
+			return
+		}
+		for key, v := range req {
+			fields[key] = v
+		}
+		s.fillPort(fields)
+	case secGroupKind:
+		if str(req, "name") == "" {
+			writeError(w, http.StatusBadRequest, "Security group name is required.")
+			return
+		}
+		fields["name"] = str(req, "name")
+		fields["description"] = str(req, "description")
+		fields["shared"] = false
+		sg := s.insert(secGroupKind, fields)
+		// Neutron adds egress rules to every new group.
+		for _, ether := range []string{"IPv4", "IPv6"} {
+			s.insert(secRuleKind, newRule(sg.fields["id"].(string), map[string]any{"direction": "egress", "ethertype": ether}))
+		}
+		out := clone(sg.fields)
+		s.renderNetworkResource(k, out)
+		writeJSON(w, http.StatusCreated, map[string]any{k.singular: out})
+		return
+	case secRuleKind:
+		sgID := str(req, "security_group_id")
+		if s.lookup(secGroupKind, sgID) == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Security group %s could not be found.", sgID))
+			return
+		}
+		if dir := str(req, "direction"); dir != "ingress" && dir != "egress" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid direction %q.", dir))
+			return
+		}
+		fields = newRule(sgID, req)
+	}
+	res := s.insert(k, fields)
+	writeJSON(w, http.StatusCreated, map[string]any{k.singular: clone(res.fields)})
+}
+
+// newRule returns the document of a security group rule from the fields of
+// a create request.
+func newRule(sgID string, req map[string]any) map[string]any {
+	rule := map[string]any{
+		"tenant_id":         TenantID,
+		"project_id":        TenantID,
+		"security_group_id": sgID,
+		"ethertype":         "IPv4",
+		"protocol":          nil,
+		"port_range_min":    nil,
+		"port_range_max":    nil,
+		"remote_ip_prefix":  nil,
+		"remote_group_id":   nil,
+	}
+	for k, v := range req {
+		rule[k] = v
+	}
+	return rule
+}
+
+// fillPort completes the document of a new port. s.mu must be held.
+func (s *Server) fillPort(fields map[string]any) {
+	id := s.newID()
+	fields["id"] = id
+	fields["mac_address"] = macAddress(s.nextID)
+	fields["admin_state_up"] = true
+	fields["status"] = "ACTIVE"
+	fields["device_owner"] = ""
+	for key, def := range map[string]any{
+		"name":                  "",
+		"device_id":             "",
+		"fixed_ips":             []any{map[string]any{"subnet_id": "", "ip_address": fmt.Sprintf("192.168.0.%d", s.nextID%250+1)}},
+		"security_groups":       []any{},
+		"allowed_address_pairs": []any{},
+		"extra_dhcp_opts":       []any{},
+		"binding:vnic_type":     "normal",
+	} {
+		if _, ok := fields[key]; !ok {
+			fields[key] = def
+		}
+	}
+}
+
+// deleteNetworkResource deletes res, or returns why it is in use. s.mu
+// must be held.
+func (s *Server) deleteNetworkResource(k kind, res *resource) string {
+	id := res.fields["id"].(string)
+	switch k {
+	case networkKind:
+		ports, _ := s.list(portKind, nil, func(p map[string]any) bool { return p["network_id"] == id })
+		if len(ports) > 0 {
+			return fmt.Sprintf("Unable to complete operation on network %s. There are one or more ports still in use on the network.", id)
+		}
+		for _, subnet := range res.fields["subnets"].([]any) {
+			s.remove(subnetKind, subnet.(string))
+		}
+	case subnetKind:
+		if network := s.lookup(networkKind, res.fields["network_id"].(string)); network != nil {
+			var subnets []any
+			for _, subnet := range network.fields["subnets"].([]any) {
+				if subnet != id {
+					subnets = append(subnets, subnet)
+				}
+			}
+			network.fields["subnets"] = append([]any{}, subnets...)
+		}
+	case secGroupKind:
+		rules, _ := s.list(secRuleKind, nil, func(rule map[string]any) bool { return rule["security_group_id"] == id })
+		for _, rule := range rules {
+			s.remove(secRuleKind, rule["id"].(string))
+		}
+	}
+	s.remove(k, id)
+	return ""
+}
+
+// allocateIPs serves POST /allocateips, which creates a port on the public
+// network with the requested number of addresses.
+func (s *Server) allocateIPs(w http.ResponseWriter, r *http.Request) {
+	req := wrapped(w, r, "allocateip")
+	if req == nil {
+		return
+	}
+	count, _ := req["count"].(float64)
+	if count < 1 {
+		writeError(w, http.StatusBadRequest, "count must be at least 1.")
+		return
+	}
+	ips := make([]any, int(count))
+	for i := range ips {
+		s.nextID++
+		ips[i] = map[string]any{"subnet_id": "public", "ip_address": fmt.Sprintf("203.0.113.%d", s.nextID%250+1)}
+	}
+	fields := map[string]any{
+		"tenant_id":  TenantID,
+		"project_id": TenantID,
+		"network_id": "public",
+		"fixed_ips":  ips,
+	}
+	if groups, ok := req["security_groups"]; ok {
+		fields["security_groups"] = groups
+	}
+	s.fillPort(fields)
+	port := s.insert(portKind, fields)
+	writeJSON(w, http.StatusCreated, map[string]any{"port": clone(port.fields)})
+}
@@ -0,0 +1,447 @@
+package conohatest
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// container is an Object Storage container.
+type container struct {
+	headers http.Header // X-Container-*, X-Versions-Location
+	objects map[string]*storedObject
+}
+
+// storedObject is an object, a DLO manifest (X-Object-Manifest header) or
+// an SLO manifest (segments).
+type storedObject struct {
+	data        []byte
+	contentType string
+	etag        string
+	modified    time.Time
+	deleteAt    int64
+	headers     http.Header // X-Object-Meta-*, X-Object-Manifest
+	segments    []string    // "container/object" paths of an SLO
+}
+
+// serveObjectStorage serves the account, containers and objects under
+// /object/v1/AUTH_<tenant>, including versioning, scheduled deletion and
+// large object manifests.
+func (s *Server) serveObjectStorage(w http.ResponseWriter, r *http.Request, segs []string) {
+	var body []byte
+	if r.Method == http.MethodPut {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch len(segs) {
+	case 0:
+		s.serveAccount(w, r)
+	case 1:
+		s.serveContainer(w, r, segs[0])
+	default:
+		s.serveObject(w, r, segs[0], strings.Join(segs[1:], "/"), body)
+	}
+}
+
+func (s *Server) serveAccount(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodHead, http.MethodGet:
+		var objects, used int64
+		names := make([]string, 0, len(s.containers))
+		for name, c := range s.containers {
+			names = append(names, name)
+			n, b := s.containerUsage(c)
+			objects += n
+			used += b
+		}
+		sort.Strings(names)
+		h := w.Header()
+		copyHeaders(h, s.account)
+		h.Set("X-Account-Container-Count", strconv.Itoa(len(s.containers)))
+		h.Set("X-Account-Object-Count", strconv.FormatInt(objects, 10))
+		h.Set("X-Account-Bytes-Used", strconv.FormatInt(used, 10))
+		h.Set("X-Account-Bytes-Used-Actual", strconv.FormatInt(used, 10))
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		list := []any{}
+		for _, name := range names {
+			n, b := s.containerUsage(s.containers[name])
+			list = append(list, map[string]any{"name": name, "count": n, "bytes": b})
+		}
+		writeJSON(w, http.StatusOK, list)
+	case http.MethodPost:
+		updateMetadata(s.account, r.Header, "X-Account-Meta-", "X-Remove-Account-Meta-")
+		if gb := r.Header.Get("X-Account-Meta-Quota-Giga-Bytes"); gb != "" {
+			n, err := strconv.ParseInt(gb, 10, 64)
+			if err != nil || n <= 0 || n%100 != 0 {
+				writeError(w, http.StatusBadRequest, "Quota must be a positive multiple of 100 GB.")
+				return
+			}
+			s.account.Set("X-Account-Meta-Quota-Bytes", strconv.FormatInt(n<<30, 10))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// containerUsage returns the number of live objects in c and their size.
+// s.mu must be held.
+func (s *Server) containerUsage(c *container) (objects, used int64) {
+	for name, o := range c.objects {
+		if s.expired(c, name, o) {
+			continue
+		}
+		objects++
+		used += int64(len(o.data))
+	}
+	return objects, used
+}
+
+func (s *Server) serveContainer(w http.ResponseWriter, r *http.Request, name string) {
+	c := s.containers[name]
+	if c == nil && r.Method != http.MethodPut {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Container %s not found.", name))
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		status := http.StatusAccepted
+		if c == nil {
+			c = &container{headers: http.Header{}, objects: map[string]*storedObject{}}
+			s.containers[name] = c
+			status = http.StatusCreated
+		}
+		updateContainer(c, r.Header)
+		w.WriteHeader(status)
+	case http.MethodPost:
+		updateContainer(c, r.Header)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodHead:
+		n, b := s.containerUsage(c)
+		h := w.Header()
+		copyHeaders(h, c.headers)
+		h.Set("X-Container-Object-Count", strconv.FormatInt(n, 10))
+		h.Set("X-Container-Bytes-Used", strconv.FormatInt(b, 10))
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.listObjects(c, r))
+	case http.MethodDelete:
+		if n, _ := s.containerUsage(c); n > 0 {
+			writeError(w, http.StatusConflict, "There was a conflict when trying to complete your request.")
+			return
+		}
+		delete(s.containers, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// updateContainer applies the container headers of a PUT or POST.
+func updateContainer(c *container, h http.Header) {
+	for _, key := range []string{"X-Container-Read", "X-Container-Write", "X-Versions-Location"} {
+		if vals, ok := h[key]; ok {
+			if len(vals) == 0 || vals[0] == "" {
+				c.headers.Del(key)
+			} else {
+				c.headers.Set(key, vals[0])
+			}
+		}
+	}
+	if _, ok := h["X-Remove-Versions-Location"]; ok {
+		c.headers.Del("X-Versions-Location")
+	}
+	updateMetadata(c.headers, h, "X-Container-Meta-", "X-Remove-Container-Meta-")
+}
+
+// listObjects returns the listing of c for the query of r. s.mu must be
+// held.
+func (s *Server) listObjects(c *container, r *http.Request) []any {
+	q := r.URL.Query()
+	prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+	marker, endMarker := q.Get("marker"), q.Get("end_marker")
+
+	names := make([]string, 0, len(c.objects))
+	for name, o := range c.objects {
+		if !s.expired(c, name, o) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if q.Get("reverse") == "true" {
+		sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	}
+
+	list := []any{}
+	seen := map[string]bool{}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	for _, name := range names {
+		if limit > 0 && len(list) >= limit {
+			break
+		}
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if marker != "" && (q.Get("reverse") == "true" && name >= marker || q.Get("reverse") != "true" && name <= marker) {
+			continue
+		}
+		if endMarker != "" && (q.Get("reverse") == "true" && name <= endMarker || q.Get("reverse") != "true" && name >= endMarker) {
+			continue
+		}
+		if delimiter != "" {
+			if i := strings.Index(name[len(prefix):], delimiter); i >= 0 {
+				subdir := name[:len(prefix)+i+len(delimiter)]
+				if !seen[subdir] {
+					seen[subdir] = true
+					list = append(list, map[string]any{"subdir": subdir})
+				}
+				continue
+			}
+		}
+		o := c.objects[name]
+		list = append(list, map[string]any{
+			"name":          name,
+			"hash":          o.etag,
+			"bytes":         len(o.data),
+			"content_type":  o.contentType,
+			"last_modified": o.modified.Format("2006-01-02T15:04:05.000000"),
+		})
+	}
+	return list
+}
+
+func (s *Server) serveObject(w http.ResponseWriter, r *http.Request, containerName, name string, body []byte) {
+	c := s.containers[containerName]
+	if c == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Container %s not found.", containerName))
+		return
+	}
+	o := c.objects[name]
+	if o != nil && s.expired(c, name, o) {
+		o = nil
+	}
+	if o == nil && r.Method != http.MethodPut {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Object %s/%s not found.", containerName, name))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		obj := &storedObject{contentType: r.Header.Get("Content-Type"), modified: time.Now(), headers: http.Header{}}
+		if obj.contentType == "" {
+			obj.contentType = "application/octet-stream"
+		}
+		if manifest := r.Header.Get("X-Object-Manifest"); manifest != "" {
+			obj.headers.Set("X-Object-Manifest", manifest)
+		} else if r.URL.Query().Get("multipart-manifest") == "put" {
+			if msg := s.parseSLO(obj, body); msg != "" {
+				writeError(w, http.StatusBadRequest, msg)
+				return
+			}
+		} else {
+			obj.data = body
+		}
+		sum := md5.Sum(obj.data)
+		obj.etag = hex.EncodeToString(sum[:])
+		updateMetadata(obj.headers, r.Header, "X-Object-Meta-", "")
+		if o != nil {
+			s.archiveVersion(c, name, o)
+		}
+		c.objects[name] = obj
+		w.Header().Set("ETag", obj.etag)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet, http.MethodHead:
+		data := s.objectContent(o)
+		h := w.Header()
+		copyHeaders(h, o.headers)
+		h.Set("Content-Type", o.contentType)
+		h.Set("ETag", o.etag)
+		h.Set("Last-Modified", o.modified.UTC().Format(http.TimeFormat))
+		h.Set("Content-Length", strconv.Itoa(len(data)))
+		if o.deleteAt > 0 {
+			h.Set("X-Delete-At", strconv.FormatInt(o.deleteAt, 10))
+		}
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(c.objects, name)
+		s.restoreVersion(c, name)
+		w.WriteHeader(http.StatusNoContent)
+	case "COPY":
+		dstContainer, dstName, _ := strings.Cut(strings.TrimPrefix(r.Header.Get("Destination"), "/"), "/")
+		dst := s.containers[dstContainer]
+		if dst == nil || dstName == "" {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Destination container %s not found.", dstContainer))
+			return
+		}
+		cp := *o
+		cp.data = s.objectContent(o)
+		cp.segments = nil
+		cp.headers = o.headers.Clone()
+		cp.headers.Del("X-Object-Manifest")
+		cp.modified = time.Now()
+		cp.deleteAt = 0
+		sum := md5.Sum(cp.data)
+		cp.etag = hex.EncodeToString(sum[:])
+		dst.objects[dstName] = &cp
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodPost:
+		if at := r.Header.Get("X-Delete-At"); at != "" {
+			o.deleteAt, _ = strconv.ParseInt(at, 10, 64)
+		}
+		if after := r.Header.Get("X-Delete-After"); after != "" {
+			secs, _ := strconv.ParseInt(after, 10, 64)
+			o.deleteAt = time.Now().Unix() + secs
+		}
+		updateMetadata(o.headers, r.Header, "X-Object-Meta-", "X-Remove-Object-Meta-")
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// parseSLO reads the segment list of an SLO manifest into obj, or returns
+// why it is invalid. s.mu must be held.
+func (s *Server) parseSLO(obj *storedObject, body []byte) string {
+	var segments []struct {
+		Path      string `json:"path"`
+		Etag      string `json:"etag"`
+		SizeBytes int64  `json:"size_bytes"`
+	}
+	if err := json.Unmarshal(body, &segments); err != nil || len(segments) == 0 {
+		return "Invalid SLO manifest."
+	}
+	for _, seg := range segments {
+		path := strings.TrimPrefix(seg.Path, "/")
+		cname, oname, _ := strings.Cut(path, "/")
+		c := s.containers[cname]
+		if c == nil || c.objects[oname] == nil {
+			return fmt.Sprintf("Segment %s not found.", seg.Path)
+		}
+		o := c.objects[oname]
+		if seg.Etag != "" && seg.Etag != o.etag {
+			return fmt.Sprintf("Etag mismatch for segment %s.", seg.Path)
+		}
+		if seg.SizeBytes != 0 && seg.SizeBytes != int64(len(o.data)) {
+			return fmt.Sprintf("Size mismatch for segment %s.", seg.Path)
+		}
+		obj.segments = append(obj.segments, path)
+	}
+	obj.headers.Set("X-Static-Large-Object", "True")
+	return ""
+}
+
+// objectContent returns the content of o, joining the segments of large
+// objects. s.mu must be held.
+func (s *Server) objectContent(o *storedObject) []byte {
+	var paths []string
+	if manifest := o.headers.Get("X-Object-Manifest"); manifest != "" {
+		cname, prefix, _ := strings.Cut(manifest, "/")
+		if c := s.containers[cname]; c != nil {
+			for name, seg := range c.objects {
+				if strings.HasPrefix(name, prefix) && !s.expired(c, name, seg) {
+					paths = append(paths, cname+"/"+name)
+				}
+			}
+		}
+		sort.Strings(paths)
+	} else if o.segments != nil {
+		paths = o.segments
+	} else {
+		return o.data
+	}
+	var buf bytes.Buffer
+	for _, path := range paths {
+		cname, oname, _ := strings.Cut(path, "/")
+		if c := s.containers[cname]; c != nil && c.objects[oname] != nil {
+			buf.Write(c.objects[oname].data)
+		}
+	}
+	return buf.Bytes()
+}
+
+// expired deletes the object name of c if its X-Delete-At has passed, and
+// reports whether it did. s.mu must be held.
+func (s *Server) expired(c *container, name string, o *storedObject) bool {
+	if o.deleteAt == 0 || time.Now().Unix() < o.deleteAt {
+		return false
+	}
+	delete(c.objects, name)
+	return true
+}
+
+// archiveVersion copies the current content of an object that is being
+// overwritten to the container's versions location, if it has one. s.mu
+// must be held.
+func (s *Server) archiveVersion(c *container, name string, o *storedObject) {
+	versions := s.containers[c.headers.Get("X-Versions-Location")]
+	if versions == nil {
+		return
+	}
+	s.nextID++
+	versions.objects[fmt.Sprintf("%03x%s/%d.%08d", len(name), name, o.modified.Unix(), s.nextID)] = o
+}
+
+// restoreVersion moves the newest archived version of a deleted object
+// back into place, if the container keeps versions. s.mu must be held.
+func (s *Server) restoreVersion(c *container, name string) {
+	versions := s.containers[c.headers.Get("X-Versions-Location")]
+	if versions == nil {
+		return
+	}
+	prefix := fmt.Sprintf("%03x%s/", len(name), name)
+	var newest string
+	for v := range versions.objects {
+		if strings.HasPrefix(v, prefix) && v > newest {
+			newest = v
+		}
+	}
+	if newest != "" {
+		c.objects[name] = versions.objects[newest]
+		delete(versions.objects, newest)
+	}
+}
+
+// updateMetadata applies the metadata headers of a request to stored
+// headers: those with prefix are set, those with removePrefix (if not
+// empty) are removed, and empty values remove the key as well.
+func updateMetadata(stored, req http.Header, prefix, removePrefix string) {
+	for key, vals := range req {
+		switch {
+		case strings.HasPrefix(key, prefix):
+			if len(vals) == 0 || vals[0] == "" {
+				stored.Del(key)
+			} else {
+				stored.Set(key, vals[0])
+			}
+		case removePrefix != "" && strings.HasPrefix(key, removePrefix):
+			stored.Del(prefix + strings.TrimPrefix(key, removePrefix))
+		}
+	}
+}
+
+func copyHeaders(dst, src http.Header) {
+	for key, vals := range src {
+		dst[key] = append([]string(nil), vals...)
+	}
+}
@@ -0,0 +1,93 @@
+package conohatest
+
+// Seeded resources, present in every new server.
+const (
+	// FlavorID is the ID of the smallest seeded flavor, g2l-t-c2m1.
+	FlavorID = "784f1ae8-0aa5-4a42-bcb4-5b2e2ac1e4b0"
+	// ImageID is the ID of the seeded public Ubuntu image.
+	ImageID = "2b6f2e3c-2a5e-4b3a-9c3b-5e0f2c1a7d10"
+	// BootVolumeType and AdditionalVolumeType are the seeded volume types.
+	BootVolumeType       = "c3j1-ds02-boot"
+	AdditionalVolumeType = "c3j1-ds02-add"
+)
+
+// seed creates the read-only resources that every account sees: flavors,
+// public images, volume types and QoS policies.
+func (s *Server) seed() {
+	flavors := []struct {
+		id, name         string
+		vcpus, ram, disk int
+	}{
+		{FlavorID, "g2l-t-c2m1", 2, 1024, 0},
+		{"a3c5b7e4-3d41-4b5a-8e3c-6c2f4b1d9e21", "g2l-t-c3m2", 3, 2048, 0},
+		{"c1d9f2a6-7b3e-4f8d-a5c2-9e4b6d3f1a32", "g2l-t-c4m4", 4, 4096, 0},
+		{"e7b2c4d8-5f1a-4c9e-b3d6-2a8f5c7e9b43", "g2l-t-c6m8", 6, 8192, 0},
+	}
+	for _, f := range flavors {
+		s.insert(flavorKind, map[string]any{
+			"id":                         f.id,
+			"name":                       f.name,
+			"vcpus":                      f.vcpus,
+			"ram":                        f.ram,
+			"disk":                       f.disk,
+			"swap":                       "",
+			"rxtx_factor":                1.0,
+			"links":                      []any{},
+			"OS-FLV-EXT-DATA:ephemeral":  0,
+			"OS-FLV-DISABLED:disabled":   false,
+			"os-flavor-access:is_public": true,
+		})
+	}
+
+	images := []struct {
+		id, name, osType string
+	}{
+		{ImageID, "vmi-ubuntu-24.04-amd64", "linux"},
+		{"4d8a1c3e-6b2f-4e9a-8c5d-7f3b1e2a9c54", "vmi-rockylinux-9-amd64", "linux"},
+		{"6f2c9e1b-8d4a-4b7e-a3c6-1e5d9b7f2a65", "vmi-win-2022-amd64", "windows"},
+	}
+	for _, img := range images {
+		s.insert(imageKind, map[string]any{
+			"id":               img.id,
+			"name":             img.name,
+			"status":           "active",
+			"visibility":       "public",
+			"os_type":          img.osType,
+			"size":             3 << 30,
+			"disk_format":      "qcow2",
+			"container_format": "bare",
+			"min_disk":         30,
+			"min_ram":          1024,
+			"created_at":       now(),
+			"updated_at":       now(),
+			"owner":            "conoha",
+			"protected":        true,
+			"tags":             []any{},
+			"os_hidden":        false,
+			"virtual_size":     nil,
+		})
+	}
+
+	for _, vt := range []struct{ id, name, desc string }{
+		{"8c4d2e6f-1a3b-4c5d-9e7f-2b4a6c8d0e76", BootVolumeType, "boot volume"},
+		{"9d5e3f7a-2b4c-4d6e-8f1a-3c5b7d9e1f87", AdditionalVolumeType, "additional volume"},
+	} {
+		s.insert(volumeTypeKind, map[string]any{
+			"id":          vt.id,
+			"name":        vt.name,
+			"is_public":   true,
+			"description": vt.desc,
+		})
+	}
+
+	s.insert(qosPolicyKind, map[string]any{
+		"id":          "0e6f4a8b-3c5d-4e7f-9a1b-4d6c8e0f2a98",
+		"name":        "c3j1-qos-default",
+		"description": "",
+		"shared":      true,
+		"is_default":  true,
+		"project_id":  TenantID,
+		"tenant_id":   TenantID,
+		"rules":       []any{},
+	})
+}
@@ -0,0 +1,311 @@
+// Package conohatest provides an in-memory ConoHa API server for tests.
+//
+// The server emulates Identity, Compute, Block Storage, Image, Networking,
+// Load Balancer, Object Storage and DNS well enough for create, read,
+// update and delete calls and the usual state transitions of servers
+// (BUILD → ACTIVE), volumes (creating → available), images (queued →
+// active) and load balancers (PENDING_CREATE → ACTIVE). Faults such as
+// latency, 5xx and 429 responses can be injected per request.
+//
+//	srv := conohatest.NewServer()
+//	defer srv.Close()
+//	client := srv.NewClient()
+//	vol, err := client.CreateVolume(ctx, conoha.CreateVolumeRequest{Size: 100, Name: "data"})
+//	vol, err = client.WaitForVolumeStatus(ctx, vol.ID, "available", nil)
+//
+// Calls the server does not emulate answer 501 Not Implemented.
+package conohatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+// Credentials accepted by the server.
+const (
+	UserID     = "test-user-id"
+	UserName   = "test-user"
+	Password   = "test-password"
+	TenantID   = "test-tenant-id"
+	TenantName = "test-tenant"
+	AccessKey  = "test-access-key"
+	SecretKey  = "test-secret-key"
+	Region     = "c3j1"
+)
+
+// Server is an in-memory ConoHa API. It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, to be used as the Identity URL.
+	URL string
+
+	srv *httptest.Server
+
+	mu         sync.Mutex
+	delay      time.Duration
+	nextID     int
+	tokens     map[string]bool
+	tokenTTL   time.Duration
+	colls      map[string]*collection
+	containers map[string]*container
+	account    http.Header // Object Storage account metadata
+	quota      string      // image storage quota
+	faults     []*Fault
+	requests   []Request
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithTransitionDelay sets how long resources stay in their transitional
+// status (BUILD, creating, …) before they become ready, and deleted
+// resources stay in a deleting status. By default transitions complete
+// before the next request is handled.
+func WithTransitionDelay(d time.Duration) Option {
+	return func(s *Server) {
+		s.delay = d
+	}
+}
+
+// WithTokenTTL sets the lifetime of issued tokens, reported as their
+// expires_at. The default is one day.
+func WithTokenTTL(d time.Duration) Option {
+	return func(s *Server) {
+		s.tokenTTL = d
+	}
+}
+
+// NewServer starts a Server. Close it when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		tokens:     make(map[string]bool),
+		tokenTTL:   24 * time.Hour,
+		colls:      make(map[string]*collection),
+		containers: make(map[string]*container),
+		account:    http.Header{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.seed()
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Config returns a client configuration with the server's credentials and
+// Identity URL. The other endpoints come from the service catalog.
+func (s *Server) Config() conoha.Config {
+	return conoha.Config{
+		UserID:    UserID,
+		Password:  Password,
+		TenantID:  TenantID,
+		Region:    Region,
+		Endpoints: conoha.Endpoints{Identity: s.URL + "/v3"},
+	}
+}
+
+// NewClient returns a client for the server that authenticates on its
+// first call. opts are applied after the server's configuration.
+func (s *Server) NewClient(opts ...conoha.ClientOption) *conoha.Client {
+	c, err := conoha.NewClientFromConfig(s.Config(), opts...)
+	if err != nil {
+		panic(err) // the configuration is complete
+	}
+	return c
+}
+
+// RevokeTokens invalidates every issued token, so that clients get 401
+// responses until they re-authenticate.
+func (s *Server) RevokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = make(map[string]bool)
+}
+
+// Request is a request the server received.
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// SetStatus sets the status of a resource, cancelling any pending
+// transition, e.g. to make a server fail with "ERROR". kind is the plural
+// name of the resource in the API: "servers", "volumes", "backups",
+// "images", "ports" or "loadbalancers" (whose provisioning_status is set).
+// It reports whether the resource exists.
+func (s *Server) SetStatus(kind, id, status string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.colls {
+		if c.kind.plural != kind {
+			continue
+		}
+		if r := c.items[id]; r != nil {
+			r.fields[c.kind.statusKey] = status
+			r.pending = nil
+			return true
+		}
+	}
+	return false
+}
+
+// newID returns a new resource ID. s.mu must be held.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", s.nextID, s.nextID)
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery})
+	s.settle()
+	fault := s.matchFault(r)
+	s.mu.Unlock()
+
+	if fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.StatusCode != 0 {
+			if fault.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(fault.RetryAfter.Seconds()))))
+			}
+			writeError(w, fault.StatusCode, "injected fault")
+			return
+		}
+	}
+
+	path := r.URL.Path
+	if strings.HasPrefix(path, "/v3/") {
+		s.serveIdentity(w, r, strings.TrimPrefix(path, "/v3"))
+		return
+	}
+
+	s.mu.Lock()
+	authorized := s.tokens[r.Header.Get("X-Auth-Token")]
+	s.mu.Unlock()
+	if !authorized {
+		writeError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+		return
+	}
+
+	service, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	svc, ok := services[service]
+	if !ok {
+		writeError(w, http.StatusNotFound, "no such service")
+		return
+	}
+	segs := trimSegs(strings.Split(strings.Trim(rest, "/"), "/"), svc.prefix...)
+	if segs == nil {
+		writeError(w, http.StatusNotFound, "no such API version")
+		return
+	}
+	svc.serve(s, w, r, segs)
+}
+
+// services maps the first path segment of the catalog endpoints to the
+// versioned prefix and handler of the service.
+var services = map[string]struct {
+	prefix []string
+	serve  func(s *Server, w http.ResponseWriter, r *http.Request, segs []string)
+}{
+	"compute": {[]string{"v2.1"}, (*Server).serveCompute},
+	"volume":  {[]string{"v3", TenantID}, (*Server).serveBlockStorage},
+	"image":   {[]string{"v2"}, (*Server).serveImage},
+	"network": {[]string{"v2.0"}, (*Server).serveNetwork},
+	"lbaas":   {[]string{"v2.0", "lbaas"}, (*Server).serveLoadBalancer},
+	"object":  {[]string{"v1", "AUTH_" + TenantID}, (*Server).serveObjectStorage},
+	"dns":     {[]string{"v1"}, (*Server).serveDNS},
+}
+
+// trimSegs removes the leading segments prefix from segs, or returns nil if
+// segs does not start with them. The result is never nil otherwise.
+func trimSegs(segs []string, prefix ...string) []string {
+	if len(segs) < len(prefix) {
+		return nil
+	}
+	for i, p := range prefix {
+		if segs[i] != p {
+			return nil
+		}
+	}
+	segs = segs[len(prefix):]
+	if len(segs) == 1 && segs[0] == "" {
+		return []string{}
+	}
+	return segs
+}
+
+// errorKeys are the OpenStack error keys of the statuses the server answers.
+var errorKeys = map[int]string{
+	http.StatusBadRequest:          "badRequest",
+	http.StatusUnauthorized:        "unauthorized",
+	http.StatusForbidden:           "forbidden",
+	http.StatusNotFound:            "itemNotFound",
+	http.StatusConflict:            "conflictingRequest",
+	http.StatusTooManyRequests:     "overLimit",
+	http.StatusNotImplemented:      "notImplemented",
+	http.StatusInternalServerError: "computeFault",
+	http.StatusServiceUnavailable:  "serviceUnavailable",
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	key := errorKeys[status]
+	if key == "" {
+		key = "error"
+	}
+	writeJSON(w, status, map[string]any{key: map[string]any{"message": message, "code": status}})
+}
+
+func notImplemented(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotImplemented, fmt.Sprintf("conohatest does not emulate %s %s", r.Method, r.URL.Path))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// readJSON decodes the request body into a map. An empty body is an empty
+// map.
+func readJSON(r *http.Request) (map[string]any, error) {
+	body := map[string]any{}
+	if r.ContentLength == 0 {
+		return body, nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
@@ -0,0 +1,391 @@
+package conohatest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+)
+
+// fastWait polls often enough for tests.
+var fastWait = &conoha.WaitOptions{Interval: time.Millisecond, Timeout: 5 * time.Second}
+
+func newServerAndClient(t *testing.T, opts ...Option) (*Server, *conoha.Client) {
+	t.Helper()
+	srv := NewServer(opts...)
+	t.Cleanup(srv.Close)
+	return srv, srv.NewClient()
+}
+
+// createBootVolume creates an available boot volume from the seeded image.
+func createBootVolume(t *testing.T, client *conoha.Client) *conoha.Volume {
+	t.Helper()
+	ctx := context.Background()
+	vol, err := client.CreateVolume(ctx, conoha.CreateVolumeRequest{
+		Size: 100, Name: "boot", VolumeType: BootVolumeType, ImageRef: ImageID,
+	})
+	if err != nil {
+		t.Fatalf("CreateVolume: %v", err)
+	}
+	if vol.Status != "creating" {
+		t.Errorf("new volume status = %q, want creating", vol.Status)
+	}
+	vol, err = client.WaitForVolumeStatus(ctx, vol.ID, "available", fastWait)
+	if err != nil {
+		t.Fatalf("WaitForVolumeStatus: %v", err)
+	}
+	return vol
+}
+
+func TestServer_ServerLifecycle(t *testing.T) {
+	srv, client := newServerAndClient(t)
+	ctx := context.Background()
+
+	vol := createBootVolume(t, client)
+	created, err := client.CreateServer(ctx, conoha.CreateServerRequest{
+		FlavorRef:          FlavorID,
+		AdminPass:          "Passw0rd!",
+		BlockDeviceMapping: []conoha.BlockDeviceMap{{UUID: vol.ID}},
+		Metadata:           map[string]string{"instance_name_tag": "web-1"},
+	})
+	if err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	if created.AdminPass != "Passw0rd!" {
+		t.Errorf("AdminPass = %q", created.AdminPass)
+	}
+
+	s, err := client.WaitForServerStatus(ctx, created.ID, "ACTIVE", fastWait)
+	if err != nil {
+		t.Fatalf("WaitForServerStatus: %v", err)
+	}
+	if s.Metadata["instance_name_tag"] != "web-1" || s.Flavor.ID != FlavorID {
+		t.Errorf("server = %+v", s)
+	}
+	if v, _ := client.GetVolume(ctx, vol.ID); v.Status != "in-use" {
+		t.Errorf("boot volume status = %q, want in-use", v.Status)
+	}
+
+	if err := client.StartServer(ctx, created.ID); !errors.Is(err, conoha.ErrConflict) {
+		t.Errorf("StartServer on ACTIVE server: err = %v, want ErrConflict", err)
+	}
+	if err := client.StopServer(ctx, created.ID); err != nil {
+		t.Fatalf("StopServer: %v", err)
+	}
+	if _, err := client.WaitForServerStatus(ctx, created.ID, "SHUTOFF", fastWait); err != nil {
+		t.Fatalf("WaitForServerStatus SHUTOFF: %v", err)
+	}
+
+	if err := client.ResizeServer(ctx, created.ID, "a3c5b7e4-3d41-4b5a-8e3c-6c2f4b1d9e21"); err != nil {
+		t.Fatalf("ResizeServer: %v", err)
+	}
+	if _, err := client.WaitForServerStatus(ctx, created.ID, "VERIFY_RESIZE", fastWait); err != nil {
+		t.Fatalf("WaitForServerStatus VERIFY_RESIZE: %v", err)
+	}
+	if err := client.ConfirmResize(ctx, created.ID); err != nil {
+		t.Fatalf("ConfirmResize: %v", err)
+	}
+	s, err = client.WaitForServerStatus(ctx, created.ID, "ACTIVE", fastWait)
+	if err != nil {
+		t.Fatalf("WaitForServerStatus after resize: %v", err)
+	}
+	if s.Flavor.ID != "a3c5b7e4-3d41-4b5a-8e3c-6c2f4b1d9e21" {
+		t.Errorf("flavor after resize = %q", s.Flavor.ID)
+	}
+
+	servers, err := client.ListServersDetail(ctx, &conoha.ListServersOptions{Status: "ACTIVE"})
+	if err != nil || len(servers) != 1 {
+		t.Fatalf("ListServersDetail = %d servers, %v", len(servers), err)
+	}
+
+	if err := client.DeleteServer(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteServer: %v", err)
+	}
+	if err := client.WaitForServerDeleted(ctx, created.ID, fastWait); err != nil {
+		t.Fatalf("WaitForServerDeleted: %v", err)
+	}
+	if v, _ := client.GetVolume(ctx, vol.ID); v.Status != "available" {
+		t.Errorf("boot volume status after delete = %q, want available", v.Status)
+	}
+
+	if got := srv.Requests(); got[0].Method != http.MethodPost || got[0].Path != "/v3/auth/tokens" {
+		t.Errorf("first request = %+v, want the token request", got[0])
+	}
+}
+
+func TestServer_TransitionDelay(t *testing.T) {
+	_, client := newServerAndClient(t, WithTransitionDelay(50*time.Millisecond))
+	ctx := context.Background()
+
+	vol, err := client.CreateVolume(ctx, conoha.CreateVolumeRequest{Size: 100, Name: "data"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.GetVolume(ctx, vol.ID)
+	if err != nil || got.Status != "creating" {
+		t.Fatalf("GetVolume right after create = %v, %v; want creating", got, err)
+	}
+	var seen []string
+	opts := *fastWait
+	opts.Progress = func(status string) { seen = append(seen, status) }
+	if _, err := client.WaitForVolumeStatus(ctx, vol.ID, "available", &opts); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) < 2 || seen[0] != "creating" {
+		t.Errorf("statuses seen = %v, want creating before available", seen)
+	}
+
+	if err := client.DeleteVolume(ctx, vol.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := client.GetVolume(ctx, vol.ID); got == nil || got.Status != "deleting" {
+		t.Errorf("volume right after delete = %+v, want deleting", got)
+	}
+	if err := client.WaitForVolumeDeleted(ctx, vol.ID, fastWait); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServer_SetStatus(t *testing.T) {
+	srv, client := newServerAndClient(t)
+	ctx := context.Background()
+
+	vol, err := client.CreateVolume(ctx, conoha.CreateVolumeRequest{Size: 100, Name: "data"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !srv.SetStatus("volumes", vol.ID, "error") {
+		t.Fatal("SetStatus: volume not found")
+	}
+	_, err = client.WaitForVolumeStatus(ctx, vol.ID, "available", fastWait)
+	var statusErr *conoha.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != "error" {
+		t.Errorf("err = %v, want a StatusError for status error", err)
+	}
+	if srv.SetStatus("volumes", "missing", "error") {
+		t.Error("SetStatus of a missing volume reported true")
+	}
+}
+
+func TestServer_Faults(t *testing.T) {
+	srv, client := newServerAndClient(t)
+	ctx := context.Background()
+	client = srv.NewClient(conoha.WithRetryPolicy(conoha.RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+
+	srv.InjectFault(Fault{Method: http.MethodGet, Path: "/volumes", StatusCode: http.StatusServiceUnavailable, Times: 2})
+	if _, err := client.ListVolumes(ctx, nil); err != nil {
+		t.Fatalf("ListVolumes with 2 transient failures: %v", err)
+	}
+
+	srv.InjectFault(Fault{Path: "/servers", StatusCode: http.StatusTooManyRequests})
+	_, err := client.ListServers(ctx, nil)
+	if !errors.Is(err, conoha.ErrRateLimited) {
+		t.Errorf("ListServers with a persistent 429: err = %v, want ErrRateLimited", err)
+	}
+	srv.ClearFaults()
+	if _, err := client.ListServers(ctx, nil); err != nil {
+		t.Errorf("ListServers after ClearFaults: %v", err)
+	}
+
+	srv.InjectFault(Fault{Path: "/flavors", Latency: time.Second, Times: 1})
+	tctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.ListFlavors(tctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ListFlavors with latency: err = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestServer_RetryAfter(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.InjectFault(Fault{StatusCode: http.StatusTooManyRequests, RetryAfter: 1500 * time.Millisecond})
+
+	resp, err := http.Get(srv.URL + "/compute/v2.1/servers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "2" {
+		t.Errorf("status %d, Retry-After %q; want 429 and 2", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+}
+
+func TestServer_Authentication(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	client := conoha.NewClient(conoha.WithEndpoints(conoha.Endpoints{Identity: srv.URL + "/v3"}))
+	if _, err := client.Authenticate(ctx, UserID, "wrong", TenantID); !errors.Is(err, conoha.ErrUnauthorized) {
+		t.Errorf("Authenticate with a wrong password: err = %v, want ErrUnauthorized", err)
+	}
+	if _, err := client.AuthenticateByName(ctx, UserName, Password, TenantName); err != nil {
+		t.Fatalf("AuthenticateByName: %v", err)
+	}
+	if _, err := client.AuthenticateWithCredential(ctx, AccessKey, SecretKey); err != nil {
+		t.Fatalf("AuthenticateWithCredential: %v", err)
+	}
+	if _, err := client.AuthenticateWithCredential(ctx, AccessKey, "wrong"); !errors.Is(err, conoha.ErrUnauthorized) {
+		t.Errorf("AuthenticateWithCredential with a wrong secret: err = %v, want ErrUnauthorized", err)
+	}
+
+	// Revoked tokens are re-issued transparently.
+	client = srv.NewClient()
+	if _, err := client.ListFlavors(ctx); err != nil {
+		t.Fatal(err)
+	}
+	srv.RevokeTokens()
+	if _, err := client.ListFlavors(ctx); err != nil {
+		t.Fatalf("ListFlavors after RevokeTokens: %v", err)
+	}
+	if ep := client.Endpoint(conoha.ServiceTypeCompute); ep != srv.URL+"/compute/v2.1" {
+		t.Errorf("compute endpoint = %q", ep)
+	}
+}
+
+func TestServer_ObjectStorage(t *testing.T) {
+	_, client := newServerAndClient(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"files", "files-versions", "segments"} {
+		if err := client.CreateContainer(ctx, name); err != nil {
+			t.Fatalf("CreateContainer %s: %v", name, err)
+		}
+	}
+	if err := client.EnableVersioning(ctx, "files", "files-versions"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UploadObject(ctx, "files", "docs/a.txt", strings.NewReader("v1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UploadObject(ctx, "files", "docs/a.txt", strings.NewReader("v2")); err != nil {
+		t.Fatal(err)
+	}
+	if got := download(t, client, "files", "docs/a.txt"); got != "v2" {
+		t.Errorf("object = %q, want v2", got)
+	}
+	if err := client.DeleteObject(ctx, "files", "docs/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got := download(t, client, "files", "docs/a.txt"); got != "v1" {
+		t.Errorf("object after deleting the current version = %q, want v1", got)
+	}
+
+	for i, part := range []string{"hello, ", "world"} {
+		name := "big/" + string(rune('0'+i))
+		if err := client.UploadObject(ctx, "segments", name, strings.NewReader(part)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.CreateDLOManifest(ctx, "files", "big.txt", "segments", "big/"); err != nil {
+		t.Fatal(err)
+	}
+	if got := download(t, client, "files", "big.txt"); got != "hello, world" {
+		t.Errorf("DLO = %q", got)
+	}
+
+	objects, err := client.ListObjects(ctx, "files", &conoha.ListObjectsOptions{Prefix: "docs/"})
+	if err != nil || len(objects) != 1 || objects[0].Name != "docs/a.txt" {
+		t.Errorf("ListObjects = %+v, %v", objects, err)
+	}
+	// A page ending with a pseudo-directory continues after it.
+	if err := client.UploadObject(ctx, "files", "zz.txt", strings.NewReader("z")); err != nil {
+		t.Fatal(err)
+	}
+	objects, err = client.ListAllObjects(ctx, "files", &conoha.ListObjectsOptions{Limit: 2, Delimiter: "/"})
+	if err != nil || len(objects) != 3 || objects[0].Name != "big.txt" || objects[1].Subdir != "docs/" || objects[2].Name != "zz.txt" {
+		t.Errorf("ListAllObjects with a delimiter = %+v, %v", objects, err)
+	}
+	info, err := client.GetAccountInfo(ctx)
+	if err != nil || info.ContainerCount != 3 {
+		t.Errorf("GetAccountInfo = %+v, %v", info, err)
+	}
+	if err := client.DeleteContainer(ctx, "segments"); !errors.Is(err, conoha.ErrConflict) {
+		t.Errorf("DeleteContainer of a non-empty container: err = %v, want ErrConflict", err)
+	}
+}
+
+func download(t *testing.T, client *conoha.Client, container, name string) string {
+	t.Helper()
+	body, err := client.DownloadObject(context.Background(), container, name)
+	if err != nil {
+		t.Fatalf("DownloadObject %s/%s: %v", container, name, err)
+	}
+	defer body.Close()
+	b, _ := io.ReadAll(body)
+	return string(b)
+}
+
+func TestServer_NetworkAndDNS(t *testing.T) {
+	_, client := newServerAndClient(t)
+	ctx := context.Background()
+
+	sg, err := client.CreateSecurityGroup(ctx, "web", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proto, port := "tcp", 443
+	if _, err := client.CreateSecurityGroupRule(ctx, conoha.CreateSecurityGroupRuleRequest{
+		SecurityGroupID: sg.ID, Direction: "ingress", EtherType: "IPv4",
+		Protocol: &proto, PortRangeMin: &port, PortRangeMax: &port,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sg, err = client.GetSecurityGroup(ctx, sg.ID)
+	if err != nil || len(sg.Rules) != 3 {
+		t.Errorf("security group has %d rules, want 2 default egress rules and 1 ingress rule (err %v)", len(sg.Rules), err)
+	}
+
+	network, err := client.CreateNetwork(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreatePort(ctx, conoha.CreatePortRequest{NetworkID: network.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteNetwork(ctx, network.ID); !errors.Is(err, conoha.ErrConflict) {
+		t.Errorf("DeleteNetwork with a port: err = %v, want ErrConflict", err)
+	}
+
+	domain, err := client.CreateDomain(ctx, conoha.CreateDomainRequest{Name: "example.com.", TTL: 300, Email: "admin@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateDNSRecord(ctx, domain.UUID, conoha.CreateDNSRecordRequest{Name: "www.example.com.", Type: "A", Data: "203.0.113.10"}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := client.ListDNSRecords(ctx, domain.UUID, nil)
+	if err != nil || len(records) != 1 || records[0].TTL != 300 {
+		t.Errorf("ListDNSRecords = %+v, %v", records, err)
+	}
+}
+
+func TestServer_LoadBalancer(t *testing.T) {
+	_, client := newServerAndClient(t, WithTransitionDelay(time.Hour))
+	ctx := context.Background()
+
+	lb, err := client.CreateLoadBalancer(ctx, "lb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lb.ProvisioningStatus != "PENDING_CREATE" {
+		t.Errorf("provisioning status = %q, want PENDING_CREATE", lb.ProvisioningStatus)
+	}
+	if _, err := client.CreateListener(ctx, "http", "HTTP", 80, lb.ID); !errors.Is(err, conoha.ErrConflict) {
+		t.Errorf("CreateListener on a pending load balancer: err = %v, want ErrConflict", err)
+	}
+}
+
+func TestServer_NotImplemented(t *testing.T) {
+	_, client := newServerAndClient(t)
+	_, err := client.ListRoles(context.Background())
+	var apiErr *conoha.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotImplemented {
+		t.Errorf("err = %v, want a 501 APIError", err)
+	}
+}
@@ -0,0 +1,248 @@
+package conohatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// kind describes a resource type of the API.
+type kind struct {
+	singular string // JSON key of one resource, e.g. "server"
+	plural   string // JSON key of a list, e.g. "servers"
+	idKey    string // "id", or "uuid" for DNS
+
+	// statusKey is the field set by transitions, e.g. "status".
+	statusKey string
+	// deleting is the status of a resource being deleted when the server
+	// has a transition delay. Empty deletes resources at once.
+	deleting string
+}
+
+// resource is one stored resource, kept as its JSON document.
+type resource struct {
+	fields  map[string]any
+	pending *transition
+}
+
+// transition is a status change that takes effect at a given time.
+type transition struct {
+	at     time.Time
+	status string
+	delete bool
+	// done, if set, runs when the transition takes effect. s.mu is held.
+	done func()
+}
+
+// collection holds the resources of a kind in creation order.
+type collection struct {
+	kind  kind
+	items map[string]*resource
+	order []string
+}
+
+// collection returns the collection of k, creating it on first use.
+// s.mu must be held.
+func (s *Server) collection(k kind) *collection {
+	c := s.colls[k.plural]
+	if c == nil {
+		c = &collection{kind: k, items: make(map[string]*resource)}
+		s.colls[k.plural] = c
+	}
+	return c
+}
+
+// insert stores fields under a new ID, or under fields[idKey] if it is
+// already set, stamping the creation time. s.mu must be held.
+func (s *Server) insert(k kind, fields map[string]any) *resource {
+	c := s.collection(k)
+	id, _ := fields[k.idKey].(string)
+	if id == "" {
+		id = s.newID()
+		fields[k.idKey] = id
+	}
+	if _, ok := c.items[id]; !ok {
+		c.order = append(c.order, id)
+	}
+	r := &resource{fields: fields}
+	c.items[id] = r
+	return r
+}
+
+// lookup returns the resource of k with the given ID, or nil. s.mu must be
+// held.
+func (s *Server) lookup(k kind, id string) *resource {
+	return s.collection(k).items[id]
+}
+
+// remove deletes a resource at once. s.mu must be held.
+func (s *Server) remove(k kind, id string) {
+	c := s.collection(k)
+	if _, ok := c.items[id]; !ok {
+		return
+	}
+	delete(c.items, id)
+	for i, v := range c.order {
+		if v == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// transit puts r into the transitional status from and schedules its
+// change to status to after the server's transition delay. done, if not
+// nil, runs when the change takes effect. s.mu must be held.
+func (s *Server) transit(k kind, r *resource, from, to string, done func()) {
+	r.fields[k.statusKey] = from
+	r.pending = &transition{at: time.Now().Add(s.delay), status: to, done: done}
+}
+
+// destroy deletes r, after the transition delay if the server has one.
+// s.mu must be held.
+func (s *Server) destroy(k kind, r *resource) {
+	id, _ := r.fields[k.idKey].(string)
+	if s.delay == 0 || k.deleting == "" {
+		s.remove(k, id)
+		return
+	}
+	r.fields[k.statusKey] = k.deleting
+	r.pending = &transition{at: time.Now().Add(s.delay), delete: true}
+}
+
+// settle applies the transitions that are due. s.mu must be held.
+func (s *Server) settle() {
+	now := time.Now()
+	for _, c := range s.colls {
+		for _, id := range append([]string(nil), c.order...) {
+			r := c.items[id]
+			if r == nil || r.pending == nil || r.pending.at.After(now) {
+				continue
+			}
+			p := r.pending
+			r.pending = nil
+			if p.delete {
+				s.remove(c.kind, id)
+				continue
+			}
+			r.fields[c.kind.statusKey] = p.status
+			if p.done != nil {
+				p.done()
+			}
+		}
+	}
+}
+
+// list returns copies of the resources of k that match the filter query
+// parameters of q, paginated by its limit, marker and offset. The total
+// number of matches is returned as well. s.mu must be held.
+func (s *Server) list(k kind, q url.Values, match func(fields map[string]any) bool) ([]map[string]any, int) {
+	c := s.collection(k)
+	var items []map[string]any
+	for _, id := range c.order {
+		fields := c.items[id].fields
+		if !matchQuery(fields, q) || (match != nil && !match(fields)) {
+			continue
+		}
+		items = append(items, fields)
+	}
+	total := len(items)
+
+	if marker := q.Get("marker"); marker != "" {
+		for i, fields := range items {
+			if fields[k.idKey] == marker {
+				items = items[i+1:]
+				break
+			}
+		}
+	}
+	if offset, _ := strconv.Atoi(q.Get("offset")); offset > 0 {
+		if offset > len(items) {
+			offset = len(items)
+		}
+		items = items[offset:]
+	}
+	if limit, _ := strconv.Atoi(q.Get("limit")); limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+
+	out := make([]map[string]any, len(items))
+	for i, fields := range items {
+		out[i] = clone(fields)
+	}
+	return out, total
+}
+
+// controlParams are the query parameters that are not field filters.
+var controlParams = map[string]bool{
+	"limit": true, "marker": true, "offset": true, "sort": true, "sort_key": true,
+	"sort_dir": true, "sort_type": true, "with_count": true, "format": true, "force": true,
+}
+
+// matchQuery reports whether fields has the value of every filter parameter
+// of q.
+func matchQuery(fields map[string]any, q url.Values) bool {
+	for key := range q {
+		if controlParams[key] {
+			continue
+		}
+		v, ok := fields[key]
+		if !ok || fmt.Sprint(v) != q.Get(key) {
+			return false
+		}
+	}
+	return true
+}
+
+// merge copies the fields of update into r, refreshing its update time.
+func merge(r *resource, update map[string]any, updatedKey string) {
+	for k, v := range update {
+		r.fields[k] = v
+	}
+	if updatedKey != "" {
+		r.fields[updatedKey] = now()
+	}
+}
+
+// clone deep-copies a JSON document so that it can be written out after
+// s.mu is released.
+func clone(fields map[string]any) map[string]any {
+	b, err := json.Marshal(fields)
+	if err != nil {
+		panic(err) // fields only ever holds JSON values
+	}
+	var out map[string]any
+	json.Unmarshal(b, &out)
+	return out
+}
+
+// object returns body[key] as a JSON object, or nil.
+func object(body map[string]any, key string) map[string]any {
+	m, _ := body[key].(map[string]any)
+	return m
+}
+
+// str returns m[key] as a string, or "".
+func str(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// wrapped decodes a request body of the form {"<key>": {...}}, writing a
+// 400 response and returning nil if it is not.
+func wrapped(w http.ResponseWriter, r *http.Request, key string) map[string]any {
+	body, err := readJSON(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body: "+err.Error())
+		return nil
+	}
+	m := object(body, key)
+	if m == nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("request body must contain %q", key))
+		return nil
+	}
+	return m
+}
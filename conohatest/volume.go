@@ -0,0 +1,187 @@
+package conohatest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+var (
+	volumeKind     = kind{singular: "volume", plural: "volumes", idKey: "id", statusKey: "status", deleting: "deleting"}
+	volumeTypeKind = kind{singular: "volume_type", plural: "volume_types", idKey: "id"}
+	backupKind     = kind{singular: "backup", plural: "backups", idKey: "id", statusKey: "status", deleting: "deleting"}
+)
+
+// serveBlockStorage serves volumes, volume types and the listing of
+// backups. Auto-backup settings are not emulated.
+func (s *Server) serveBlockStorage(w http.ResponseWriter, r *http.Request, segs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case len(segs) == 1 && segs[0] == "volumes" && r.Method == http.MethodGet:
+		items, total := s.list(volumeKind, r.URL.Query(), nil)
+		brief := make([]map[string]any, len(items))
+		for i, item := range items {
+			brief[i] = map[string]any{"id": item["id"], "name": item["name"], "links": item["links"]}
+		}
+		resp := map[string]any{"volumes": brief}
+		if r.URL.Query().Get("with_count") == "true" {
+			resp["count"] = total
+		}
+		writeJSON(w, http.StatusOK, resp)
+	case len(segs) == 2 && segs[0] == "volumes" && segs[1] == "detail" && r.Method == http.MethodGet:
+		items, _ := s.list(volumeKind, r.URL.Query(), nil)
+		writeJSON(w, http.StatusOK, map[string]any{"volumes": items})
+	case len(segs) == 1 && segs[0] == "volumes" && r.Method == http.MethodPost:
+		s.createVolume(w, r)
+	case len(segs) >= 2 && segs[0] == "volumes":
+		vol := s.lookup(volumeKind, segs[1])
+		if vol == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Volume %s could not be found.", segs[1]))
+			return
+		}
+		s.serveVolume(w, r, vol, segs[2:])
+	case len(segs) == 1 && segs[0] == "types" && r.Method == http.MethodGet:
+		items, _ := s.list(volumeTypeKind, r.URL.Query(), nil)
+		writeJSON(w, http.StatusOK, map[string]any{"volume_types": items})
+	case len(segs) == 2 && segs[0] == "types" && r.Method == http.MethodGet:
+		vt := s.lookup(volumeTypeKind, segs[1])
+		if vt == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Volume type %s could not be found.", segs[1]))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"volume_type": clone(vt.fields)})
+	case len(segs) == 1 && segs[0] == "backups" && r.Method == http.MethodGet,
+		len(segs) == 2 && segs[0] == "backups" && segs[1] == "detail" && r.Method == http.MethodGet:
+		items, _ := s.list(backupKind, r.URL.Query(), nil)
+		writeJSON(w, http.StatusOK, map[string]any{"backups": items})
+	case len(segs) == 2 && segs[0] == "backups" && r.Method == http.MethodGet:
+		b := s.lookup(backupKind, segs[1])
+		if b == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Backup %s could not be found.", segs[1]))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"backup": clone(b.fields)})
+	default:
+		notImplemented(w, r)
+	}
+}
+
+func (s *Server) createVolume(w http.ResponseWriter, r *http.Request) {
+	req := wrapped(w, r, "volume")
+	if req == nil {
+		return
+	}
+	size, _ := req["size"].(float64)
+	if size <= 0 {
+		writeError(w, http.StatusBadRequest, "Volume size must be greater than 0.")
+		return
+	}
+	volumeType := str(req, "volume_type")
+	if volumeType == "" {
+		volumeType = AdditionalVolumeType
+	}
+	found := false
+	for _, vt := range s.collection(volumeTypeKind).items {
+		found = found || vt.fields["name"] == volumeType || vt.fields["id"] == volumeType
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Volume type %s could not be found.", volumeType))
+		return
+	}
+	bootable := "false"
+	if ref := str(req, "imageRef"); ref != "" {
+		img := s.lookup(imageKind, ref)
+		if img == nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Image %s could not be found.", ref))
+			return
+		}
+		if img.fields["status"] != "active" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Image %s is not active.", ref))
+			return
+		}
+		bootable = "true"
+	}
+	if src := str(req, "source_volid"); src != "" && s.lookup(volumeKind, src) == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Volume %s could not be found.", src))
+		return
+	}
+
+	vol := s.insert(volumeKind, map[string]any{
+		"status":            "creating",
+		"size":              int(size),
+		"availability_zone": "nova",
+		"created_at":        now(),
+		"updated_at":        now(),
+		"name":              str(req, "name"),
+		"description":       req["description"],
+		"volume_type":       volumeType,
+		"snapshot_id":       nil,
+		"source_volid":      nilIfEmpty(str(req, "source_volid")),
+		"metadata":          map[string]any{},
+		"user_id":           UserID,
+		"bootable":          bootable,
+		"encrypted":         false,
+		"multiattach":       false,
+		"attachments":       []any{},
+		"links":             []any{},
+	})
+	out := clone(vol.fields)
+	s.transit(volumeKind, vol, "creating", "available", nil)
+	writeJSON(w, http.StatusAccepted, map[string]any{"volume": out})
+}
+
+func (s *Server) serveVolume(w http.ResponseWriter, r *http.Request, vol *resource, segs []string) {
+	switch {
+	case len(segs) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"volume": clone(vol.fields)})
+	case len(segs) == 0 && r.Method == http.MethodPut:
+		req := wrapped(w, r, "volume")
+		if req == nil {
+			return
+		}
+		update := map[string]any{}
+		for _, k := range []string{"name", "description"} {
+			if v, ok := req[k]; ok {
+				update[k] = v
+			}
+		}
+		merge(vol, update, "updated_at")
+		writeJSON(w, http.StatusOK, map[string]any{"volume": clone(vol.fields)})
+	case len(segs) == 0 && r.Method == http.MethodDelete:
+		status := vol.fields["status"]
+		if status != "available" && status != "error" && r.URL.Query().Get("force") != "true" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid volume: Volume status must be available or error, but current status is: %s.", status))
+			return
+		}
+		s.destroy(volumeKind, vol)
+		w.WriteHeader(http.StatusAccepted)
+	case len(segs) == 1 && segs[0] == "action" && r.Method == http.MethodPost:
+		req := wrapped(w, r, "os-volume_upload_image")
+		if req == nil {
+			return
+		}
+		status := vol.fields["status"].(string)
+		if status != "available" && status != "in-use" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid volume: Volume status must be available or in-use, but current status is: %s.", status))
+			return
+		}
+		img := s.insert(imageKind, newImage(str(req, "image_name"), "private"))
+		img.fields["disk_format"] = "qcow2"
+		img.fields["container_format"] = "bare"
+		img.fields["size"] = int64(vol.fields["size"].(int)) << 30
+		s.transit(imageKind, img, "saving", "active", nil)
+		s.transit(volumeKind, vol, "uploading", status, nil)
+		writeJSON(w, http.StatusAccepted, map[string]any{"os-volume_upload_image": map[string]any{
+			"id":               vol.fields["id"],
+			"status":           "uploading",
+			"size":             vol.fields["size"],
+			"image_id":         img.fields["id"],
+			"container_format": "bare",
+			"disk_format":      "qcow2",
+			"image_name":       img.fields["name"],
+		}})
+	default:
+		notImplemented(w, r)
+	}
+}
@@ -0,0 +1,280 @@
+package conoha
+
+import (
+	"strings"
+)
+
+// EndpointSource tells where the URL of an endpoint came from.
+type EndpointSource string
+
+// Endpoint sources, in order of precedence.
+const (
+	// EndpointSourceExplicit is a URL set with a With*URL option or
+	// WithEndpoints.
+	EndpointSourceExplicit EndpointSource = "explicit"
+	// EndpointSourceResolver is a URL returned by the EndpointResolver set
+	// with WithEndpointResolver.
+	EndpointSourceResolver EndpointSource = "resolver"
+	// EndpointSourceCatalog is a URL taken from the service catalog of the
+	// current token.
+	EndpointSourceCatalog EndpointSource = "catalog"
+	// EndpointSourceRegion is a URL generated from the region pattern
+	// https://{service}.{region}.conoha.io.
+	EndpointSourceRegion EndpointSource = "region"
+)
+
+// endpointServices lists the service types the client has an endpoint for,
+// in the order of Endpoints.
+var endpointServices = [...]string{
+	ServiceTypeIdentity,
+	ServiceTypeCompute,
+	ServiceTypeBlockStorage,
+	ServiceTypeImage,
+	ServiceTypeNetwork,
+	ServiceTypeLBaaS,
+	ServiceTypeObjectStore,
+	ServiceTypeDNS,
+}
+
+// ResolvedEndpoint is the endpoint URL the client uses for a service, and
+// where it came from.
+type ResolvedEndpoint struct {
+	Service string // One of the ServiceType* constants.
+	URL     string
+	Source  EndpointSource
+}
+
+// Endpoints returns the endpoint of every service the client talks to, in
+// a fixed order starting with identity. The result is a snapshot; it does
+// not change when a later authentication updates the endpoints.
+func (c *Client) Endpoints() []ResolvedEndpoint {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	endpoints := make([]ResolvedEndpoint, 0, len(endpointServices))
+	for _, service := range endpointServices {
+		source := c.endpointSources[service]
+		if c.explicitURLs[service] {
+			source = EndpointSourceExplicit
+		}
+		endpoints = append(endpoints, ResolvedEndpoint{
+			Service: service,
+			URL:     *c.endpointField(service),
+			Source:  source,
+		})
+	}
+	return endpoints
+}
+
+// Endpoint returns the current endpoint URL of service (one of the
+// ServiceType* constants), or "" if the client has no endpoint for it. It
+// is safe to call while the client authenticates in another goroutine.
+func (c *Client) Endpoint(service string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if f := c.endpointField(service); f != nil {
+		return *f
+	}
+	return ""
+}
+
+func (c *Client) identityURL() string      { return c.Endpoint(ServiceTypeIdentity) }
+func (c *Client) computeURL() string       { return c.Endpoint(ServiceTypeCompute) }
+func (c *Client) blockStorageURL() string  { return c.Endpoint(ServiceTypeBlockStorage) }
+func (c *Client) imageServiceURL() string  { return c.Endpoint(ServiceTypeImage) }
+func (c *Client) networkingURL() string    { return c.Endpoint(ServiceTypeNetwork) }
+func (c *Client) lbaasURL() string         { return c.Endpoint(ServiceTypeLBaaS) }
+func (c *Client) objectStorageURL() string { return c.Endpoint(ServiceTypeObjectStore) }
+func (c *Client) dnsServiceURL() string    { return c.Endpoint(ServiceTypeDNS) }
+
+// Catalog returns a copy of the service catalog of the current token, with
+// the endpoints of every region and interface, or nil before the client
+// has authenticated.
+func (c *Client) Catalog() []ServiceCatalog {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.catalog == nil {
+		return nil
+	}
+	catalog := make([]ServiceCatalog, len(c.catalog))
+	for i, svc := range c.catalog {
+		catalog[i] = svc
+		catalog[i].Endpoints = append([]Endpoint(nil), svc.Endpoints...)
+	}
+	return catalog
+}
+
+// EndpointResolver chooses endpoint URLs in place of the service catalog
+// and the region pattern, e.g. to use the internal interface, a proxy or a
+// mirror. URLs set explicitly with a With*URL option still take priority.
+type EndpointResolver interface {
+	// ResolveEndpoint returns the URL of service (one of the ServiceType*
+	// constants) in region, or ok false to keep the default. catalog is
+	// the service catalog of the current token, or nil before the client
+	// has authenticated.
+	//
+	// The URL is used as is, so it must include the API version path
+	// (e.g. "/v2.1" for compute). ResolveEndpoint is called with the
+	// client locked and must not call methods of the client.
+	ResolveEndpoint(service, region string, catalog []ServiceCatalog) (url string, ok bool)
+}
+
+// EndpointResolverFunc adapts a function to an EndpointResolver.
+type EndpointResolverFunc func(service, region string, catalog []ServiceCatalog) (string, bool)
+
+// ResolveEndpoint calls f(service, region, catalog).
+func (f EndpointResolverFunc) ResolveEndpoint(service, region string, catalog []ServiceCatalog) (string, bool) {
+	return f(service, region, catalog)
+}
+
+// WithEndpointResolver sets a resolver that chooses the endpoint URLs not
+// set explicitly. It is consulted when the client is created and whenever
+// authentication returns a new service catalog.
+//
+//	// Route object storage through a caching proxy.
+//	client := conoha.NewClient(conoha.WithEndpointResolver(conoha.EndpointResolverFunc(
+//	    func(service, region string, _ []conoha.ServiceCatalog) (string, bool) {
+//	        if service == conoha.ServiceTypeObjectStore {
+//	            return "https://swift-proxy.internal/v1", true
+//	        }
+//	        return "", false
+//	    })))
+func WithEndpointResolver(r EndpointResolver) ClientOption {
+	return func(c *Client) {
+		c.resolver = r
+	}
+}
+
+// InterfaceResolver returns an EndpointResolver that takes the endpoints of
+// the given catalog interface ("internal" or "admin") instead of "public".
+// Services without such an endpoint in the client's region keep their
+// default URL.
+func InterfaceResolver(iface string) EndpointResolver {
+	return EndpointResolverFunc(func(service, region string, catalog []ServiceCatalog) (string, bool) {
+		for _, svc := range catalog {
+			if catalogServiceType(svc.Type) != service {
+				continue
+			}
+			if u := catalogURL(svc, iface, region); u != "" {
+				return normalizeCatalogURL(service, u), true
+			}
+		}
+		return "", false
+	})
+}
+
+// endpointField returns the URL field of service, or nil for a service the
+// client has no endpoint for.
+func (c *Client) endpointField(service string) *string {
+	switch service {
+	case ServiceTypeIdentity:
+		return &c.IdentityURL
+	case ServiceTypeCompute:
+		return &c.ComputeURL
+	case ServiceTypeBlockStorage:
+		return &c.BlockStorageURL
+	case ServiceTypeImage:
+		return &c.ImageServiceURL
+	case ServiceTypeNetwork:
+		return &c.NetworkingURL
+	case ServiceTypeLBaaS:
+		return &c.LBaaSURL
+	case ServiceTypeObjectStore:
+		return &c.ObjectStorageURL
+	case ServiceTypeDNS:
+		return &c.DNSServiceURL
+	}
+	return nil
+}
+
+// setEndpoint sets the URL of service and records its source. The caller
+// must hold c.mu or own c exclusively.
+func (c *Client) setEndpoint(service, url string, source EndpointSource) {
+	*c.endpointField(service) = url
+	if c.endpointSources == nil {
+		c.endpointSources = make(map[string]EndpointSource)
+	}
+	c.endpointSources[service] = source
+}
+
+// applyResolver sets the endpoints the resolver returns a URL for, except
+// explicit ones. The caller must hold c.mu or own c exclusively.
+func (c *Client) applyResolver(catalog []ServiceCatalog) {
+	if c.resolver == nil {
+		return
+	}
+	for _, service := range endpointServices {
+		if c.explicitURLs[service] {
+			continue
+		}
+		if u, ok := c.resolver.ResolveEndpoint(service, c.Region, catalog); ok && u != "" {
+			c.setEndpoint(service, u, EndpointSourceResolver)
+		}
+	}
+}
+
+// catalogServiceType maps a catalog service type to the ServiceType*
+// constant of its endpoint, or "" if the client does not use the service.
+func catalogServiceType(catalogType string) string {
+	if catalogType == "volumev3" {
+		return ServiceTypeBlockStorage
+	}
+	for _, service := range endpointServices {
+		if service == catalogType {
+			return service
+		}
+	}
+	return ""
+}
+
+// catalogURL returns the URL of the first endpoint of svc with the given
+// interface in region, without a trailing slash, or "" if there is none.
+// An empty region matches every endpoint.
+func catalogURL(svc ServiceCatalog, iface, region string) string {
+	for _, ep := range svc.Endpoints {
+		if ep.Interface != iface {
+			continue
+		}
+		if region != "" && ep.Region != region && ep.RegionID != region {
+			continue
+		}
+		return strings.TrimRight(ep.URL, "/")
+	}
+	return ""
+}
+
+// normalizeCatalogURL turns a catalog URL of service into the endpoint
+// URL the SDK methods append resource paths to.
+//
+// The ConoHa service catalog may return base URLs without version paths
+// (e.g. "https://networking.c3j1.conoha.io" instead of ".../v2.0"), so the
+// required API version path is added if missing.
+func normalizeCatalogURL(service, u string) string {
+	switch service {
+	case ServiceTypeIdentity:
+		return ensureVersionPath(u, "/v3")
+	case ServiceTypeCompute:
+		return ensureVersionPath(u, "/v2.1")
+	case ServiceTypeBlockStorage:
+		// Catalog may include tenant ID in path (e.g. /v3/{tenantID}).
+		// Strip everything after the version path since SDK methods add tenant ID.
+		u = ensureVersionPath(u, "/v3")
+		if idx := strings.Index(u, "/v3/"); idx >= 0 {
+			u = u[:idx+3]
+		}
+		return u
+	case ServiceTypeImage:
+		return ensureVersionPath(u, "/v2")
+	case ServiceTypeNetwork, ServiceTypeLBaaS:
+		return ensureVersionPath(u, "/v2.0")
+	case ServiceTypeObjectStore:
+		// Catalog may include /v1/AUTH_{tenantID}. Strip the AUTH_ portion
+		// since objectStoragePath() appends it.
+		if idx := strings.Index(u, "/AUTH_"); idx >= 0 {
+			u = u[:idx]
+		}
+		return ensureVersionPath(u, "/v1")
+	case ServiceTypeDNS:
+		return ensureVersionPath(u, "/v1")
+	}
+	return u
+}
@@ -0,0 +1,139 @@
+package conoha
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func endpointsByService(c *Client) map[string]ResolvedEndpoint {
+	m := make(map[string]ResolvedEndpoint)
+	for _, ep := range c.Endpoints() {
+		m[ep.Service] = ep
+	}
+	return m
+}
+
+func TestEndpoints_Sources(t *testing.T) {
+	c := NewClient(WithComputeURL("https://compute.example.com"))
+	c.updateEndpointsFromCatalog([]ServiceCatalog{{
+		Type: "volumev3",
+		Endpoints: []Endpoint{
+			{Interface: "public", Region: "c3j1", URL: "https://bs.example.com/v3/tenant"},
+		},
+	}})
+
+	eps := c.Endpoints()
+	if len(eps) != 8 || eps[0].Service != ServiceTypeIdentity {
+		t.Fatalf("Endpoints = %+v", eps)
+	}
+	got := endpointsByService(c)
+	want := map[string]ResolvedEndpoint{
+		ServiceTypeCompute:      {ServiceTypeCompute, "https://compute.example.com/v2.1", EndpointSourceExplicit},
+		ServiceTypeBlockStorage: {ServiceTypeBlockStorage, "https://bs.example.com/v3", EndpointSourceCatalog},
+		ServiceTypeNetwork:      {ServiceTypeNetwork, "https://networking.c3j1.conoha.io/v2.0", EndpointSourceRegion},
+	}
+	for service, w := range want {
+		if got[service] != w {
+			t.Errorf("%s = %+v, want %+v", service, got[service], w)
+		}
+	}
+}
+
+func TestCatalog_ReturnsCopy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Subject-Token", "tok")
+		w.WriteHeader(201)
+		w.Write([]byte(`{"token":{"project":{"id":"tenant"},"catalog":[{"type":"compute","endpoints":[
+			{"interface":"public","region":"c3j1","url":"https://compute.example.com/v2.1"}
+		]}]}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithIdentityURL(server.URL))
+	if c.Catalog() != nil {
+		t.Error("Catalog before Authenticate should be nil")
+	}
+	_, err := c.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+
+	catalog := c.Catalog()
+	if len(catalog) != 1 || catalog[0].Endpoints[0].URL != "https://compute.example.com/v2.1" {
+		t.Fatalf("Catalog = %+v", catalog)
+	}
+	catalog[0].Endpoints[0].URL = "changed"
+	if c.Catalog()[0].Endpoints[0].URL == "changed" {
+		t.Error("Catalog shares its endpoints with the client")
+	}
+}
+
+func TestWithEndpointResolver(t *testing.T) {
+	var calls []string
+	resolver := EndpointResolverFunc(func(service, region string, catalog []ServiceCatalog) (string, bool) {
+		calls = append(calls, fmt.Sprintf("%s/%s/%d", service, region, len(catalog)))
+		switch service {
+		case ServiceTypeObjectStore:
+			return "https://proxy.example.com/swift", true
+		case ServiceTypeCompute:
+			return "https://compute.override.example.com/v2.1", true
+		}
+		return "", false
+	})
+	c := NewClient(
+		WithRegion("c3j2"),
+		WithComputeURL("https://compute.example.com"),
+		WithEndpointResolver(resolver),
+	)
+
+	got := endpointsByService(c)
+	if ep := got[ServiceTypeObjectStore]; ep.URL != "https://proxy.example.com/swift" || ep.Source != EndpointSourceResolver {
+		t.Errorf("object-store = %+v, want the resolver URL verbatim", ep)
+	}
+	if ep := got[ServiceTypeCompute]; ep.Source != EndpointSourceExplicit {
+		t.Errorf("compute = %+v, want the explicit URL", ep)
+	}
+	if calls[0] != "identity/c3j2/0" {
+		t.Errorf("first call = %q", calls[0])
+	}
+
+	// The resolver also wins over the catalog.
+	c.updateEndpointsFromCatalog([]ServiceCatalog{{
+		Type: ServiceTypeObjectStore,
+		Endpoints: []Endpoint{
+			{Interface: "public", Region: "c3j2", URL: "https://object.example.com/v1/AUTH_tenant"},
+		},
+	}})
+	if ep := endpointsByService(c)[ServiceTypeObjectStore]; ep.URL != "https://proxy.example.com/swift" {
+		t.Errorf("object-store after catalog = %+v", ep)
+	}
+}
+
+func TestInterfaceResolver(t *testing.T) {
+	c := NewClient(WithEndpointResolver(InterfaceResolver("internal")))
+	c.updateEndpointsFromCatalog([]ServiceCatalog{
+		{
+			Type: ServiceTypeNetwork,
+			Endpoints: []Endpoint{
+				{Interface: "public", Region: "c3j1", URL: "https://networking.c3j1.conoha.io"},
+				{Interface: "internal", Region: "c3j2", URL: "https://net.c3j2.internal"},
+				{Interface: "internal", Region: "c3j1", URL: "https://net.c3j1.internal/"},
+			},
+		},
+		{
+			Type: ServiceTypeCompute,
+			Endpoints: []Endpoint{
+				{Interface: "public", Region: "c3j1", URL: "https://compute.c3j1.conoha.io/v2.1"},
+			},
+		},
+	})
+
+	got := endpointsByService(c)
+	if ep := got[ServiceTypeNetwork]; ep.URL != "https://net.c3j1.internal/v2.0" || ep.Source != EndpointSourceResolver {
+		t.Errorf("network = %+v, want the internal endpoint", ep)
+	}
+	if ep := got[ServiceTypeCompute]; ep.URL != "https://compute.c3j1.conoha.io/v2.1" || ep.Source != EndpointSourceCatalog {
+		t.Errorf("compute = %+v, want the public endpoint", ep)
+	}
+}
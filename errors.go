@@ -0,0 +1,95 @@
+package conoha
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors for classifying API errors with errors.Is:
+//
+//	if errors.Is(err, conoha.ErrNotFound) {
+//	    // the server is already gone
+//	}
+var (
+	ErrNotFound      = errors.New("conoha: resource not found")
+	ErrConflict      = errors.New("conoha: conflict")
+	ErrUnauthorized  = errors.New("conoha: unauthorized")
+	ErrForbidden     = errors.New("conoha: forbidden")
+	ErrRateLimited   = errors.New("conoha: rate limited")
+	ErrQuotaExceeded = errors.New("conoha: quota exceeded")
+)
+
+// Is reports whether e matches one of the sentinel errors, so that
+// errors.Is(err, ErrNotFound) works for API errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrQuotaExceeded:
+		return e.isQuotaExceeded()
+	}
+	return false
+}
+
+// isQuotaExceeded recognizes the ways OpenStack services report exhausted
+// quotas: Compute and Block Storage answer 413 (overLimit), Networking
+// answers 409 (OverQuota), and some services answer 400, 403 or 413 with a
+// message mentioning the quota. A 413 without one, such as an object too
+// large for Object Storage, is not a quota error.
+func (e *APIError) isQuotaExceeded() bool {
+	switch e.ErrorType {
+	case "overLimit", "OverQuota":
+		return true
+	}
+	switch e.StatusCode {
+	case http.StatusBadRequest, http.StatusForbidden, http.StatusConflict, http.StatusRequestEntityTooLarge:
+		text := strings.ToLower(e.Message + " " + e.Body)
+		return strings.Contains(text, "quota") || strings.Contains(text, "overlimit")
+	}
+	return false
+}
+
+// IsNotFound reports whether err is an API error with status 404.
+func IsNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
+
+// IsConflict reports whether err is an API error with status 409.
+func IsConflict(err error) bool { return errors.Is(err, ErrConflict) }
+
+// IsUnauthorized reports whether err is an API error with status 401.
+func IsUnauthorized(err error) bool { return errors.Is(err, ErrUnauthorized) }
+
+// IsForbidden reports whether err is an API error with status 403.
+func IsForbidden(err error) bool { return errors.Is(err, ErrForbidden) }
+
+// IsRateLimited reports whether err is an API error with status 429.
+func IsRateLimited(err error) bool { return errors.Is(err, ErrRateLimited) }
+
+// IsQuotaExceeded reports whether err is an API error caused by an
+// exhausted quota.
+func IsQuotaExceeded(err error) bool { return errors.Is(err, ErrQuotaExceeded) }
+
+// IsRetryable reports whether the failed call may succeed if repeated:
+// network errors, 429 Too Many Requests and temporary 5xx errors.
+// Context cancellation and deadline errors are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return isRetryableStatus(apiErr.StatusCode)
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
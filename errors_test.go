@@ -0,0 +1,118 @@
+package conoha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAPIError_CarriesRequestDetails(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Openstack-Request-Id", "req-123")
+		w.WriteHeader(404)
+		w.Write([]byte(`{"itemNotFound":{"message":"Instance s1 could not be found.","code":404}}`))
+	})
+	defer server.Close()
+
+	_, err := client.GetServer(context.Background(), "s1")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *APIError", err)
+	}
+	if apiErr.Method != http.MethodGet || apiErr.URL != server.URL+"/servers/s1" {
+		t.Errorf("Method/URL = %s %s", apiErr.Method, apiErr.URL)
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("RequestID = %q", apiErr.RequestID)
+	}
+	if apiErr.ErrorType != "itemNotFound" {
+		t.Errorf("ErrorType = %q", apiErr.ErrorType)
+	}
+	if !strings.Contains(apiErr.Error(), "GET "+server.URL+"/servers/s1") {
+		t.Errorf("Error() = %q, should contain method and URL", apiErr.Error())
+	}
+}
+
+func TestAPIError_ObjectStorageCarriesRequestDetails(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Trans-Id", "tx-abc")
+		w.WriteHeader(404)
+	})
+	defer server.Close()
+
+	_, err := client.GetAccountInfo(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *APIError", err)
+	}
+	if apiErr.Method != http.MethodHead || apiErr.RequestID != "tx-abc" {
+		t.Errorf("Method = %q, RequestID = %q", apiErr.Method, apiErr.RequestID)
+	}
+}
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		err    *APIError
+		target error
+		helper func(error) bool
+	}{
+		{&APIError{StatusCode: 404}, ErrNotFound, IsNotFound},
+		{&APIError{StatusCode: 409}, ErrConflict, IsConflict},
+		{&APIError{StatusCode: 401}, ErrUnauthorized, IsUnauthorized},
+		{&APIError{StatusCode: 403}, ErrForbidden, IsForbidden},
+		{&APIError{StatusCode: 429}, ErrRateLimited, IsRateLimited},
+		{newAPIError(413, "413 Request Entity Too Large", `{"overLimit":{"message":"Quota exceeded for cores","code":413}}`), ErrQuotaExceeded, IsQuotaExceeded},
+		{newAPIError(409, "409 Conflict", `{"NeutronError":{"type":"OverQuota","message":"Quota exceeded for resources: ['port'].","detail":""}}`), ErrQuotaExceeded, IsQuotaExceeded},
+		{newAPIError(403, "403 Forbidden", `{"forbidden":{"message":"Maximum number of volumes allowed (10) exceeded for quota 'volumes'.","code":403}}`), ErrQuotaExceeded, IsQuotaExceeded},
+	}
+	for _, tt := range tests {
+		wrapped := fmt.Errorf("create server: %w", tt.err)
+		if !errors.Is(wrapped, tt.target) {
+			t.Errorf("errors.Is(%d %s, %v) = false", tt.err.StatusCode, tt.err.Body, tt.target)
+		}
+		if !tt.helper(wrapped) {
+			t.Errorf("helper for %v returned false for %d", tt.target, tt.err.StatusCode)
+		}
+	}
+
+	if errors.Is(&APIError{StatusCode: 500}, ErrNotFound) {
+		t.Error("500 should not match ErrNotFound")
+	}
+	if IsQuotaExceeded(newAPIError(409, "409 Conflict", `{"conflictingRequest":{"message":"Instance is locked","code":409}}`)) {
+		t.Error("plain conflict should not be a quota error")
+	}
+	if IsQuotaExceeded(newAPIError(413, "413 Request Entity Too Large", "<html><h1>Request Entity Too Large</h1><p>The body of your request was too large for this server.</p></html>")) {
+		t.Error("an object over the size limit should not be a quota error")
+	}
+	if !IsQuotaExceeded(newAPIError(413, "413 Request Entity Too Large", "Upload exceeds quota.")) {
+		t.Error("an Object Storage account quota error should be a quota error")
+	}
+	if IsNotFound(errors.New("not found")) {
+		t.Error("non-API errors should not match")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&APIError{StatusCode: 503}, true},
+		{&APIError{StatusCode: 429}, true},
+		{&APIError{StatusCode: 404}, false},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{context.Canceled, false},
+		{fmt.Errorf("get: %w", context.DeadlineExceeded), false},
+		{errors.New("marshal request body: bad"), false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
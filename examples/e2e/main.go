@@ -4,7 +4,6 @@ package main
 import (
 	"bufio"
 	"context"
-	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -14,47 +13,12 @@ import (
 	conoha "github.com/leonunix/conohav3-golang-sdk"
 )
 
-// waitForServerStatus polls until the server reaches the target status.
-func waitForServerStatus(ctx context.Context, client *conoha.Client, serverID, target string, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for {
-		server, err := client.GetServer(ctx, serverID)
-		if err != nil {
-			return err
-		}
-		fmt.Printf("  status: %s\n", server.Status)
-		if server.Status == target {
-			return nil
-		}
-		if server.Status == "ERROR" {
-			return fmt.Errorf("server entered ERROR state")
-		}
-		if time.Now().After(deadline) {
-			return fmt.Errorf("timed out waiting for status %q (last: %q)", target, server.Status)
-		}
-		time.Sleep(5 * time.Second)
-	}
-}
-
-// waitForVolumeStatus polls until the volume reaches the target status.
-func waitForVolumeStatus(ctx context.Context, client *conoha.Client, volumeID, target string, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for {
-		vol, err := client.GetVolume(ctx, volumeID)
-		if err != nil {
-			return err
-		}
-		fmt.Printf("  status: %s\n", vol.Status)
-		if vol.Status == target {
-			return nil
-		}
-		if vol.Status == "error" {
-			return fmt.Errorf("volume entered error state")
-		}
-		if time.Now().After(deadline) {
-			return fmt.Errorf("timed out waiting for status %q (last: %q)", target, vol.Status)
-		}
-		time.Sleep(5 * time.Second)
+// waitOptions polls every 5 seconds and prints each status seen.
+func waitOptions(timeout time.Duration) *conoha.WaitOptions {
+	return &conoha.WaitOptions{
+		Interval: 5 * time.Second,
+		Timeout:  timeout,
+		Progress: func(status string) { fmt.Printf("  status: %s\n", status) },
 	}
 }
 
@@ -128,7 +92,7 @@ func main() {
 	fmt.Printf("Volume created: %s\n", volumeID)
 
 	fmt.Println("Waiting for volume to become available...")
-	if err := waitForVolumeStatus(ctx, client, volumeID, "available", 3*time.Minute); err != nil {
+	if _, err := client.WaitForVolumeStatus(ctx, volumeID, "available", waitOptions(3*time.Minute)); err != nil {
 		log.Fatalf("Volume wait failed: %v", err)
 	}
 	fmt.Println("Volume is available.")
@@ -152,7 +116,7 @@ func main() {
 	fmt.Printf("Server created: %s\n", serverID)
 
 	fmt.Println("Waiting for server to become ACTIVE...")
-	if err := waitForServerStatus(ctx, client, serverID, "ACTIVE", 5*time.Minute); err != nil {
+	if _, err := client.WaitForServerStatus(ctx, serverID, "ACTIVE", waitOptions(5*time.Minute)); err != nil {
 		log.Fatalf("Server wait failed: %v", err)
 	}
 	fmt.Println("Server is ACTIVE.")
@@ -163,7 +127,7 @@ func main() {
 		log.Fatalf("Stop server failed: %v", err)
 	}
 	fmt.Println("Stop requested. Waiting for SHUTOFF...")
-	if err := waitForServerStatus(ctx, client, serverID, "SHUTOFF", 3*time.Minute); err != nil {
+	if _, err := client.WaitForServerStatus(ctx, serverID, "SHUTOFF", waitOptions(3*time.Minute)); err != nil {
 		log.Fatalf("Server stop wait failed: %v", err)
 	}
 	fmt.Println("Server is SHUTOFF.")
@@ -174,7 +138,7 @@ func main() {
 		log.Fatalf("Start server failed: %v", err)
 	}
 	fmt.Println("Start requested. Waiting for ACTIVE...")
-	if err := waitForServerStatus(ctx, client, serverID, "ACTIVE", 3*time.Minute); err != nil {
+	if _, err := client.WaitForServerStatus(ctx, serverID, "ACTIVE", waitOptions(3*time.Minute)); err != nil {
 		log.Fatalf("Server start wait failed: %v", err)
 	}
 	fmt.Println("Server is ACTIVE again.")
@@ -220,20 +184,10 @@ func main() {
 		fmt.Printf("Warning: delete server failed: %v\n", err)
 	} else {
 		fmt.Println("Waiting for server to be deleted...")
-		deadline := time.Now().Add(2 * time.Minute)
-		for time.Now().Before(deadline) {
-			s, err := client.GetServer(ctx, serverID)
-			if err != nil {
-				var apiErr *conoha.APIError
-				if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
-					fmt.Println("Server deleted.")
-					break
-				}
-				fmt.Printf("  error checking: %v\n", err)
-				break
-			}
-			fmt.Printf("  status: %s\n", s.Status)
-			time.Sleep(5 * time.Second)
+		if err := client.WaitForServerDeleted(ctx, serverID, waitOptions(2*time.Minute)); err != nil {
+			fmt.Printf("  error checking: %v\n", err)
+		} else {
+			fmt.Println("Server deleted.")
 		}
 	}
 
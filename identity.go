@@ -2,8 +2,16 @@ package conoha
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
 )
 
 // ------------------------------------------------------------
@@ -105,6 +113,9 @@ type tokenResponse struct {
 }
 
 // Authenticate authenticates using user ID and tenant ID, setting the token on the client.
+//
+// The credentials are kept in memory so that the client can re-issue the
+// token shortly before it expires, or when the API rejects it with 401.
 func (c *Client) Authenticate(ctx context.Context, userID, password, tenantID string) (*Token, error) {
 	req := &AuthRequest{
 		Auth: AuthBody{
@@ -146,9 +157,121 @@ func (c *Client) AuthenticateByName(ctx context.Context, userName, password, ten
 	return c.authenticate(ctx, req, "")
 }
 
+// AuthenticateWithCredential issues a token from an API credential (an
+// access/secret key pair created with CreateCredential) instead of a
+// password. The token is scoped to the credential's tenant.
+//
+// The secret never leaves the client: the request is signed with it
+// (EC2 signature version 2, HMAC-SHA256) and sent to the Identity
+// ec2tokens endpoint. Like Authenticate, the credential is kept in memory
+// so that the token can be re-issued when it expires or is rejected.
+func (c *Client) AuthenticateWithCredential(ctx context.Context, access, secret string) (*Token, error) {
+	key := tokenCacheKey(c.identityURL(), access, "")
+	return c.issueToken(ctx, key, "", func(ctx context.Context) (*http.Request, error) {
+		url := c.identityURL() + "/ec2tokens"
+		body, err := signEC2Credentials(url, access, secret, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return c.newRequest(ctx, http.MethodPost, url, body)
+	})
+}
+
+// ec2CredentialsRequest is the request body of POST /ec2tokens.
+type ec2CredentialsRequest struct {
+	Credentials ec2Credentials `json:"credentials"`
+}
+
+type ec2Credentials struct {
+	Access    string            `json:"access"`
+	Host      string            `json:"host"`
+	Verb      string            `json:"verb"`
+	Path      string            `json:"path"`
+	Params    map[string]string `json:"params"`
+	Signature string            `json:"signature"`
+}
+
+// signEC2Credentials builds the ec2tokens request for a POST to rawURL,
+// signed with secret using signature version 2.
+func signEC2Credentials(rawURL, access, secret string, now time.Time) (*ec2CredentialsRequest, error) {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse identity URL: %w", err)
+	}
+	creds := ec2Credentials{
+		Access: access,
+		Host:   u.Host,
+		Verb:   http.MethodPost,
+		Path:   u.EscapedPath(),
+		Params: map[string]string{
+			"AWSAccessKeyId":   access,
+			"SignatureMethod":  "HmacSHA256",
+			"SignatureVersion": "2",
+			"Timestamp":        now.UTC().Format("2006-01-02T15:04:05Z"),
+		},
+	}
+	creds.Signature = ec2SignatureV2(secret, creds.Verb, creds.Host, creds.Path, creds.Params)
+	return &ec2CredentialsRequest{Credentials: creds}, nil
+}
+
+// ec2SignatureV2 computes an EC2 signature version 2: the base64 encoded
+// HMAC-SHA256 of the verb, host, path and canonical query string.
+func ec2SignatureV2(secret, verb, host, path string, params map[string]string) string {
+	pairs := make([]string, 0, len(params))
+	for _, k := range sortedKeys(params) {
+		pairs = append(pairs, ec2Escape(k)+"="+ec2Escape(params[k]))
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(verb + "\n" + host + "\n" + path + "\n" + strings.Join(pairs, "&")))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ec2Escape percent-encodes s as RFC 3986 requires for signing: only
+// letters, digits and "-_.~" are left as is.
+func ec2Escape(s string) string {
+	return strings.ReplaceAll(neturl.QueryEscape(s), "+", "%20")
+}
+
 func (c *Client) authenticate(ctx context.Context, authReq *AuthRequest, tenantID string) (*Token, error) {
-	url := c.IdentityURL + "/auth/tokens"
-	httpReq, err := c.newRequest(ctx, http.MethodPost, url, authReq)
+	user := authReq.Auth.Identity.Password.User.ID
+	if user == "" {
+		user = authReq.Auth.Identity.Password.User.Name
+	}
+	var tenant string
+	if scope := authReq.Auth.Scope; scope != nil {
+		tenant = scope.Project.ID
+		if tenant == "" {
+			tenant = scope.Project.Name
+		}
+	}
+	key := tokenCacheKey(c.identityURL(), user, tenant)
+	return c.issueToken(ctx, key, tenantID, func(ctx context.Context) (*http.Request, error) {
+		return c.newRequest(ctx, http.MethodPost, c.identityURL()+"/auth/tokens", authReq)
+	})
+}
+
+// issueToken makes the client use a token for the credentials behind
+// build: a token cached under key in the token store if one is still
+// fresh, or else a newly issued one.
+func (c *Client) issueToken(ctx context.Context, key, tenantID string, build func(ctx context.Context) (*http.Request, error)) (*Token, error) {
+	if cached := c.loadCachedToken(ctx, key); cached != nil {
+		c.mu.Lock()
+		c.Token = cached.Token
+		c.applyToken(&cached.Details, tenantID)
+		c.setReauth(key, tenantID, build)
+		c.mu.Unlock()
+		return &cached.Details, nil
+	}
+	return c.requestToken(ctx, key, tenantID, build)
+}
+
+// requestToken sends the token request built by build and stores the
+// issued token on the client and in the token store. build is kept so that
+// the token can be re-issued the same way when it expires or is rejected;
+// it is called again for every re-issue, so it may sign the request with a
+// fresh timestamp.
+func (c *Client) requestToken(ctx context.Context, key, tenantID string, build func(ctx context.Context) (*http.Request, error)) (*Token, error) {
+	httpReq, err := build(context.WithValue(ctx, authRequestKey{}, true))
 	if err != nil {
 		return nil, err
 	}
@@ -163,22 +286,216 @@ func (c *Client) authenticate(ctx context.Context, authReq *AuthRequest, tenantI
 
 	c.mu.Lock()
 	c.Token = resp.Header.Get("X-Subject-Token")
+	c.applyToken(&result.Token, tenantID)
+	c.setReauth(key, tenantID, build)
+	cached := &CachedToken{Token: c.Token, Details: result.Token}
+	c.mu.Unlock()
+
+	c.saveCachedToken(ctx, key, cached)
+	return &result.Token, nil
+}
+
+// setReauth remembers how the current token was issued so it can be
+// re-issued when it expires or is rejected. c.mu must be held.
+func (c *Client) setReauth(key, tenantID string, build func(ctx context.Context) (*http.Request, error)) {
+	c.tokenKey = key
+	c.reauth = func(ctx context.Context) error {
+		// The cached token is expiring or was rejected; don't reuse it.
+		c.deleteCachedToken(ctx, key)
+		_, err := c.requestToken(ctx, key, tenantID, build)
+		return err
+	}
+}
+
+// applyToken records the expiry, tenant and endpoints of token. tenantID,
+// if set, takes precedence over the token's project. c.mu must be held.
+func (c *Client) applyToken(token *Token, tenantID string) {
+	c.tokenExpiresAt = parseTokenExpiry(token.ExpiresAt)
 	if tenantID != "" {
 		c.TenantID = tenantID
-	} else if result.Token.Project.ID != "" {
-		c.TenantID = result.Token.Project.ID
+	} else if token.Project.ID != "" {
+		c.TenantID = token.Project.ID
 	}
 
 	// Auto-discover endpoint URLs from Service Catalog.
 	// Only overrides URLs that were NOT explicitly set by the user.
-	if len(result.Token.Catalog) > 0 {
-		c.updateEndpointsFromCatalog(result.Token.Catalog)
+	if len(token.Catalog) > 0 {
+		c.catalog = token.Catalog
+		c.updateEndpointsFromCatalog(token.Catalog)
 	}
+}
+
+// ValidateToken checks the client's current token against the Identity
+// API and returns its details, including the service catalog. The client's
+// TenantID and endpoints are filled in from the token, which makes it the
+// natural next step after WithToken.
+func (c *Client) ValidateToken(ctx context.Context) (*Token, error) {
+	url := c.identityURL() + "/auth/tokens"
+	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Subject-Token", req.Header.Get("X-Auth-Token"))
+
+	var result tokenResponse
+	if _, err := c.do(req, &result); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.applyToken(&result.Token, "")
 	c.mu.Unlock()
 
 	return &result.Token, nil
 }
 
+// RevokeToken revokes the client's current token, e.g. on logout. The
+// client forgets the token and the credentials it was issued with, so it
+// will not re-issue it; authenticate again to keep using the client.
+func (c *Client) RevokeToken(ctx context.Context) error {
+	url := c.identityURL() + "/auth/tokens"
+	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+	token := req.Header.Get("X-Auth-Token")
+	req.Header.Set("X-Subject-Token", token)
+
+	if _, err := c.do(req, nil); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	var key string
+	if c.Token == token {
+		key = c.tokenKey
+		c.Token = ""
+		c.tokenExpiresAt = time.Time{}
+		c.reauth = nil
+		c.tokenKey = ""
+	}
+	c.mu.Unlock()
+	if key != "" {
+		c.deleteCachedToken(ctx, key)
+	}
+	return nil
+}
+
+// tokenRefreshWindow is how long before expiry a token is re-issued.
+const tokenRefreshWindow = 5 * time.Minute
+
+// tokenRefreshTimeout bounds a shared token refresh, which does not stop
+// when the caller that started it gives up.
+const tokenRefreshTimeout = time.Minute
+
+// errNoReauth is returned when a token refresh is needed but the client
+// was never authenticated with reusable credentials.
+var errNoReauth = errors.New("conoha: no credentials available to re-issue the token")
+
+// tokenRefresh is a token refresh in progress. done is closed when it
+// finishes, after which err holds its result.
+type tokenRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// parseTokenExpiry parses a Token.ExpiresAt value such as
+// "2025-01-01T00:00:00.000000Z". It returns the zero time if the value is
+// empty or malformed.
+func parseTokenExpiry(expiresAt string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, expiresAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// canReauth reports whether the client can re-issue its token. A region
+// client can when its tokenBase can, which may change after it was made.
+func (c *Client) canReauth() bool {
+	c.mu.RLock()
+	reauth, base := c.reauth != nil, c.tokenBase
+	c.mu.RUnlock()
+	if base != nil {
+		return reauth && base.canReauth()
+	}
+	return reauth
+}
+
+// tokenNeedsRefresh reports whether the current token expires within
+// tokenRefreshWindow and can be re-issued.
+func (c *Client) tokenNeedsRefresh() bool {
+	c.mu.RLock()
+	expiresAt := c.tokenExpiresAt
+	c.mu.RUnlock()
+	if expiresAt.IsZero() || !c.canReauth() {
+		return false
+	}
+	return time.Until(expiresAt) < tokenRefreshWindow
+}
+
+// refreshToken replaces staleToken with a newly issued token.
+//
+// Concurrent callers share a single refresh: the first one starts it and
+// every caller waits for its result or for its own ctx to be done. The
+// refresh itself is not canceled with the caller that started it; it is
+// bounded by tokenRefreshTimeout instead. If the token has already changed
+// since staleToken was read, refreshToken returns immediately. An empty
+// staleToken issues the first token of a lazily authenticated client.
+func (c *Client) refreshToken(ctx context.Context, staleToken string) error {
+	c.mu.Lock()
+	if c.Token != staleToken {
+		c.mu.Unlock()
+		return nil
+	}
+	r := c.refreshing
+	if r == nil {
+		reauth := c.reauth
+		if reauth == nil {
+			c.mu.Unlock()
+			return errNoReauth
+		}
+		r = &tokenRefresh{done: make(chan struct{})}
+		c.refreshing = r
+		refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tokenRefreshTimeout)
+		go func() {
+			defer cancel()
+			c.runRefresh(refreshCtx, r, reauth, staleToken == "")
+		}()
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runRefresh performs the refresh r and closes r.done.
+func (c *Client) runRefresh(ctx context.Context, r *tokenRefresh, reauth func(context.Context) error, first bool) {
+	if first {
+		// The first token of a lazily authenticated client.
+		r.err = reauth(withOperationName(ctx, "Authenticate"))
+	} else {
+		r.err = reauth(withOperationName(ctx, "RefreshToken"))
+		if c.metrics != nil {
+			c.metrics.IncTokenRefresh(r.err)
+		}
+		if r.err != nil {
+			c.logEvent(ctx, slog.LevelWarn, "conoha token refresh failed", slog.String("error", r.err.Error()))
+		} else {
+			c.logEvent(ctx, slog.LevelInfo, "conoha token refreshed")
+		}
+	}
+
+	c.mu.Lock()
+	c.refreshing = nil
+	c.mu.Unlock()
+	close(r.done)
+}
+
 // ------------------------------------------------------------
 // Credentials
 // ------------------------------------------------------------
@@ -203,7 +520,7 @@ type credentialResponse struct {
 
 // ListCredentials lists all credentials for a user.
 func (c *Client) ListCredentials(ctx context.Context, userID string) ([]Credential, error) {
-	url := fmt.Sprintf("%s/users/%s/credentials/OS-EC2", c.IdentityURL, userID)
+	url := fmt.Sprintf("%s/users/%s/credentials/OS-EC2", c.identityURL(), userID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -217,7 +534,7 @@ func (c *Client) ListCredentials(ctx context.Context, userID string) ([]Credenti
 
 // CreateCredential creates a new credential for a user.
 func (c *Client) CreateCredential(ctx context.Context, userID, tenantID string) (*Credential, error) {
-	url := fmt.Sprintf("%s/users/%s/credentials/OS-EC2", c.IdentityURL, userID)
+	url := fmt.Sprintf("%s/users/%s/credentials/OS-EC2", c.identityURL(), userID)
 	body := map[string]string{"tenant_id": tenantID}
 	req, err := c.newRequest(ctx, http.MethodPost, url, body)
 	if err != nil {
@@ -232,7 +549,7 @@ func (c *Client) CreateCredential(ctx context.Context, userID, tenantID string)
 
 // GetCredential gets a credential detail.
 func (c *Client) GetCredential(ctx context.Context, userID, credentialID string) (*Credential, error) {
-	url := fmt.Sprintf("%s/users/%s/credentials/OS-EC2/%s", c.IdentityURL, userID, credentialID)
+	url := fmt.Sprintf("%s/users/%s/credentials/OS-EC2/%s", c.identityURL(), userID, credentialID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -246,7 +563,7 @@ func (c *Client) GetCredential(ctx context.Context, userID, credentialID string)
 
 // DeleteCredential deletes a credential.
 func (c *Client) DeleteCredential(ctx context.Context, userID, credentialID string) error {
-	url := fmt.Sprintf("%s/users/%s/credentials/OS-EC2/%s", c.IdentityURL, userID, credentialID)
+	url := fmt.Sprintf("%s/users/%s/credentials/OS-EC2/%s", c.identityURL(), userID, credentialID)
 	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
@@ -288,7 +605,7 @@ type CreateSubUserRequest struct {
 
 // ListSubUsers lists all sub-users.
 func (c *Client) ListSubUsers(ctx context.Context) ([]SubUser, error) {
-	url := c.IdentityURL + "/sub-users"
+	url := c.identityURL() + "/sub-users"
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -302,7 +619,7 @@ func (c *Client) ListSubUsers(ctx context.Context) ([]SubUser, error) {
 
 // CreateSubUser creates a new sub-user.
 func (c *Client) CreateSubUser(ctx context.Context, password string, roles []string) (*SubUser, error) {
-	url := c.IdentityURL + "/sub-users"
+	url := c.identityURL() + "/sub-users"
 	body := map[string]interface{}{
 		"user": CreateSubUserRequest{
 			Password: password,
@@ -322,7 +639,7 @@ func (c *Client) CreateSubUser(ctx context.Context, password string, roles []str
 
 // GetSubUser gets a sub-user detail.
 func (c *Client) GetSubUser(ctx context.Context, subUserID string) (*SubUser, error) {
-	url := fmt.Sprintf("%s/sub-users/%s", c.IdentityURL, subUserID)
+	url := fmt.Sprintf("%s/sub-users/%s", c.identityURL(), subUserID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -336,7 +653,7 @@ func (c *Client) GetSubUser(ctx context.Context, subUserID string) (*SubUser, er
 
 // UpdateSubUser updates a sub-user's password.
 func (c *Client) UpdateSubUser(ctx context.Context, subUserID, password string) (*SubUser, error) {
-	url := fmt.Sprintf("%s/sub-users/%s", c.IdentityURL, subUserID)
+	url := fmt.Sprintf("%s/sub-users/%s", c.identityURL(), subUserID)
 	body := map[string]interface{}{
 		"user": map[string]string{"password": password},
 	}
@@ -353,7 +670,7 @@ func (c *Client) UpdateSubUser(ctx context.Context, subUserID, password string)
 
 // DeleteSubUser deletes a sub-user.
 func (c *Client) DeleteSubUser(ctx context.Context, subUserID string) error {
-	url := fmt.Sprintf("%s/sub-users/%s", c.IdentityURL, subUserID)
+	url := fmt.Sprintf("%s/sub-users/%s", c.identityURL(), subUserID)
 	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
@@ -364,7 +681,7 @@ func (c *Client) DeleteSubUser(ctx context.Context, subUserID string) error {
 
 // AssignRolesToSubUser assigns roles to a sub-user.
 func (c *Client) AssignRolesToSubUser(ctx context.Context, subUserID string, roleIDs []string) (*SubUser, error) {
-	url := fmt.Sprintf("%s/sub-users/%s/assign", c.IdentityURL, subUserID)
+	url := fmt.Sprintf("%s/sub-users/%s/assign", c.identityURL(), subUserID)
 	body := map[string]interface{}{"roles": roleIDs}
 	req, err := c.newRequest(ctx, http.MethodPost, url, body)
 	if err != nil {
@@ -379,7 +696,7 @@ func (c *Client) AssignRolesToSubUser(ctx context.Context, subUserID string, rol
 
 // UnassignRolesFromSubUser removes roles from a sub-user.
 func (c *Client) UnassignRolesFromSubUser(ctx context.Context, subUserID string, roleIDs []string) (*SubUser, error) {
-	url := fmt.Sprintf("%s/sub-users/%s/unassign", c.IdentityURL, subUserID)
+	url := fmt.Sprintf("%s/sub-users/%s/unassign", c.identityURL(), subUserID)
 	body := map[string]interface{}{"roles": roleIDs}
 	req, err := c.newRequest(ctx, http.MethodPost, url, body)
 	if err != nil {
@@ -414,7 +731,7 @@ type roleDetailResponse struct {
 
 // ListRoles lists all roles.
 func (c *Client) ListRoles(ctx context.Context) ([]RoleDetail, error) {
-	url := c.IdentityURL + "/sub-users/roles"
+	url := c.identityURL() + "/sub-users/roles"
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -428,7 +745,7 @@ func (c *Client) ListRoles(ctx context.Context) ([]RoleDetail, error) {
 
 // CreateRole creates a new role with permissions.
 func (c *Client) CreateRole(ctx context.Context, name string, permissions []string) (*RoleDetail, error) {
-	url := c.IdentityURL + "/sub-users/roles"
+	url := c.identityURL() + "/sub-users/roles"
 	body := map[string]interface{}{
 		"role": map[string]interface{}{
 			"name":        name,
@@ -448,7 +765,7 @@ func (c *Client) CreateRole(ctx context.Context, name string, permissions []stri
 
 // GetRole gets a role detail.
 func (c *Client) GetRole(ctx context.Context, roleID string) (*RoleDetail, error) {
-	url := fmt.Sprintf("%s/sub-users/roles/%s", c.IdentityURL, roleID)
+	url := fmt.Sprintf("%s/sub-users/roles/%s", c.identityURL(), roleID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -462,7 +779,7 @@ func (c *Client) GetRole(ctx context.Context, roleID string) (*RoleDetail, error
 
 // UpdateRole updates a role's name.
 func (c *Client) UpdateRole(ctx context.Context, roleID, name string) (*RoleDetail, error) {
-	url := fmt.Sprintf("%s/sub-users/roles/%s", c.IdentityURL, roleID)
+	url := fmt.Sprintf("%s/sub-users/roles/%s", c.identityURL(), roleID)
 	body := map[string]interface{}{
 		"role": map[string]string{"name": name},
 	}
@@ -479,7 +796,7 @@ func (c *Client) UpdateRole(ctx context.Context, roleID, name string) (*RoleDeta
 
 // DeleteRole deletes a role.
 func (c *Client) DeleteRole(ctx context.Context, roleID string) error {
-	url := fmt.Sprintf("%s/sub-users/roles/%s", c.IdentityURL, roleID)
+	url := fmt.Sprintf("%s/sub-users/roles/%s", c.identityURL(), roleID)
 	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
@@ -504,7 +821,7 @@ type permissionsResponse struct {
 
 // ListPermissions lists all available permissions.
 func (c *Client) ListPermissions(ctx context.Context) ([]Permission, error) {
-	url := c.IdentityURL + "/permissions"
+	url := c.identityURL() + "/permissions"
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -518,7 +835,7 @@ func (c *Client) ListPermissions(ctx context.Context) ([]Permission, error) {
 
 // AssignPermissionsToRole assigns permissions to a role.
 func (c *Client) AssignPermissionsToRole(ctx context.Context, roleID string, permissions []string) (*RoleDetail, error) {
-	url := fmt.Sprintf("%s/sub-users/roles/%s/assign", c.IdentityURL, roleID)
+	url := fmt.Sprintf("%s/sub-users/roles/%s/assign", c.identityURL(), roleID)
 	body := map[string]interface{}{"permissions": permissions}
 	req, err := c.newRequest(ctx, http.MethodPost, url, body)
 	if err != nil {
@@ -533,7 +850,7 @@ func (c *Client) AssignPermissionsToRole(ctx context.Context, roleID string, per
 
 // UnassignPermissionsFromRole removes permissions from a role.
 func (c *Client) UnassignPermissionsFromRole(ctx context.Context, roleID string, permissions []string) (*RoleDetail, error) {
-	url := fmt.Sprintf("%s/sub-users/roles/%s/unassign", c.IdentityURL, roleID)
+	url := fmt.Sprintf("%s/sub-users/roles/%s/unassign", c.identityURL(), roleID)
 	body := map[string]interface{}{"permissions": permissions}
 	req, err := c.newRequest(ctx, http.MethodPost, url, body)
 	if err != nil {
@@ -3,10 +3,15 @@ package conoha
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // ============================================================
@@ -171,6 +176,349 @@ func TestAuthenticate_CatalogAutoDiscovery(t *testing.T) {
 	}
 }
 
+// ============================================================
+// Token refresh
+// ============================================================
+
+// tokenRefreshServer serves /auth/tokens and /ec2tokens by issuing "token-1", "token-2", …
+// and answers every other path with 401 unless the request carries the most
+// recently issued token.
+type tokenRefreshServer struct {
+	mu        sync.Mutex
+	issued    int
+	expiresAt string
+	apiCalls  int
+}
+
+func (s *tokenRefreshServer) handler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if strings.HasSuffix(r.URL.Path, "/auth/tokens") || strings.HasSuffix(r.URL.Path, "/ec2tokens") {
+		s.issued++
+		w.Header().Set("X-Subject-Token", fmt.Sprintf("token-%d", s.issued))
+		w.WriteHeader(201)
+		fmt.Fprintf(w, `{"token":{"catalog":[],"project":{"id":"tenant"},"expires_at":%q}}`, s.expiresAt)
+		return
+	}
+	s.apiCalls++
+	if r.Header.Get("X-Auth-Token") != fmt.Sprintf("token-%d", s.issued) {
+		w.WriteHeader(401)
+		w.Write([]byte(`{"unauthorized":{"message":"The request you have made requires authentication.","code":401}}`))
+		return
+	}
+	w.WriteHeader(200)
+	w.Write([]byte(`{"servers":[]}`))
+}
+
+func (s *tokenRefreshServer) counts() (issued, apiCalls int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issued, s.apiCalls
+}
+
+func TestAuthenticate_ReauthenticatesOn401(t *testing.T) {
+	ts := &tokenRefreshServer{}
+	server, client := setupTestServer(ts.handler)
+	defer server.Close()
+
+	_, err := client.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+
+	// Simulate the API revoking the token behind the client's back.
+	ts.mu.Lock()
+	ts.issued++
+	ts.mu.Unlock()
+
+	_, err = client.ListServers(context.Background(), nil)
+	assertNoError(t, err)
+
+	issued, apiCalls := ts.counts()
+	if issued != 3 {
+		t.Errorf("tokens issued = %d, want 3", issued)
+	}
+	if apiCalls != 2 {
+		t.Errorf("API calls = %d, want 2 (401 + retry)", apiCalls)
+	}
+	if client.Token != "token-3" {
+		t.Errorf("Token = %q, want %q", client.Token, "token-3")
+	}
+}
+
+func TestAuthenticate_RefreshesBeforeExpiry(t *testing.T) {
+	ts := &tokenRefreshServer{
+		expiresAt: time.Now().Add(time.Minute).UTC().Format("2006-01-02T15:04:05.000000Z"),
+	}
+	server, client := setupTestServer(ts.handler)
+	defer server.Close()
+
+	_, err := client.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+
+	_, err = client.ListServers(context.Background(), nil)
+	assertNoError(t, err)
+
+	issued, apiCalls := ts.counts()
+	if issued != 2 {
+		t.Errorf("tokens issued = %d, want 2", issued)
+	}
+	if apiCalls != 1 {
+		t.Errorf("API calls = %d, want 1 (no 401 round trip)", apiCalls)
+	}
+}
+
+func TestAuthenticate_ConcurrentRefreshIsShared(t *testing.T) {
+	ts := &tokenRefreshServer{}
+	server, client := setupTestServer(ts.handler)
+	defer server.Close()
+
+	_, err := client.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+
+	ts.mu.Lock()
+	ts.issued++
+	ts.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.ListServers(context.Background(), nil)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assertNoError(t, err)
+	}
+
+	if issued, _ := ts.counts(); issued != 3 {
+		t.Errorf("tokens issued = %d, want 3 (one shared refresh)", issued)
+	}
+}
+
+func TestAuthenticate_RefreshSurvivesCanceledCaller(t *testing.T) {
+	ts := &tokenRefreshServer{}
+	authStarted := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var blockAuth atomic.Bool
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/auth/tokens") && blockAuth.Load() {
+			authStarted <- struct{}{}
+			<-release
+		}
+		ts.handler(w, r)
+	})
+	defer server.Close()
+
+	_, err := client.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+	ts.mu.Lock()
+	ts.issued++
+	ts.mu.Unlock()
+	blockAuth.Store(true)
+
+	// The first caller starts the refresh, then gives up.
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := client.ListServers(ctx, nil)
+		firstErr <- err
+	}()
+	<-authStarted
+	secondErr := make(chan error, 1)
+	go func() {
+		_, err := client.ListServers(context.Background(), nil)
+		secondErr <- err
+	}()
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled caller: err = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	assertNoError(t, <-secondErr)
+	if issued, _ := ts.counts(); issued != 3 {
+		t.Errorf("tokens issued = %d, want 3 (one shared refresh)", issued)
+	}
+}
+
+func TestAuthenticateWithCredential_Success(t *testing.T) {
+	var body ec2CredentialsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v3/ec2tokens" {
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+		readJSONBody(t, r, &body)
+		w.Header().Set("X-Subject-Token", "ec2-token")
+		w.WriteHeader(200)
+		w.Write([]byte(`{
+			"token": {
+				"catalog": [
+					{"type": "compute", "endpoints": [{"interface": "public", "region": "c3j1", "url": "https://compute.example.com/v2.1"}]}
+				],
+				"project": {"id": "tenant-from-credential"}
+			}
+		}`))
+	}))
+	defer server.Close()
+	client := NewClient(WithIdentityURL(server.URL))
+
+	_, err := client.AuthenticateWithCredential(context.Background(), "access-123", "secret-456")
+	assertNoError(t, err)
+
+	creds := body.Credentials
+	if creds.Access != "access-123" || creds.Params["AWSAccessKeyId"] != "access-123" {
+		t.Errorf("credentials = %+v", creds)
+	}
+	if creds.Signature != ec2SignatureV2("secret-456", creds.Verb, creds.Host, creds.Path, creds.Params) {
+		t.Errorf("signature %q does not verify", creds.Signature)
+	}
+	if client.Token != "ec2-token" || client.TenantID != "tenant-from-credential" {
+		t.Errorf("Token = %q, TenantID = %q", client.Token, client.TenantID)
+	}
+	if client.ComputeURL != "https://compute.example.com/v2.1" {
+		t.Errorf("ComputeURL = %q, want endpoint from catalog", client.ComputeURL)
+	}
+}
+
+func TestAuthenticateWithCredential_ReauthenticatesOn401(t *testing.T) {
+	ts := &tokenRefreshServer{}
+	server, client := setupTestServer(ts.handler)
+	defer server.Close()
+
+	_, err := client.AuthenticateWithCredential(context.Background(), "access", "secret")
+	assertNoError(t, err)
+	ts.mu.Lock()
+	ts.issued++
+	ts.mu.Unlock()
+
+	_, err = client.ListServers(context.Background(), nil)
+	assertNoError(t, err)
+	if issued, _ := ts.counts(); issued != 3 {
+		t.Errorf("tokens issued = %d, want 3", issued)
+	}
+}
+
+func TestSignEC2Credentials(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	req, err := signEC2Credentials("https://identity.c3j1.conoha.io/v3/ec2tokens", "access-123", "secret-456", now)
+	assertNoError(t, err)
+
+	creds := req.Credentials
+	if creds.Host != "identity.c3j1.conoha.io" || creds.Path != "/v3/ec2tokens" || creds.Verb != "POST" {
+		t.Errorf("host/path/verb = %q %q %q", creds.Host, creds.Path, creds.Verb)
+	}
+	if creds.Params["Timestamp"] != "2025-01-02T03:04:05Z" {
+		t.Errorf("Timestamp = %q", creds.Params["Timestamp"])
+	}
+	if want := "EU2yRXJZDbadOSRekZwFrJ4du58Hlk4h723gxpyQxbM="; creds.Signature != want {
+		t.Errorf("Signature = %q, want %q", creds.Signature, want)
+	}
+}
+
+func TestValidateToken_FillsTenantAndEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v3/auth/tokens" {
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("X-Auth-Token") != "minted-token" || r.Header.Get("X-Subject-Token") != "minted-token" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		w.Write([]byte(`{
+			"token": {
+				"catalog": [
+					{"type": "network", "endpoints": [{"interface": "public", "region": "c3j1", "url": "https://network.example.com/v2.0"}]}
+				],
+				"project": {"id": "tenant-from-token"},
+				"expires_at": "2030-01-01T00:00:00Z"
+			}
+		}`))
+	}))
+	defer server.Close()
+	client := NewClient(WithIdentityURL(server.URL), WithToken("minted-token"))
+
+	token, err := client.ValidateToken(context.Background())
+	assertNoError(t, err)
+
+	if token.Project.ID != "tenant-from-token" || len(token.Catalog) != 1 {
+		t.Errorf("token = %+v", token)
+	}
+	if client.TenantID != "tenant-from-token" {
+		t.Errorf("TenantID = %q", client.TenantID)
+	}
+	if client.NetworkingURL != "https://network.example.com/v2.0" {
+		t.Errorf("NetworkingURL = %q", client.NetworkingURL)
+	}
+	if client.Token != "minted-token" {
+		t.Errorf("Token = %q", client.Token)
+	}
+}
+
+func TestValidateToken_Invalid(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		w.Write([]byte(`{"error":{"message":"Could not find token.","code":404}}`))
+	})
+	defer server.Close()
+
+	_, err := client.ValidateToken(context.Background())
+	if !IsNotFound(err) {
+		t.Errorf("err = %v, want not found", err)
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	ts := &tokenRefreshServer{}
+	var revoked string
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			revoked = r.Header.Get("X-Subject-Token")
+			w.WriteHeader(204)
+			return
+		}
+		ts.handler(w, r)
+	})
+	defer server.Close()
+
+	_, err := client.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+	assertNoError(t, client.RevokeToken(context.Background()))
+
+	if revoked != "token-1" {
+		t.Errorf("revoked %q, want token-1", revoked)
+	}
+	if client.Token != "" || client.canReauth() {
+		t.Errorf("client still holds a token (%q) or credentials", client.Token)
+	}
+}
+
+func TestClient_401WithoutCredentialsIsReturned(t *testing.T) {
+	ts := &tokenRefreshServer{}
+	server, client := setupTestServer(ts.handler)
+	defer server.Close()
+
+	// Token set directly: the client has no credentials to re-issue it.
+	_, err := client.ListServers(context.Background(), nil)
+	assertAPIError(t, err, 401)
+
+	if issued, apiCalls := ts.counts(); issued != 0 || apiCalls != 1 {
+		t.Errorf("issued = %d, API calls = %d; want 0 and 1", issued, apiCalls)
+	}
+}
+
+func TestParseTokenExpiry(t *testing.T) {
+	got := parseTokenExpiry("2025-01-01T00:00:00.000000Z")
+	if want := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("parseTokenExpiry = %v, want %v", got, want)
+	}
+	if !parseTokenExpiry("").IsZero() {
+		t.Error("empty expiry should parse to the zero time")
+	}
+}
+
 // ============================================================
 // Credentials
 // ============================================================
@@ -15,6 +15,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/leonunix/conohav3-golang-sdk/cassette"
 )
 
 var (
@@ -22,14 +24,44 @@ var (
 	testUserID   string
 	testTenantID string
 	testCtx      context.Context
+
+	// testCassette is set when CONOHA_CASSETTE names a cassette file.
+	testCassette *cassette.Recorder
+	// pollInterval is the wait between status polls. Replayed polls
+	// don't wait.
+	pollInterval = 5 * time.Second
+	// suffixCounter makes randomSuffix deterministic with a cassette, so
+	// that replayed request bodies match the recorded ones.
+	suffixCounter int
 )
 
+// TestMain authenticates with the CONOHA_* environment variables.
+//
+// With CONOHA_CASSETTE set to a file, the suite replays that cassette
+// offline and needs no credentials; with CONOHA_CASSETTE_MODE=record as
+// well, it runs against the live API and records the cassette.
 func TestMain(m *testing.M) {
 	userID := os.Getenv("CONOHA_USER_ID")
 	password := os.Getenv("CONOHA_PASSWORD")
 	tenantID := os.Getenv("CONOHA_TENANT_ID")
 	region := os.Getenv("CONOHA_REGION")
 
+	cassettePath := os.Getenv("CONOHA_CASSETTE")
+	replay := cassettePath != "" && os.Getenv("CONOHA_CASSETTE_MODE") != "record"
+	if replay {
+		// The cassette has these placeholders in place of the recorded values.
+		if userID == "" {
+			userID = "USER_ID"
+		}
+		if password == "" {
+			password = cassette.Redacted
+		}
+		if tenantID == "" {
+			tenantID = cassette.TenantPlaceholder
+		}
+		pollInterval = 0
+	}
+
 	if userID == "" || password == "" || tenantID == "" {
 		fmt.Println("SKIP: CONOHA_USER_ID, CONOHA_PASSWORD, CONOHA_TENANT_ID not set")
 		os.Exit(0)
@@ -67,6 +99,22 @@ func TestMain(m *testing.M) {
 	if v := os.Getenv("CONOHA_DNS_URL"); v != "" {
 		opts = append(opts, WithDNSServiceURL(v))
 	}
+	if cassettePath != "" {
+		mode := cassette.Record
+		if replay {
+			mode = cassette.Replay
+		}
+		rec, err := cassette.New(cassettePath, mode,
+			cassette.WithReplacement(userID, "USER_ID"),
+			cassette.WithReplacement(tenantID, cassette.TenantPlaceholder),
+			cassette.WithIgnoredQuery("temp_url_sig", "temp_url_expires"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "FATAL: %v\n", err)
+			os.Exit(1)
+		}
+		testCassette = rec
+		opts = append(opts, WithHTTPClient(rec.Client()))
+	}
 	testClient = NewClient(opts...)
 
 	token, err := testClient.Authenticate(testCtx, userID, password, tenantID)
@@ -85,10 +133,21 @@ func TestMain(m *testing.M) {
 	}
 	fmt.Println("======================")
 
-	os.Exit(m.Run())
+	code := m.Run()
+	if testCassette != nil {
+		if err := testCassette.Stop(); err != nil {
+			fmt.Fprintf(os.Stderr, "FATAL: saving cassette: %v\n", err)
+			code = 1
+		}
+	}
+	os.Exit(code)
 }
 
 func randomSuffix() string {
+	if testCassette != nil {
+		suffixCounter++
+		return fmt.Sprintf("%08x", suffixCounter)
+	}
 	b := make([]byte, 4)
 	rand.Read(b)
 	return hex.EncodeToString(b)
@@ -106,8 +165,11 @@ func waitForServerStatus(t *testing.T, serverID, target string, timeout time.Dur
 			if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
 				return
 			}
+			if errors.Is(err, cassette.ErrNoInteraction) {
+				t.Fatal(err)
+			}
 			t.Logf("GetServer(%s): %v (retrying)", serverID, err)
-			time.Sleep(5 * time.Second)
+			time.Sleep(pollInterval)
 			continue
 		}
 		t.Logf("Server %s status: %s (waiting for %s)", serverID, s.Status, target)
@@ -117,7 +179,7 @@ func waitForServerStatus(t *testing.T, serverID, target string, timeout time.Dur
 		if s.Status == "ERROR" {
 			t.Fatalf("server %s entered ERROR state", serverID)
 		}
-		time.Sleep(5 * time.Second)
+		time.Sleep(pollInterval)
 	}
 	t.Fatalf("timeout waiting for server %s to reach status %s", serverID, target)
 }
@@ -129,8 +191,11 @@ func waitForVolumeStatus(t *testing.T, volumeID, target string, timeout time.Dur
 	for time.Now().Before(deadline) {
 		v, err := testClient.GetVolume(testCtx, volumeID)
 		if err != nil {
+			if errors.Is(err, cassette.ErrNoInteraction) {
+				t.Fatal(err)
+			}
 			t.Logf("GetVolume(%s): %v (retrying)", volumeID, err)
-			time.Sleep(5 * time.Second)
+			time.Sleep(pollInterval)
 			continue
 		}
 		t.Logf("Volume %s status: %s (waiting for %s)", volumeID, v.Status, target)
@@ -140,7 +205,7 @@ func waitForVolumeStatus(t *testing.T, volumeID, target string, timeout time.Dur
 		if v.Status == "error" {
 			t.Fatalf("volume %s entered error state", volumeID)
 		}
-		time.Sleep(5 * time.Second)
+		time.Sleep(pollInterval)
 	}
 	t.Fatalf("timeout waiting for volume %s to reach status %s", volumeID, target)
 }
@@ -156,8 +221,11 @@ func waitForLBStatus(t *testing.T, lbID, target string, timeout time.Duration) {
 			if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
 				return
 			}
+			if errors.Is(err, cassette.ErrNoInteraction) {
+				t.Fatal(err)
+			}
 			t.Logf("GetLoadBalancer(%s): %v (retrying)", lbID, err)
-			time.Sleep(5 * time.Second)
+			time.Sleep(pollInterval)
 			continue
 		}
 		t.Logf("LB %s provisioning_status: %s (waiting for %s)", lbID, lb.ProvisioningStatus, target)
@@ -167,7 +235,7 @@ func waitForLBStatus(t *testing.T, lbID, target string, timeout time.Duration) {
 		if lb.ProvisioningStatus == "ERROR" {
 			t.Fatalf("load balancer %s entered ERROR state", lbID)
 		}
-		time.Sleep(5 * time.Second)
+		time.Sleep(pollInterval)
 	}
 	t.Fatalf("timeout waiting for LB %s to reach status %s", lbID, target)
 }
@@ -1302,7 +1370,7 @@ func TestIntegration_Server_Lifecycle(t *testing.T) {
 	defer func() {
 		if vol != nil {
 			// Wait a bit before trying to delete.
-			time.Sleep(5 * time.Second)
+			time.Sleep(pollInterval)
 			if err := testClient.DeleteVolume(testCtx, vol.ID, true); err != nil {
 				t.Logf("WARNING: cleanup DeleteVolume(%s) failed: %v", vol.ID, err)
 			}
@@ -1360,7 +1428,7 @@ func TestIntegration_Server_Lifecycle(t *testing.T) {
 				if err != nil {
 					break
 				}
-				time.Sleep(5 * time.Second)
+				time.Sleep(pollInterval)
 			}
 		}
 	}()
@@ -1502,7 +1570,7 @@ func TestIntegration_Server_Lifecycle(t *testing.T) {
 			if err != nil {
 				break
 			}
-			time.Sleep(5 * time.Second)
+			time.Sleep(pollInterval)
 		}
 		serverID = ""
 		t.Log("Server deleted")
@@ -0,0 +1,233 @@
+package conoha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ServerSpec describes a server for LaunchServer. Image, Flavor,
+// SecurityGroups and the resources they name may be given by name or ID.
+type ServerSpec struct {
+	// Name is the server's instance_name_tag, the name shown in the control
+	// panel. The boot volume is named after it.
+	Name string
+	// Image is the OS image of the boot volume, e.g.
+	// "vmi-ubuntu-24.04-amd64". Required.
+	Image string
+	// Flavor is the plan of the server, e.g. "g2l-t-c2m1". Required.
+	Flavor string
+	// DiskSize is the size of the boot volume in GB. Default: 100.
+	DiskSize int
+	// VolumeType is the type of the boot volume, e.g. "c3j1-ds02-boot".
+	// Empty uses the service's default.
+	VolumeType string
+	// KeyName is the SSH keypair to install. When no keypair has that
+	// name and PublicKey is set, PublicKey is imported under KeyName.
+	KeyName   string
+	PublicKey string
+	// SecurityGroups are created, without rules, when they do not exist.
+	SecurityGroups []string
+	AdminPass      string
+	Metadata       map[string]string
+	// UserData is the base64-encoded user data passed to cloud-init, as
+	// returned by UserData.Encode.
+	UserData string
+	// Wait controls the waits for the boot volume and the server. Nil polls
+	// every 5s until ctx is done.
+	Wait *WaitOptions
+}
+
+// DefaultDiskSize is the boot volume size LaunchServer uses when
+// ServerSpec.DiskSize is 0.
+const DefaultDiskSize = 100
+
+// rollbackTimeout bounds the cleanup of a failed LaunchServer, which does
+// not stop when the caller's context is canceled.
+const rollbackTimeout = 10 * time.Minute
+
+// LaunchServer creates a server from spec and waits until it is ACTIVE:
+// it creates the boot volume from the image, waits for it, imports the
+// keypair, creates the missing security groups, creates the server and
+// waits for it. The returned server includes its addresses.
+//
+// If a step fails, the resources created so far are deleted and the
+// error of the step is returned, joined with any error of the cleanup.
+// The cleanup runs even when ctx is canceled, for up to 10 minutes.
+func (c *Client) LaunchServer(ctx context.Context, spec ServerSpec) (*ServerDetail, error) {
+	if spec.Image == "" || spec.Flavor == "" {
+		return nil, fmt.Errorf("conoha: ServerSpec.Image and ServerSpec.Flavor are required")
+	}
+	l := &launch{c: c}
+	server, err := l.run(ctx, spec)
+	if err != nil {
+		rbCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+		defer cancel()
+		if rbErr := l.rollback(rbCtx, spec.Wait); rbErr != nil {
+			err = errors.Join(err, fmt.Errorf("roll back: %w", rbErr))
+		}
+		return nil, err
+	}
+	return server, nil
+}
+
+// launch records what LaunchServer created, to undo it on failure.
+type launch struct {
+	c              *Client
+	serverID       string
+	volumeID       string
+	keypair        string
+	securityGroups []string
+}
+
+func (l *launch) run(ctx context.Context, spec ServerSpec) (*ServerDetail, error) {
+	c := l.c
+	// Resolve everything before creating anything.
+	image, err := c.ResolveImage(ctx, spec.Image)
+	if err != nil {
+		return nil, fmt.Errorf("resolve image: %w", err)
+	}
+	flavor, err := c.ResolveFlavor(ctx, spec.Flavor)
+	if err != nil {
+		return nil, fmt.Errorf("resolve flavor: %w", err)
+	}
+	importKey := false
+	if spec.KeyName != "" {
+		if importKey, err = l.checkKeypair(ctx, spec.KeyName, spec.PublicKey); err != nil {
+			return nil, err
+		}
+	}
+	// Groups that do not exist yet are left nil and created below.
+	groups := make([]*SecurityGroup, len(spec.SecurityGroups))
+	for i, nameOrID := range spec.SecurityGroups {
+		sg, err := c.ResolveSecurityGroup(ctx, nameOrID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("resolve security group: %w", err)
+		}
+		groups[i] = sg
+	}
+
+	size := spec.DiskSize
+	if size == 0 {
+		size = DefaultDiskSize
+	}
+	volumeName := ""
+	if spec.Name != "" {
+		volumeName = spec.Name + "-boot"
+	}
+	vol, err := c.CreateVolume(ctx, CreateVolumeRequest{
+		Size:       size,
+		Name:       volumeName,
+		VolumeType: spec.VolumeType,
+		ImageRef:   image.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create boot volume: %w", err)
+	}
+	l.volumeID = vol.ID
+	if _, err := c.WaitForVolumeStatus(ctx, vol.ID, "available", spec.Wait); err != nil {
+		return nil, fmt.Errorf("wait for boot volume: %w", err)
+	}
+
+	if importKey {
+		if _, err := c.ImportKeypair(ctx, spec.KeyName, spec.PublicKey); err != nil {
+			return nil, fmt.Errorf("import keypair: %w", err)
+		}
+		l.keypair = spec.KeyName
+	}
+	refs := make([]SecurityGroupRef, len(groups))
+	for i, sg := range groups {
+		if sg == nil {
+			if sg, err = c.CreateSecurityGroup(ctx, spec.SecurityGroups[i], ""); err != nil {
+				return nil, fmt.Errorf("create security group: %w", err)
+			}
+			l.securityGroups = append(l.securityGroups, sg.ID)
+		}
+		refs[i] = SecurityGroupRef{Name: sg.Name}
+	}
+
+	metadata := make(map[string]string, len(spec.Metadata)+1)
+	for k, v := range spec.Metadata {
+		metadata[k] = v
+	}
+	if spec.Name != "" {
+		metadata["instance_name_tag"] = spec.Name
+	}
+	created, err := c.CreateServer(ctx, CreateServerRequest{
+		FlavorRef:          flavor.ID,
+		AdminPass:          spec.AdminPass,
+		BlockDeviceMapping: []BlockDeviceMap{{UUID: vol.ID}},
+		Metadata:           metadata,
+		SecurityGroups:     refs,
+		KeyName:            spec.KeyName,
+		UserData:           spec.UserData,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create server: %w", err)
+	}
+	l.serverID = created.ID
+	server, err := c.WaitForServerStatus(ctx, created.ID, "ACTIVE", spec.Wait)
+	if err != nil {
+		return nil, fmt.Errorf("wait for server: %w", err)
+	}
+	return server, nil
+}
+
+// checkKeypair reports whether the keypair name has to be imported from
+// publicKey, failing if it does not exist and publicKey is empty.
+func (l *launch) checkKeypair(ctx context.Context, name, publicKey string) (bool, error) {
+	_, err := l.c.GetKeypair(ctx, name)
+	switch {
+	case err == nil:
+		return false, nil
+	case !errors.Is(err, ErrNotFound):
+		return false, fmt.Errorf("get keypair: %w", err)
+	case publicKey == "":
+		return false, fmt.Errorf("keypair %q does not exist and ServerSpec.PublicKey is empty: %w", name, err)
+	}
+	return true, nil
+}
+
+// rollback deletes what run created, in reverse order. The server has to
+// be gone before its boot volume can be deleted.
+func (l *launch) rollback(ctx context.Context, wait *WaitOptions) error {
+	c := l.c
+	var errs []error
+	volumeFree := true
+	if l.serverID != "" {
+		err := c.DeleteServer(ctx, l.serverID)
+		if err == nil || errors.Is(err, ErrNotFound) {
+			err = c.WaitForServerDeleted(ctx, l.serverID, wait)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("delete server %s: %w", l.serverID, err))
+			volumeFree = false
+		}
+	}
+	for i := len(l.securityGroups) - 1; i >= 0; i-- {
+		if err := c.DeleteSecurityGroup(ctx, l.securityGroups[i]); err != nil {
+			errs = append(errs, fmt.Errorf("delete security group %s: %w", l.securityGroups[i], err))
+		}
+	}
+	if l.keypair != "" {
+		if err := c.DeleteKeypair(ctx, l.keypair); err != nil {
+			errs = append(errs, fmt.Errorf("delete keypair %s: %w", l.keypair, err))
+		}
+	}
+	if l.volumeID != "" && volumeFree {
+		// Wait for the volume to finish creating or detaching; a volume
+		// in error can be deleted as it is.
+		var statusErr *StatusError
+		_, err := c.WaitForVolumeStatus(ctx, l.volumeID, "available", wait)
+		if err == nil || errors.As(err, &statusErr) {
+			err = c.DeleteVolume(ctx, l.volumeID, false)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("delete volume %s: %w", l.volumeID, err))
+		}
+	} else if l.volumeID != "" {
+		errs = append(errs, fmt.Errorf("volume %s was left attached to server %s", l.volumeID, l.serverID))
+	}
+	return errors.Join(errs...)
+}
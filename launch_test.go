@@ -0,0 +1,251 @@
+package conoha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// launchAPI serves the calls LaunchServer makes and records the
+// resources it creates and deletes.
+type launchAPI struct {
+	mu           sync.Mutex
+	serverStatus string // status the server reports once created
+	keypairs     map[string]bool
+	servers      map[string]bool
+	deleting     int // GETs that still report ERROR after the server is deleted
+	volumes      map[string]bool
+	bodies       map[string]map[string]interface{} // request body by "METHOD path"
+	deleted      []string
+}
+
+func newLaunchAPI() *launchAPI {
+	return &launchAPI{
+		serverStatus: "ACTIVE",
+		keypairs:     map[string]bool{},
+		servers:      map[string]bool{},
+		volumes:      map[string]bool{},
+		bodies:       map[string]map[string]interface{}{},
+	}
+}
+
+func (a *launchAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	key := r.Method + " " + r.URL.Path
+	if r.Method == http.MethodPost {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		a.bodies[key] = body
+	}
+	notFound := func() {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"itemNotFound":{"code":404,"message":"not found"}}`))
+	}
+	switch key {
+	case "GET /images":
+		if r.URL.Query().Get("marker") != "" {
+			w.Write([]byte(`{"images":[]}`))
+			return
+		}
+		w.Write([]byte(`{"images":[{"id":"img-1","name":"vmi-ubuntu-24.04-amd64"}]}`))
+	case "GET /flavors/detail":
+		w.Write([]byte(`{"flavors":[{"id":"flv-1","name":"g2l-t-c2m1"}]}`))
+	case "POST /test-tenant-id/volumes":
+		a.volumes["vol-1"] = true
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"volume":{"id":"vol-1","status":"creating"}}`))
+	case "GET /test-tenant-id/volumes/vol-1":
+		if !a.volumes["vol-1"] {
+			notFound()
+			return
+		}
+		status := "available"
+		if a.servers["srv-1"] {
+			status = "in-use"
+		}
+		fmt.Fprintf(w, `{"volume":{"id":"vol-1","status":%q}}`, status)
+	case "GET /os-keypairs/deploy":
+		if !a.keypairs["deploy"] {
+			notFound()
+			return
+		}
+		w.Write([]byte(`{"keypair":{"name":"deploy"}}`))
+	case "POST /os-keypairs":
+		a.keypairs["deploy"] = true
+		w.Write([]byte(`{"keypair":{"name":"deploy"}}`))
+	case "GET /security-groups":
+		if r.URL.Query().Get("marker") != "" {
+			w.Write([]byte(`{"security_groups":[]}`))
+			return
+		}
+		w.Write([]byte(`{"security_groups":[{"id":"sg-default","name":"default"}]}`))
+	case "POST /security-groups":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"security_group":{"id":"sg-web","name":"web"}}`))
+	case "POST /servers":
+		a.servers["srv-1"] = true
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"server":{"id":"srv-1"}}`))
+	case "GET /servers/srv-1":
+		if !a.servers["srv-1"] {
+			notFound()
+			return
+		}
+		status := a.serverStatus
+		if a.deleting > 0 {
+			// Nova reports a failed server as ERROR until it is gone.
+			if a.deleting--; a.deleting == 0 {
+				delete(a.servers, "srv-1")
+			}
+			status = "ERROR"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"server": map[string]interface{}{
+			"id":     "srv-1",
+			"status": status,
+			"addresses": map[string]interface{}{
+				"ext-net": []map[string]interface{}{{"version": 4, "addr": "192.0.2.10"}},
+			},
+		}})
+	case "DELETE /servers/srv-1":
+		a.deleting = 3
+		a.deleted = append(a.deleted, "server")
+		w.WriteHeader(http.StatusNoContent)
+	case "DELETE /security-groups/sg-web":
+		a.deleted = append(a.deleted, "security group")
+		w.WriteHeader(http.StatusNoContent)
+	case "DELETE /os-keypairs/deploy":
+		a.deleted = append(a.deleted, "keypair")
+		w.WriteHeader(http.StatusAccepted)
+	case "DELETE /test-tenant-id/volumes/vol-1":
+		if a.servers["srv-1"] {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"badRequest":{"code":400,"message":"volume is attached"}}`))
+			return
+		}
+		delete(a.volumes, "vol-1")
+		a.deleted = append(a.deleted, "volume")
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte(`{"error":"unexpected ` + key + `"}`))
+	}
+}
+
+func launchSpec() ServerSpec {
+	return ServerSpec{
+		Name:           "web",
+		Image:          "vmi-ubuntu-24.04-amd64",
+		Flavor:         "g2l-t-c2m1",
+		KeyName:        "deploy",
+		PublicKey:      "ssh-ed25519 AAAA test",
+		SecurityGroups: []string{"default", "web"},
+		Wait:           &WaitOptions{Interval: time.Millisecond},
+	}
+}
+
+func TestLaunchServer(t *testing.T) {
+	api := newLaunchAPI()
+	server, client := setupTestServer(api.ServeHTTP)
+	defer server.Close()
+
+	s, err := client.LaunchServer(context.Background(), launchSpec())
+	assertNoError(t, err)
+	if s.ID != "srv-1" || s.Status != "ACTIVE" || s.Addresses["ext-net"][0].Addr != "192.0.2.10" {
+		t.Errorf("server = %+v", s)
+	}
+
+	vol := api.bodies["POST /test-tenant-id/volumes"]["volume"].(map[string]interface{})
+	if vol["imageRef"] != "img-1" || vol["size"] != float64(DefaultDiskSize) || vol["name"] != "web-boot" {
+		t.Errorf("volume request = %v", vol)
+	}
+	req := api.bodies["POST /servers"]["server"].(map[string]interface{})
+	got, _ := json.Marshal(req)
+	for _, want := range []string{
+		`"flavorRef":"flv-1"`,
+		`"block_device_mapping_v2":[{"uuid":"vol-1"}]`,
+		`"security_groups":[{"name":"default"},{"name":"web"}]`,
+		`"key_name":"deploy"`,
+		`"metadata":{"instance_name_tag":"web"}`,
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("server request %s does not contain %s", got, want)
+		}
+	}
+	if api.bodies["POST /os-keypairs"] == nil {
+		t.Error("keypair was not imported")
+	}
+	if len(api.deleted) != 0 {
+		t.Errorf("deleted %v after a successful launch", api.deleted)
+	}
+}
+
+func TestLaunchServer_RollsBack(t *testing.T) {
+	api := newLaunchAPI()
+	api.serverStatus = "ERROR"
+	server, client := setupTestServer(api.ServeHTTP)
+	defer server.Close()
+
+	_, err := client.LaunchServer(context.Background(), launchSpec())
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Resource != "server" {
+		t.Fatalf("expected a server *StatusError, got %v", err)
+	}
+	if strings.Contains(err.Error(), "roll back") {
+		t.Errorf("rollback failed: %v", err)
+	}
+	want := []string{"server", "security group", "keypair", "volume"}
+	if strings.Join(api.deleted, ",") != strings.Join(want, ",") {
+		t.Errorf("deleted %v, want %v", api.deleted, want)
+	}
+}
+
+func TestLaunchServer_MissingKeypair(t *testing.T) {
+	api := newLaunchAPI()
+	server, client := setupTestServer(api.ServeHTTP)
+	defer server.Close()
+
+	spec := launchSpec()
+	spec.PublicKey = ""
+	_, err := client.LaunchServer(context.Background(), spec)
+	if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), `keypair "deploy" does not exist`) {
+		t.Fatalf("err = %v", err)
+	}
+	// The spec is checked before anything is created.
+	if len(api.deleted) != 0 || api.bodies["POST /test-tenant-id/volumes"] != nil {
+		t.Errorf("deleted %v, volume request %v", api.deleted, api.bodies["POST /test-tenant-id/volumes"])
+	}
+}
+
+func TestLaunchServer_RollbackError(t *testing.T) {
+	api := newLaunchAPI()
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/servers" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"badRequest":{"code":400,"message":"bad flavor"}}`))
+			return
+		}
+		if r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/security-groups/") {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"NeutronError":{"message":"in use"}}`))
+			return
+		}
+		api.ServeHTTP(w, r)
+	})
+	defer server.Close()
+
+	_, err := client.LaunchServer(context.Background(), launchSpec())
+	assertAPIError(t, err, http.StatusBadRequest)
+	if !errors.Is(err, ErrConflict) || !strings.Contains(err.Error(), "delete security group sg-web") {
+		t.Errorf("err = %v", err)
+	}
+	if strings.Join(api.deleted, ",") != "keypair,volume" {
+		t.Errorf("deleted %v", api.deleted)
+	}
+}
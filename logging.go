@@ -0,0 +1,129 @@
+package conoha
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// WithLogger logs every request attempt to logger.
+//
+// Each response is logged at Info level (Warn for errors and 4xx/5xx
+// statuses) with the method, URL, status, latency, retry attempt and the
+// OpenStack request ID. At Debug level the request and response headers
+// and JSON bodies are logged as well. Tokens, passwords, adminPass,
+// credential secrets and temp URL keys are always masked.
+//
+// Example:
+//
+//	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+//	client := conoha.NewClient(conoha.WithLogger(logger))
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// doLogged sends req through d, logging it to the client's logger.
+// attempt is the 1-based attempt number within the retry loop.
+func (c *Client) doLogged(d Doer, req *http.Request, attempt int) (*http.Response, error) {
+	logger := c.logger
+	if logger == nil {
+		return d.Do(req)
+	}
+	ctx := req.Context()
+	debug := logger.Enabled(ctx, slog.LevelDebug)
+
+	if debug {
+		attrs := []slog.Attr{
+			slog.String("method", req.Method),
+			slog.String("url", req.URL.String()),
+			slog.Int("attempt", attempt),
+			slog.Any("headers", redactHeader(req.Header)),
+		}
+		if body := requestBodyForLog(req); body != "" {
+			attrs = append(attrs, slog.String("body", body))
+		}
+		logger.LogAttrs(ctx, slog.LevelDebug, "conoha request", attrs...)
+	}
+
+	start := time.Now()
+	resp, err := d.Do(req)
+	latency := time.Since(start)
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Duration("latency", latency),
+		slog.Int("attempt", attempt),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		logger.LogAttrs(ctx, slog.LevelWarn, "conoha request failed", attrs...)
+		return nil, err
+	}
+
+	requestID := responseRequestID(resp.Header)
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+	level := slog.LevelInfo
+	if resp.StatusCode >= 400 {
+		level = slog.LevelWarn
+	}
+	logger.LogAttrs(ctx, level, "conoha response", attrs...)
+
+	if debug {
+		attrs := []slog.Attr{
+			slog.String("method", req.Method),
+			slog.String("url", req.URL.String()),
+			slog.Int("status", resp.StatusCode),
+			slog.Any("headers", redactHeader(resp.Header)),
+		}
+		if b, ok := bufferJSONBody(resp); ok {
+			attrs = append(attrs, slog.String("body", string(redactBody(b))))
+		}
+		logger.LogAttrs(ctx, slog.LevelDebug, "conoha response body", attrs...)
+	}
+	return resp, nil
+}
+
+// requestBodyForLog returns the redacted request body, or "" if the body
+// is empty or cannot be read without consuming it.
+func requestBodyForLog(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+	return string(redactBody(b))
+}
+
+// responseRequestID returns the request ID the API assigned to a response.
+// Most services use X-Openstack-Request-Id; Compute and Object Storage
+// have their own headers.
+func responseRequestID(h http.Header) string {
+	for _, name := range []string{RequestIDHeader, "X-Compute-Request-Id", "X-Trans-Id"} {
+		if id := h.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// logEvent logs a client event such as a token refresh, if a logger is set.
+func (c *Client) logEvent(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.logger != nil {
+		c.logger.LogAttrs(ctx, level, msg, attrs...)
+	}
+}
@@ -0,0 +1,151 @@
+package conoha
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// captureLogger returns a logger that writes JSON records at level to buf.
+func captureLogger(buf *bytes.Buffer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: level}))
+}
+
+// logRecords decodes the JSON records written by captureLogger.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestWithLogger_LogsResponses(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Openstack-Request-Id", "req-abc")
+		w.Write([]byte(`{"server":{"id":"s1"}}`))
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	WithLogger(captureLogger(&buf, slog.LevelInfo))(client)
+
+	_, err := client.GetServer(context.Background(), "s1")
+	assertNoError(t, err)
+
+	records := logRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1: %s", len(records), buf.String())
+	}
+	rec := records[0]
+	if rec["msg"] != "conoha response" || rec["level"] != "INFO" {
+		t.Errorf("record = %v", rec)
+	}
+	if rec["method"] != "GET" || !strings.HasSuffix(rec["url"].(string), "/servers/s1") {
+		t.Errorf("method/url = %v %v", rec["method"], rec["url"])
+	}
+	if rec["status"] != float64(200) || rec["attempt"] != float64(1) || rec["request_id"] != "req-abc" {
+		t.Errorf("status/attempt/request_id = %v %v %v", rec["status"], rec["attempt"], rec["request_id"])
+	}
+	if _, ok := rec["latency"]; !ok {
+		t.Error("latency missing")
+	}
+}
+
+func TestWithLogger_LogsRetryAttempts(t *testing.T) {
+	var calls int
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(204)
+	})
+	defer server.Close()
+	fastRetries(client)
+
+	var buf bytes.Buffer
+	WithLogger(captureLogger(&buf, slog.LevelInfo))(client)
+
+	assertNoError(t, client.DeleteServer(context.Background(), "s1"))
+
+	var got []string
+	for _, rec := range logRecords(t, &buf) {
+		got = append(got, rec["level"].(string)+" "+rec["msg"].(string))
+	}
+	want := "WARN conoha response,INFO conoha retrying request,INFO conoha response"
+	if strings.Join(got, ",") != want {
+		t.Errorf("records = %v, want %s", got, want)
+	}
+}
+
+func TestWithLogger_DebugBodiesAreMasked(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"server":{"id":"s1","adminPass":"generated-pass"}}`))
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	WithLogger(captureLogger(&buf, slog.LevelDebug))(client)
+
+	_, err := client.CreateServer(context.Background(), CreateServerRequest{
+		FlavorRef: "f1",
+		AdminPass: "my-admin-pass",
+	})
+	assertNoError(t, err)
+
+	out := buf.String()
+	for _, secret := range []string{"my-admin-pass", "generated-pass", "test-token"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log contains %q:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{`"msg":"conoha request"`, `"msg":"conoha response body"`, `flavorRef`, `s1`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log does not contain %s:\n%s", want, out)
+		}
+	}
+}
+
+func TestWithLogger_NoBodiesAboveDebug(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"server":{"id":"s1","name":"visible-only-in-debug"}}`))
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	WithLogger(captureLogger(&buf, slog.LevelInfo))(client)
+
+	_, err := client.GetServer(context.Background(), "s1")
+	assertNoError(t, err)
+	if strings.Contains(buf.String(), "visible-only-in-debug") {
+		t.Errorf("body logged at info level:\n%s", buf.String())
+	}
+}
+
+func TestResponseRequestID(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Trans-Id", "tx123")
+	if got := responseRequestID(h); got != "tx123" {
+		t.Errorf("responseRequestID = %q, want tx123", got)
+	}
+	h.Set("X-Compute-Request-Id", "req-compute")
+	if got := responseRequestID(h); got != "req-compute" {
+		t.Errorf("responseRequestID = %q, want req-compute", got)
+	}
+}
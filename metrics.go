@@ -0,0 +1,216 @@
+package conoha
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsCollector receives measurements of the API calls made by a Client.
+// Implementations must be safe for concurrent use.
+//
+// PrometheusCollector is a dependency-free implementation that serves the
+// Prometheus text format; adapters for other metrics libraries only need
+// these three methods.
+type MetricsCollector interface {
+	// ObserveRequest is called once per SDK operation when its final
+	// response arrives. statusCode is 0 when no response was received, in
+	// which case err is the cause. latency includes retries and token
+	// refreshes.
+	ObserveRequest(op Operation, statusCode int, latency time.Duration, err error)
+
+	// IncRetry is called before every retry of op.
+	IncRetry(op Operation)
+
+	// IncTokenRefresh is called after every automatic token refresh. err is
+	// nil if the refresh succeeded.
+	IncTokenRefresh(err error)
+}
+
+// WithMetrics reports measurements of every API call to m.
+func WithMetrics(m MetricsCollector) ClientOption {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// operationKey is the context key of the Operation a request belongs to.
+type operationKey struct{}
+
+// operationFromContext returns the Operation stored by send, if any.
+func operationFromContext(ctx context.Context) Operation {
+	op, _ := ctx.Value(operationKey{}).(Operation)
+	return op
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency
+// histogram buckets used by PrometheusCollector.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// PrometheusCollector is a MetricsCollector that keeps its metrics in
+// memory and serves them in the Prometheus text exposition format:
+//
+//	conoha_requests_total{service, operation, status}
+//	conoha_request_errors_total{service, operation, status}
+//	conoha_request_duration_seconds{service, operation} (histogram)
+//	conoha_retries_total{service, operation}
+//	conoha_token_refreshes_total{result}
+//
+// status is the HTTP status code, or "error" when no response was
+// received. Requests count as errors when they fail or return a status of
+// 400 or above.
+//
+// Example:
+//
+//	metrics := conoha.NewPrometheusCollector()
+//	client := conoha.NewClient(conoha.WithMetrics(metrics))
+//	http.Handle("/metrics", metrics)
+type PrometheusCollector struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[[3]string]uint64
+	errors    map[[3]string]uint64
+	latencies map[[2]string]*histogram
+	retries   map[[2]string]uint64
+	refreshes map[string]uint64
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusCollector returns an empty collector that uses
+// DefaultLatencyBuckets.
+func NewPrometheusCollector() *PrometheusCollector {
+	return &PrometheusCollector{
+		buckets:   DefaultLatencyBuckets,
+		requests:  make(map[[3]string]uint64),
+		errors:    make(map[[3]string]uint64),
+		latencies: make(map[[2]string]*histogram),
+		retries:   make(map[[2]string]uint64),
+		refreshes: make(map[string]uint64),
+	}
+}
+
+// ObserveRequest implements MetricsCollector.
+func (p *PrometheusCollector) ObserveRequest(op Operation, statusCode int, latency time.Duration, err error) {
+	status := "error"
+	if statusCode != 0 {
+		status = strconv.Itoa(statusCode)
+	}
+	key := [3]string{op.Service, op.Name, status}
+	opKey := [2]string{op.Service, op.Name}
+	seconds := latency.Seconds()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests[key]++
+	if err != nil || statusCode >= 400 {
+		p.errors[key]++
+	}
+	h := p.latencies[opKey]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		p.latencies[opKey] = h
+	}
+	for i, le := range p.buckets {
+		if seconds <= le {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// IncRetry implements MetricsCollector.
+func (p *PrometheusCollector) IncRetry(op Operation) {
+	p.mu.Lock()
+	p.retries[[2]string{op.Service, op.Name}]++
+	p.mu.Unlock()
+}
+
+// IncTokenRefresh implements MetricsCollector.
+func (p *PrometheusCollector) IncTokenRefresh(err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	p.mu.Lock()
+	p.refreshes[result]++
+	p.mu.Unlock()
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (p *PrometheusCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+func (p *PrometheusCollector) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+
+	p.mu.Lock()
+	writeCounter3(&b, "conoha_requests_total", "Total number of ConoHa API requests.", p.requests)
+	writeCounter3(&b, "conoha_request_errors_total", "Total number of failed ConoHa API requests.", p.errors)
+
+	b.WriteString("# HELP conoha_request_duration_seconds Latency of ConoHa API requests.\n")
+	b.WriteString("# TYPE conoha_request_duration_seconds histogram\n")
+	for _, k := range sortedKeys(p.latencies) {
+		h := p.latencies[k]
+		labels := fmt.Sprintf(`service=%q,operation=%q`, k[0], k[1])
+		var cumulative uint64
+		for i, le := range p.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "conoha_request_duration_seconds_bucket{%s,le=%q} %d\n", labels, strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(&b, "conoha_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		fmt.Fprintf(&b, "conoha_request_duration_seconds_sum{%s} %g\n", labels, h.sum)
+		fmt.Fprintf(&b, "conoha_request_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+
+	b.WriteString("# HELP conoha_retries_total Total number of retried ConoHa API requests.\n")
+	b.WriteString("# TYPE conoha_retries_total counter\n")
+	for _, k := range sortedKeys(p.retries) {
+		fmt.Fprintf(&b, "conoha_retries_total{service=%q,operation=%q} %d\n", k[0], k[1], p.retries[k])
+	}
+
+	b.WriteString("# HELP conoha_token_refreshes_total Total number of automatic token refreshes.\n")
+	b.WriteString("# TYPE conoha_token_refreshes_total counter\n")
+	for _, result := range sortedKeys(p.refreshes) {
+		fmt.Fprintf(&b, "conoha_token_refreshes_total{result=%q} %d\n", result, p.refreshes[result])
+	}
+	p.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func writeCounter3(b *strings.Builder, name, help string, values map[[3]string]uint64) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, k := range sortedKeys(values) {
+		fmt.Fprintf(b, "%s{service=%q,operation=%q,status=%q} %d\n", name, k[0], k[1], k[2], values[k])
+	}
+}
+
+// sortedKeys returns the keys of m in a stable order.
+func sortedKeys[K [2]string | [3]string | string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
@@ -0,0 +1,119 @@
+package conoha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMetrics records the calls made to a MetricsCollector.
+type recordingMetrics struct {
+	mu        sync.Mutex
+	requests  []string
+	retries   []string
+	refreshes []error
+}
+
+func (m *recordingMetrics) ObserveRequest(op Operation, statusCode int, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, op.String()+" "+http.StatusText(statusCode))
+}
+
+func (m *recordingMetrics) IncRetry(op Operation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries = append(m.retries, op.String())
+}
+
+func (m *recordingMetrics) IncTokenRefresh(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refreshes = append(m.refreshes, err)
+}
+
+func TestMetrics_ObservesOperationsAndRetries(t *testing.T) {
+	var calls int
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(204)
+	})
+	defer server.Close()
+	client.ComputeURL = server.URL + "/compute"
+	fastRetries(client)
+	metrics := &recordingMetrics{}
+	WithMetrics(metrics)(client)
+
+	assertNoError(t, client.DeleteServer(context.Background(), "s1"))
+
+	if strings.Join(metrics.requests, ",") != "conoha.compute.DeleteServer No Content" {
+		t.Errorf("requests = %v", metrics.requests)
+	}
+	if strings.Join(metrics.retries, ",") != "conoha.compute.DeleteServer" {
+		t.Errorf("retries = %v", metrics.retries)
+	}
+}
+
+func TestMetrics_CountsTokenRefreshes(t *testing.T) {
+	ts := &tokenRefreshServer{}
+	server, client := setupTestServer(ts.handler)
+	defer server.Close()
+	metrics := &recordingMetrics{}
+	WithMetrics(metrics)(client)
+
+	_, err := client.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+	ts.mu.Lock()
+	ts.issued++ // revoke the token
+	ts.mu.Unlock()
+
+	_, err = client.ListServers(context.Background(), nil)
+	assertNoError(t, err)
+
+	if len(metrics.refreshes) != 1 || metrics.refreshes[0] != nil {
+		t.Errorf("refreshes = %v, want one successful refresh", metrics.refreshes)
+	}
+}
+
+func TestPrometheusCollector(t *testing.T) {
+	p := NewPrometheusCollector()
+	create := Operation{Service: ServiceTypeCompute, Name: "CreateServer"}
+	get := Operation{Service: ServiceTypeCompute, Name: "GetServer"}
+
+	p.ObserveRequest(create, 202, 80*time.Millisecond, nil)
+	p.ObserveRequest(get, 404, 20*time.Millisecond, nil)
+	p.ObserveRequest(get, 0, 3*time.Second, context.DeadlineExceeded)
+	p.IncRetry(get)
+	p.IncTokenRefresh(nil)
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := rec.Body.String()
+
+	for _, want := range []string{
+		`conoha_requests_total{service="compute",operation="CreateServer",status="202"} 1`,
+		`conoha_requests_total{service="compute",operation="GetServer",status="404"} 1`,
+		`conoha_request_errors_total{service="compute",operation="GetServer",status="error"} 1`,
+		`conoha_request_duration_seconds_bucket{service="compute",operation="CreateServer",le="0.05"} 0`,
+		`conoha_request_duration_seconds_bucket{service="compute",operation="CreateServer",le="0.1"} 1`,
+		`conoha_request_duration_seconds_bucket{service="compute",operation="GetServer",le="5"} 2`,
+		`conoha_request_duration_seconds_count{service="compute",operation="GetServer"} 2`,
+		`conoha_retries_total{service="compute",operation="GetServer"} 1`,
+		`conoha_token_refreshes_total{result="success"} 1`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("output does not contain %s\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `conoha_request_errors_total{service="compute",operation="CreateServer"`) {
+		t.Errorf("successful request counted as error:\n%s", out)
+	}
+}
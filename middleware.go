@@ -0,0 +1,264 @@
+package conoha
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+)
+
+// Doer sends a single HTTP request. *http.Client implements Doer.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts an ordinary function to the Doer interface.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer that sends requests to the API.
+//
+// Every request the client sends goes through the middleware chain,
+// including each retry attempt and the token requests of Authenticate.
+// A middleware may modify the request headers, replace the response, or
+// return without calling next at all (e.g. to inject faults in tests).
+type Middleware func(next Doer) Doer
+
+// WithMiddleware adds middlewares to the client. The first middleware is
+// the outermost one: it sees the request first and the response last.
+// The option can be given several times; middlewares accumulate.
+//
+// Example:
+//
+//	client := conoha.NewClient(conoha.WithMiddleware(
+//	    conoha.UserAgentMiddleware("my-tool/1.0"),
+//	    conoha.RequestIDMiddleware(),
+//	))
+func WithMiddleware(mw ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, mw...)
+	}
+}
+
+// doer returns the client's HTTP client wrapped in its middlewares.
+func (c *Client) doer() Doer {
+	var d Doer = c.HTTPClient
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		d = c.middlewares[i](d)
+	}
+	return d
+}
+
+// UserAgentMiddleware sets the User-Agent header of every request.
+func UserAgentMiddleware(userAgent string) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("User-Agent", userAgent)
+			return next.Do(req)
+		})
+	}
+}
+
+// RequestIDHeader is the header OpenStack services use for request IDs.
+const RequestIDHeader = "X-Openstack-Request-Id"
+
+// RequestIDMiddleware sets a random "req-<uuid>" X-Openstack-Request-Id
+// header on requests that do not have one yet, so that a call can be
+// traced through the ConoHa service logs. Retries of the same call keep
+// the same ID.
+func RequestIDMiddleware() Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(RequestIDHeader) == "" {
+				req.Header.Set(RequestIDHeader, newRequestID())
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// newRequestID returns an OpenStack style request ID with a random UUID.
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("req-%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// DumpMiddleware writes every request and response to w in HTTP wire
+// format, for debugging. Tokens, temp URL keys, passwords and credential
+// secrets are redacted.
+//
+// Request bodies are dumped only when they can be replayed, and response
+// bodies only when they are JSON, so that streaming uploads and object
+// downloads are never buffered in memory.
+func DumpMiddleware(w io.Writer) Middleware {
+	var mu sync.Mutex
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if dump, err := dumpRequest(req); err == nil {
+				mu.Lock()
+				fmt.Fprintf(w, "%s\n", dump)
+				mu.Unlock()
+			}
+
+			resp, err := next.Do(req)
+			if err != nil {
+				return nil, err
+			}
+
+			if dump, err := dumpResponse(resp); err == nil {
+				mu.Lock()
+				fmt.Fprintf(w, "%s\n", dump)
+				mu.Unlock()
+			}
+			return resp, nil
+		})
+	}
+}
+
+func dumpRequest(req *http.Request) ([]byte, error) {
+	clone := req.Clone(req.Context())
+	clone.Header = redactHeader(req.Header)
+	clone.Body = nil
+	clone.ContentLength = 0
+	if req.GetBody == nil {
+		return httputil.DumpRequestOut(clone, false)
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	b = redactBody(b)
+	clone.Body = io.NopCloser(bytes.NewReader(b))
+	clone.ContentLength = int64(len(b))
+	return httputil.DumpRequestOut(clone, true)
+}
+
+func dumpResponse(resp *http.Response) ([]byte, error) {
+	shallow := *resp
+	shallow.Header = redactHeader(resp.Header)
+	dump, err := httputil.DumpResponse(&shallow, false)
+	if err != nil {
+		return nil, err
+	}
+	if b, ok := bufferJSONBody(resp); ok {
+		dump = append(dump, redactBody(b)...)
+	}
+	return dump, nil
+}
+
+// bufferJSONBody reads a JSON response body and replaces resp.Body with an
+// in-memory copy, so that it can be inspected without consuming it. Other
+// bodies, such as object downloads, are left untouched and ok is false.
+// If reading fails, ok is false and resp.Body returns what was read and
+// then the error.
+func bufferJSONBody(resp *http.Response) (body []byte, ok bool) {
+	if !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return nil, false
+	}
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(b), errReader{err}))
+		return nil, false
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	return b, true
+}
+
+// errReader is a reader that fails with err.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// sensitiveHeaders are redacted from dumps and logs.
+var sensitiveHeaders = []string{
+	"X-Auth-Token",
+	"X-Subject-Token",
+	"X-Account-Meta-Temp-Url-Key",
+	"X-Account-Meta-Temp-Url-Key-2",
+	"X-Container-Meta-Temp-Url-Key",
+	"X-Container-Meta-Temp-Url-Key-2",
+}
+
+// redactedValue replaces the values of sensitive headers and fields.
+const redactedValue = "[REDACTED]"
+
+// redactHeader returns a copy of h with sensitive values replaced.
+func redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, redactedValue)
+		}
+	}
+	return out
+}
+
+// sensitiveFields are JSON object keys whose values are redacted from dumps
+// and logs, compared case-insensitively.
+var sensitiveFields = map[string]bool{
+	"password":  true,
+	"adminpass": true,
+	"secret":    true,
+}
+
+// redactBody returns body with the values of sensitive JSON fields replaced.
+// Bodies that are not JSON are returned unchanged.
+func redactBody(body []byte) []byte {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	if !redactValue(v) {
+		return body
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// redactValue redacts sensitive fields in a decoded JSON value in place
+// and reports whether it changed anything.
+func redactValue(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if _, isString := val.(string); isString && sensitiveFields[strings.ToLower(k)] {
+				v[k] = redactedValue
+				changed = true
+				continue
+			}
+			if redactValue(val) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, val := range v {
+			if redactValue(val) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,244 @@
+package conoha
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMiddleware_Order(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"server":{"id":"s1"}}`))
+	})
+	defer server.Close()
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next.Do(req)
+				calls = append(calls, name+" after")
+				return resp, err
+			})
+		}
+	}
+	WithMiddleware(trace("outer"))(client)
+	WithMiddleware(trace("inner"))(client)
+
+	_, err := client.GetServer(context.Background(), "s1")
+	assertNoError(t, err)
+
+	want := "outer before,inner before,inner after,outer after"
+	if got := strings.Join(calls, ","); got != want {
+		t.Errorf("calls = %s, want %s", got, want)
+	}
+}
+
+func TestMiddleware_CoversStreamingPaths(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte("data"))
+	})
+	defer server.Close()
+
+	var seen []string
+	WithMiddleware(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			seen = append(seen, req.Method+" "+req.URL.Path)
+			return next.Do(req)
+		})
+	})(client)
+	ctx := context.Background()
+
+	assertNoError(t, client.UploadObject(ctx, "c", "o", strings.NewReader("data")))
+	body, err := client.DownloadObject(ctx, "c", "o")
+	assertNoError(t, err)
+	body.Close()
+	assertNoError(t, client.DeleteObject(ctx, "c", "o"))
+	assertNoError(t, client.UploadISOImage(ctx, "img1", strings.NewReader("iso")))
+
+	want := []string{
+		"PUT /AUTH_test-tenant-id/c/o",
+		"GET /AUTH_test-tenant-id/c/o",
+		"DELETE /AUTH_test-tenant-id/c/o",
+		"PUT /images/img1/file",
+	}
+	if strings.Join(seen, "\n") != strings.Join(want, "\n") {
+		t.Errorf("middleware saw:\n%s\nwant:\n%s", strings.Join(seen, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestMiddleware_FaultInjectionIsRetried(t *testing.T) {
+	var calls int32
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"server":{"id":"s1"}}`))
+	})
+	defer server.Close()
+	fastRetries(client)
+
+	var injected int32
+	WithMiddleware(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&injected, 1) == 1 {
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Status:     "503 Service Unavailable",
+					Header:     http.Header{},
+					Body:       http.NoBody,
+					Request:    req,
+				}, nil
+			}
+			return next.Do(req)
+		})
+	})(client)
+
+	_, err := client.GetServer(context.Background(), "s1")
+	assertNoError(t, err)
+	if calls != 1 || injected != 2 {
+		t.Errorf("server calls = %d, middleware calls = %d; want 1, 2", calls, injected)
+	}
+}
+
+func TestUserAgentMiddleware(t *testing.T) {
+	var ua string
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		ua = r.Header.Get("User-Agent")
+		w.WriteHeader(204)
+	})
+	defer server.Close()
+	WithMiddleware(UserAgentMiddleware("my-tool/1.0"))(client)
+
+	assertNoError(t, client.DeleteServer(context.Background(), "s1"))
+	if ua != "my-tool/1.0" {
+		t.Errorf("User-Agent = %q", ua)
+	}
+}
+
+func TestRequestIDMiddleware_KeepsIDAcrossRetries(t *testing.T) {
+	var ids []string
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get(RequestIDHeader))
+		if len(ids) == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(204)
+	})
+	defer server.Close()
+	fastRetries(client)
+	WithMiddleware(RequestIDMiddleware())(client)
+
+	assertNoError(t, client.DeleteServer(context.Background(), "s1"))
+
+	if len(ids) != 2 {
+		t.Fatalf("attempts = %d, want 2", len(ids))
+	}
+	if !regexp.MustCompile(`^req-[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(ids[0]) {
+		t.Errorf("request ID = %q", ids[0])
+	}
+	if ids[0] != ids[1] {
+		t.Errorf("retry used request ID %q, want %q", ids[1], ids[0])
+	}
+}
+
+func TestDumpMiddleware_Redacts(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Subject-Token", "issued-token")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(201)
+		w.Write([]byte(`{"token":{"expires_at":"2099-01-01T00:00:00Z","project":{"id":"t1"}}}`))
+	})
+	defer server.Close()
+	client.Token = ""
+
+	var buf bytes.Buffer
+	WithMiddleware(DumpMiddleware(&buf))(client)
+
+	_, err := client.Authenticate(context.Background(), "user-id", "s3cret-pass", "t1")
+	assertNoError(t, err)
+
+	dump := buf.String()
+	for _, secret := range []string{"s3cret-pass", "issued-token"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("dump contains %q:\n%s", secret, dump)
+		}
+	}
+	for _, want := range []string{"POST /auth/tokens", "user-id", "X-Subject-Token: [REDACTED]", "2099-01-01"} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("dump does not contain %q:\n%s", want, dump)
+		}
+	}
+	if client.currentToken() != "issued-token" {
+		t.Errorf("Token = %q, dumping must not change the response", client.currentToken())
+	}
+}
+
+func TestDumpMiddleware_LeavesStreamsAlone(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte("object payload"))
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	WithMiddleware(DumpMiddleware(&buf))(client)
+
+	body, err := client.DownloadObject(context.Background(), "c", "o")
+	assertNoError(t, err)
+	defer body.Close()
+	data, _ := io.ReadAll(body)
+
+	if string(data) != "object payload" {
+		t.Errorf("body = %q", data)
+	}
+	if strings.Contains(buf.String(), "object payload") {
+		t.Errorf("dump contains the object body:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "X-Auth-Token: [REDACTED]") {
+		t.Errorf("dump does not redact X-Auth-Token:\n%s", buf.String())
+	}
+}
+
+func TestDumpMiddleware_KeepsReadError(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte(`{"server":`)) // the connection closes short of 100 bytes
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	WithMiddleware(DumpMiddleware(&buf))(client)
+
+	_, err := client.GetServer(context.Background(), "srv-1")
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("err = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	in := `{"server":{"adminPass":"p","name":"web"},"credentials":[{"secret":"s","access":"a"}]}`
+	out := string(redactBody([]byte(in)))
+
+	for _, secret := range []string{`"p"`, `"s"`} {
+		if strings.Contains(out, secret) {
+			t.Errorf("redactBody left %s in %s", secret, out)
+		}
+	}
+	for _, keep := range []string{`"web"`, `"a"`} {
+		if !strings.Contains(out, keep) {
+			t.Errorf("redactBody removed %s from %s", keep, out)
+		}
+	}
+	if got := string(redactBody([]byte("not json"))); got != "not json" {
+		t.Errorf("redactBody(non-JSON) = %q", got)
+	}
+}
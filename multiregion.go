@@ -0,0 +1,237 @@
+package conoha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MultiRegionClient holds one Client per region of a service catalog, all
+// sharing the token of a single authentication.
+//
+//	client := conoha.NewClient()
+//	client.Authenticate(ctx, userID, password, tenantID)
+//	mr, err := conoha.NewMultiRegionClient(ctx, client)
+//	servers, err := mr.ListAllServers(ctx, nil) // servers of every region
+type MultiRegionClient struct {
+	regions []string
+	clients map[string]*Client
+}
+
+// NewMultiRegionClient creates a Client for every region in the service
+// catalog of base, which must be authenticated (or created with
+// NewClientFromConfig, in which case it authenticates now).
+//
+// The region clients use the token of base and the endpoints the catalog
+// lists for their region, and share the HTTP client, retry policy, rate
+// limiter, middlewares, logger, tracer, metrics and endpoint resolver of
+// base. When their token expires or is rejected, base re-issues it once
+// for all regions.
+func NewMultiRegionClient(ctx context.Context, base *Client) (*MultiRegionClient, error) {
+	if base.lazyAuth != nil && base.currentToken() == "" && base.canReauth() {
+		if err := base.refreshToken(ctx, ""); err != nil {
+			return nil, fmt.Errorf("authenticate: %w", err)
+		}
+	}
+
+	base.mu.RLock()
+	catalog := base.catalog
+	base.mu.RUnlock()
+	regions := catalogRegions(catalog)
+	if len(regions) == 0 {
+		return nil, errors.New("conoha: no regions in the service catalog; authenticate the client first")
+	}
+
+	m := &MultiRegionClient{regions: regions, clients: make(map[string]*Client)}
+	for _, region := range regions {
+		m.clients[region] = base.regionClient(region, catalog)
+	}
+	return m, nil
+}
+
+// catalogRegions returns the sorted regions of the public endpoints in
+// catalog.
+func catalogRegions(catalog []ServiceCatalog) []string {
+	seen := make(map[string]bool)
+	for _, svc := range catalog {
+		for _, ep := range svc.Endpoints {
+			region := ep.Region
+			if region == "" {
+				region = ep.RegionID
+			}
+			if ep.Interface == "public" && region != "" {
+				seen[region] = true
+			}
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// regionClient returns a Client for region that takes its endpoints from
+// catalog and its token from c.
+func (c *Client) regionClient(region string, catalog []ServiceCatalog) *Client {
+	c.mu.RLock()
+	rc := &Client{
+		HTTPClient:     c.HTTPClient,
+		Token:          c.Token,
+		TenantID:       c.TenantID,
+		Region:         region,
+		explicitURLs:   make(map[string]bool),
+		explicitRegion: true,
+		tokenExpiresAt: c.tokenExpiresAt,
+		catalog:        catalog,
+		retryPolicy:    c.retryPolicy,
+		limiter:        c.limiter,
+		middlewares:    c.middlewares,
+		logger:         c.logger,
+		tracer:         c.tracer,
+		metrics:        c.metrics,
+		resolver:       c.resolver,
+	}
+	c.mu.RUnlock()
+
+	rc.updateEndpointsFromCatalog(catalog)
+	rc.fillURLsFromRegion()
+
+	// Re-issue through c, so that every region shares one new token. While
+	// c cannot re-issue its token (e.g. it was made WithToken and has not
+	// authenticated since), rc.canReauth reports false.
+	rc.tokenBase = c
+	rc.reauth = func(ctx context.Context) error {
+		if err := c.refreshToken(ctx, rc.currentToken()); err != nil {
+			return err
+		}
+		c.mu.RLock()
+		token, expiresAt := c.Token, c.tokenExpiresAt
+		c.mu.RUnlock()
+		rc.mu.Lock()
+		rc.Token, rc.tokenExpiresAt = token, expiresAt
+		rc.mu.Unlock()
+		return nil
+	}
+	return rc
+}
+
+// Regions returns the regions of the client, sorted by name.
+func (m *MultiRegionClient) Regions() []string {
+	return append([]string(nil), m.regions...)
+}
+
+// Region returns the Client for region, or nil if the catalog has no such
+// region.
+func (m *MultiRegionClient) Region(region string) *Client {
+	return m.clients[region]
+}
+
+// Regional is a value that belongs to a region.
+type Regional[T any] struct {
+	Region string
+	Value  T
+}
+
+// MultiRegionError reports the regions in which a fan-out call failed.
+type MultiRegionError struct {
+	// Errors maps each failed region to its error.
+	Errors map[string]error
+}
+
+func (e *MultiRegionError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, region := range sortedKeys(e.Errors) {
+		msgs = append(msgs, region+": "+e.Errors[region].Error())
+	}
+	return fmt.Sprintf("conoha: failed in %d region(s): %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the failed regions, so that errors.Is and
+// errors.As look at each of them.
+func (e *MultiRegionError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, region := range sortedKeys(e.Errors) {
+		errs = append(errs, e.Errors[region])
+	}
+	return errs
+}
+
+// FanOut calls fn concurrently with the Client of every region. It returns
+// the values of the regions that succeeded, in Regions order, and a
+// *MultiRegionError naming the regions that failed, if any. Results from
+// the successful regions are returned even when others fail.
+func FanOut[T any](ctx context.Context, m *MultiRegionClient, fn func(ctx context.Context, c *Client) (T, error)) ([]Regional[T], error) {
+	values := make([]T, len(m.regions))
+	errs := make([]error, len(m.regions))
+	var wg sync.WaitGroup
+	for i, region := range m.regions {
+		wg.Add(1)
+		go func(i int, c *Client) {
+			defer wg.Done()
+			values[i], errs[i] = fn(ctx, c)
+		}(i, m.clients[region])
+	}
+	wg.Wait()
+
+	var results []Regional[T]
+	failed := make(map[string]error)
+	for i, region := range m.regions {
+		if errs[i] != nil {
+			failed[region] = errs[i]
+			continue
+		}
+		results = append(results, Regional[T]{Region: region, Value: values[i]})
+	}
+	if len(failed) > 0 {
+		return results, &MultiRegionError{Errors: failed}
+	}
+	return results, nil
+}
+
+// fanOutList runs a list call in every region and flattens the results,
+// tagging each item with its region.
+func fanOutList[T any](ctx context.Context, m *MultiRegionClient, list func(ctx context.Context, c *Client) ([]T, error)) ([]Regional[T], error) {
+	pages, err := FanOut(ctx, m, list)
+	var items []Regional[T]
+	for _, page := range pages {
+		for _, item := range page.Value {
+			items = append(items, Regional[T]{Region: page.Region, Value: item})
+		}
+	}
+	return items, err
+}
+
+// ListAllServers lists the servers of every region. On partial failure it
+// returns the servers of the regions that succeeded and a
+// *MultiRegionError.
+func (m *MultiRegionClient) ListAllServers(ctx context.Context, opts *ListServersOptions) ([]Regional[Server], error) {
+	return fanOutList(ctx, m, func(ctx context.Context, c *Client) ([]Server, error) {
+		return c.ListAllServers(ctx, opts)
+	})
+}
+
+// ListAllServersDetail lists the servers of every region with full
+// details. On partial failure it returns the servers of the regions that
+// succeeded and a *MultiRegionError.
+func (m *MultiRegionClient) ListAllServersDetail(ctx context.Context, opts *ListServersOptions) ([]Regional[ServerDetail], error) {
+	return fanOutList(ctx, m, func(ctx context.Context, c *Client) ([]ServerDetail, error) {
+		return c.ListAllServersDetail(ctx, opts)
+	})
+}
+
+// ListAllVolumes lists the volumes of every region. On partial failure it
+// returns the volumes of the regions that succeeded and a
+// *MultiRegionError.
+func (m *MultiRegionClient) ListAllVolumes(ctx context.Context, opts *ListVolumesOptions) ([]Regional[Volume], error) {
+	return fanOutList(ctx, m, func(ctx context.Context, c *Client) ([]Volume, error) {
+		return c.ListAllVolumes(ctx, opts)
+	})
+}
+
+// ListAllImages lists the images of every region. On partial failure it
+// returns the images of the regions that succeeded and a
+// *MultiRegionError.
+func (m *MultiRegionClient) ListAllImages(ctx context.Context, opts *ListImagesOptions) ([]Regional[Image], error) {
+	return fanOutList(ctx, m, func(ctx context.Context, c *Client) ([]Image, error) {
+		return c.ListAllImages(ctx, opts)
+	})
+}
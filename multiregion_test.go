@@ -0,0 +1,170 @@
+package conoha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// multiRegionServer serves a token whose catalog lists compute endpoints
+// in c3j1 and c3j2 under /<region>/compute, and a server list per region.
+// Requests to regions in failing answer 500.
+type multiRegionServer struct {
+	mu      sync.Mutex
+	url     string
+	issued  int
+	failing map[string]bool
+}
+
+func (s *multiRegionServer) handler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if strings.HasSuffix(r.URL.Path, "/auth/tokens") {
+		s.issued++
+		w.Header().Set("X-Subject-Token", fmt.Sprintf("token-%d", s.issued))
+		w.WriteHeader(201)
+		fmt.Fprintf(w, `{"token":{"project":{"id":"tenant"},"catalog":[{"type":"compute","endpoints":[
+			{"interface":"public","region":"c3j1","url":"%[1]s/c3j1/compute/v2.1"},
+			{"interface":"internal","region":"c3j9","url":"%[1]s/c3j9/compute/v2.1"},
+			{"interface":"public","region":"c3j2","url":"%[1]s/c3j2/compute/v2.1"}
+		]}]}}`, s.url)
+		return
+	}
+	if r.Header.Get("X-Auth-Token") != fmt.Sprintf("token-%d", s.issued) {
+		w.WriteHeader(401)
+		return
+	}
+	region := strings.Split(r.URL.Path, "/")[1]
+	if s.failing[region] {
+		w.WriteHeader(500)
+		return
+	}
+	if r.URL.Query().Get("marker") != "" {
+		w.Write([]byte(`{"servers":[]}`))
+		return
+	}
+	fmt.Fprintf(w, `{"servers":[{"id":"%s-1"},{"id":"%s-2"}]}`, region, region)
+}
+
+func newMultiRegionTest(t *testing.T, failing ...string) (*multiRegionServer, *MultiRegionClient) {
+	t.Helper()
+	mrs := &multiRegionServer{failing: make(map[string]bool)}
+	for _, region := range failing {
+		mrs.failing[region] = true
+	}
+	server := httptest.NewServer(http.HandlerFunc(mrs.handler))
+	t.Cleanup(server.Close)
+	mrs.url = server.URL
+
+	client := NewClient(WithIdentityURL(server.URL))
+	_, err := client.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+	mr, err := NewMultiRegionClient(context.Background(), client)
+	assertNoError(t, err)
+	return mrs, mr
+}
+
+func TestMultiRegionClient_Regions(t *testing.T) {
+	mrs, mr := newMultiRegionTest(t)
+
+	if got := strings.Join(mr.Regions(), ","); got != "c3j1,c3j2" {
+		t.Errorf("Regions = %s", got)
+	}
+	c := mr.Region("c3j2")
+	if c == nil || c.Region != "c3j2" || c.ComputeURL != mrs.url+"/c3j2/compute/v2.1" {
+		t.Fatalf("c3j2 client = %+v", c)
+	}
+	if c.NetworkingURL != "https://networking.c3j2.conoha.io/v2.0" {
+		t.Errorf("NetworkingURL = %q, want region pattern", c.NetworkingURL)
+	}
+	if mr.Region("c3j9") != nil {
+		t.Error("region without a public endpoint should not get a client")
+	}
+}
+
+func TestMultiRegionClient_ListAllServers(t *testing.T) {
+	_, mr := newMultiRegionTest(t)
+
+	servers, err := mr.ListAllServers(context.Background(), nil)
+	assertNoError(t, err)
+
+	var got []string
+	for _, s := range servers {
+		got = append(got, s.Region+"/"+s.Value.ID)
+	}
+	if strings.Join(got, ",") != "c3j1/c3j1-1,c3j1/c3j1-2,c3j2/c3j2-1,c3j2/c3j2-2" {
+		t.Errorf("servers = %v", got)
+	}
+}
+
+func TestMultiRegionClient_PartialFailure(t *testing.T) {
+	_, mr := newMultiRegionTest(t, "c3j2")
+
+	servers, err := mr.ListAllServers(context.Background(), nil)
+	var mrErr *MultiRegionError
+	if !errors.As(err, &mrErr) {
+		t.Fatalf("err = %v, want *MultiRegionError", err)
+	}
+	if len(mrErr.Errors) != 1 || mrErr.Errors["c3j2"] == nil {
+		t.Errorf("Errors = %v", mrErr.Errors)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 500 {
+		t.Errorf("errors.As(APIError) = %v", apiErr)
+	}
+	if len(servers) != 2 || servers[0].Region != "c3j1" {
+		t.Errorf("servers = %v, want the c3j1 results", servers)
+	}
+}
+
+func TestMultiRegionClient_SharesTokenRefresh(t *testing.T) {
+	mrs, mr := newMultiRegionTest(t)
+	mrs.mu.Lock()
+	mrs.issued++ // revoke the token
+	mrs.mu.Unlock()
+
+	_, err := mr.ListAllServers(context.Background(), nil)
+	assertNoError(t, err)
+
+	mrs.mu.Lock()
+	defer mrs.mu.Unlock()
+	// The initial token, the revoked one, and one refresh for both regions.
+	if mrs.issued != 3 {
+		t.Errorf("tokens issued = %d, want 3", mrs.issued)
+	}
+}
+
+func TestMultiRegionClient_WithoutReauth(t *testing.T) {
+	mrs := &multiRegionServer{failing: make(map[string]bool)}
+	server := httptest.NewServer(http.HandlerFunc(mrs.handler))
+	defer server.Close()
+	mrs.url = server.URL
+
+	client := NewClient(WithIdentityURL(server.URL), WithToken("stale-token"))
+	_, err := client.ValidateToken(context.Background())
+	assertNoError(t, err)
+	mr, err := NewMultiRegionClient(context.Background(), client)
+	assertNoError(t, err)
+
+	_, err = mr.Region("c3j1").ListServers(context.Background(), nil)
+	if !IsUnauthorized(err) {
+		t.Errorf("err = %v, want the 401", err)
+	}
+
+	// Once the base client authenticates, the region clients take its token.
+	_, err = client.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+	_, err = mr.Region("c3j1").ListServers(context.Background(), nil)
+	assertNoError(t, err)
+}
+
+func TestNewMultiRegionClient_RequiresCatalog(t *testing.T) {
+	if _, err := NewMultiRegionClient(context.Background(), NewClient()); err == nil {
+		t.Error("want an error for an unauthenticated client")
+	}
+}
@@ -52,7 +52,7 @@ type qosPolicyResponse struct {
 
 // ListQoSPolicies lists all QoS policies.
 func (c *Client) ListQoSPolicies(ctx context.Context, opts *ListQoSPoliciesOptions) ([]QoSPolicy, error) {
-	url := c.NetworkingURL + "/qos/policies"
+	url := c.networkingURL() + "/qos/policies"
 	if opts != nil {
 		params := map[string]string{}
 		if opts.Limit > 0 {
@@ -76,7 +76,7 @@ func (c *Client) ListQoSPolicies(ctx context.Context, opts *ListQoSPoliciesOptio
 
 // GetQoSPolicy gets a QoS policy's details.
 func (c *Client) GetQoSPolicy(ctx context.Context, policyID string) (*QoSPolicy, error) {
-	url := fmt.Sprintf("%s/qos/policies/%s", c.NetworkingURL, policyID)
+	url := fmt.Sprintf("%s/qos/policies/%s", c.networkingURL(), policyID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -132,7 +132,7 @@ type subnetResponse struct {
 
 // ListSubnets lists all subnets.
 func (c *Client) ListSubnets(ctx context.Context, opts *ListSubnetsOptions) ([]Subnet, error) {
-	url := c.NetworkingURL + "/subnets"
+	url := c.networkingURL() + "/subnets"
 	if opts != nil {
 		params := map[string]string{}
 		if opts.Limit > 0 {
@@ -156,7 +156,7 @@ func (c *Client) ListSubnets(ctx context.Context, opts *ListSubnetsOptions) ([]S
 
 // GetSubnet gets a subnet's details.
 func (c *Client) GetSubnet(ctx context.Context, subnetID string) (*Subnet, error) {
-	url := fmt.Sprintf("%s/subnets/%s", c.NetworkingURL, subnetID)
+	url := fmt.Sprintf("%s/subnets/%s", c.networkingURL(), subnetID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -170,7 +170,7 @@ func (c *Client) GetSubnet(ctx context.Context, subnetID string) (*Subnet, error
 
 // CreateSubnet creates a subnet on a local network.
 func (c *Client) CreateSubnet(ctx context.Context, networkID, cidr string) (*Subnet, error) {
-	url := c.NetworkingURL + "/subnets"
+	url := c.networkingURL() + "/subnets"
 	body := map[string]interface{}{
 		"subnet": map[string]string{
 			"network_id": networkID,
@@ -190,7 +190,7 @@ func (c *Client) CreateSubnet(ctx context.Context, networkID, cidr string) (*Sub
 
 // DeleteSubnet deletes a subnet.
 func (c *Client) DeleteSubnet(ctx context.Context, subnetID string) error {
-	url := fmt.Sprintf("%s/subnets/%s", c.NetworkingURL, subnetID)
+	url := fmt.Sprintf("%s/subnets/%s", c.networkingURL(), subnetID)
 	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
@@ -272,7 +272,7 @@ type securityGroupRuleResponse struct {
 
 // ListSecurityGroups lists all security groups.
 func (c *Client) ListSecurityGroups(ctx context.Context, opts *ListSecurityGroupsOptions) ([]SecurityGroup, error) {
-	url := c.NetworkingURL + "/security-groups"
+	url := c.networkingURL() + "/security-groups"
 	if opts != nil {
 		params := map[string]string{}
 		if opts.Limit > 0 {
@@ -296,7 +296,7 @@ func (c *Client) ListSecurityGroups(ctx context.Context, opts *ListSecurityGroup
 
 // GetSecurityGroup gets a security group's details.
 func (c *Client) GetSecurityGroup(ctx context.Context, sgID string) (*SecurityGroup, error) {
-	url := fmt.Sprintf("%s/security-groups/%s", c.NetworkingURL, sgID)
+	url := fmt.Sprintf("%s/security-groups/%s", c.networkingURL(), sgID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -310,7 +310,7 @@ func (c *Client) GetSecurityGroup(ctx context.Context, sgID string) (*SecurityGr
 
 // CreateSecurityGroup creates a security group.
 func (c *Client) CreateSecurityGroup(ctx context.Context, name, description string) (*SecurityGroup, error) {
-	url := c.NetworkingURL + "/security-groups"
+	url := c.networkingURL() + "/security-groups"
 	body := map[string]interface{}{
 		"security_group": map[string]string{
 			"name":        name,
@@ -330,7 +330,7 @@ func (c *Client) CreateSecurityGroup(ctx context.Context, name, description stri
 
 // UpdateSecurityGroup updates a security group.
 func (c *Client) UpdateSecurityGroup(ctx context.Context, sgID, name, description string) (*SecurityGroup, error) {
-	url := fmt.Sprintf("%s/security-groups/%s", c.NetworkingURL, sgID)
+	url := fmt.Sprintf("%s/security-groups/%s", c.networkingURL(), sgID)
 	sgBody := map[string]string{}
 	if name != "" {
 		sgBody["name"] = name
@@ -352,7 +352,7 @@ func (c *Client) UpdateSecurityGroup(ctx context.Context, sgID, name, descriptio
 
 // DeleteSecurityGroup deletes a security group.
 func (c *Client) DeleteSecurityGroup(ctx context.Context, sgID string) error {
-	url := fmt.Sprintf("%s/security-groups/%s", c.NetworkingURL, sgID)
+	url := fmt.Sprintf("%s/security-groups/%s", c.networkingURL(), sgID)
 	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
@@ -363,7 +363,7 @@ func (c *Client) DeleteSecurityGroup(ctx context.Context, sgID string) error {
 
 // ListSecurityGroupRules lists all security group rules.
 func (c *Client) ListSecurityGroupRules(ctx context.Context, opts *ListSecurityGroupRulesOptions) ([]SecurityGroupRule, error) {
-	url := c.NetworkingURL + "/security-group-rules"
+	url := c.networkingURL() + "/security-group-rules"
 	if opts != nil {
 		params := map[string]string{}
 		if opts.Limit > 0 {
@@ -390,7 +390,7 @@ func (c *Client) ListSecurityGroupRules(ctx context.Context, opts *ListSecurityG
 
 // GetSecurityGroupRule gets a security group rule's details.
 func (c *Client) GetSecurityGroupRule(ctx context.Context, ruleID string) (*SecurityGroupRule, error) {
-	url := fmt.Sprintf("%s/security-group-rules/%s", c.NetworkingURL, ruleID)
+	url := fmt.Sprintf("%s/security-group-rules/%s", c.networkingURL(), ruleID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -404,7 +404,7 @@ func (c *Client) GetSecurityGroupRule(ctx context.Context, ruleID string) (*Secu
 
 // CreateSecurityGroupRule creates a security group rule.
 func (c *Client) CreateSecurityGroupRule(ctx context.Context, opts CreateSecurityGroupRuleRequest) (*SecurityGroupRule, error) {
-	url := c.NetworkingURL + "/security-group-rules"
+	url := c.networkingURL() + "/security-group-rules"
 	body := map[string]interface{}{"security_group_rule": opts}
 	req, err := c.newRequest(ctx, http.MethodPost, url, body)
 	if err != nil {
@@ -419,7 +419,7 @@ func (c *Client) CreateSecurityGroupRule(ctx context.Context, opts CreateSecurit
 
 // DeleteSecurityGroupRule deletes a security group rule.
 func (c *Client) DeleteSecurityGroupRule(ctx context.Context, ruleID string) error {
-	url := fmt.Sprintf("%s/security-group-rules/%s", c.NetworkingURL, ruleID)
+	url := fmt.Sprintf("%s/security-group-rules/%s", c.networkingURL(), ruleID)
 	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
@@ -462,7 +462,7 @@ type networkResponse struct {
 
 // ListNetworks lists all networks.
 func (c *Client) ListNetworks(ctx context.Context, opts *ListNetworksOptions) ([]Network, error) {
-	url := c.NetworkingURL + "/networks"
+	url := c.networkingURL() + "/networks"
 	if opts != nil {
 		params := map[string]string{}
 		if opts.Limit > 0 {
@@ -486,7 +486,7 @@ func (c *Client) ListNetworks(ctx context.Context, opts *ListNetworksOptions) ([
 
 // GetNetwork gets a network's details.
 func (c *Client) GetNetwork(ctx context.Context, networkID string) (*Network, error) {
-	url := fmt.Sprintf("%s/networks/%s", c.NetworkingURL, networkID)
+	url := fmt.Sprintf("%s/networks/%s", c.networkingURL(), networkID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -500,7 +500,7 @@ func (c *Client) GetNetwork(ctx context.Context, networkID string) (*Network, er
 
 // CreateNetwork creates a local network.
 func (c *Client) CreateNetwork(ctx context.Context) (*Network, error) {
-	url := c.NetworkingURL + "/networks"
+	url := c.networkingURL() + "/networks"
 	req, err := c.newRequest(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return nil, err
@@ -514,7 +514,7 @@ func (c *Client) CreateNetwork(ctx context.Context) (*Network, error) {
 
 // DeleteNetwork deletes a local network.
 func (c *Client) DeleteNetwork(ctx context.Context, networkID string) error {
-	url := fmt.Sprintf("%s/networks/%s", c.NetworkingURL, networkID)
+	url := fmt.Sprintf("%s/networks/%s", c.networkingURL(), networkID)
 	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
@@ -592,7 +592,7 @@ type portResponse struct {
 
 // ListPorts lists all ports.
 func (c *Client) ListPorts(ctx context.Context, opts *ListPortsOptions) ([]Port, error) {
-	url := c.NetworkingURL + "/ports"
+	url := c.networkingURL() + "/ports"
 	if opts != nil {
 		params := map[string]string{}
 		if opts.Limit > 0 {
@@ -622,7 +622,7 @@ func (c *Client) ListPorts(ctx context.Context, opts *ListPortsOptions) ([]Port,
 
 // GetPort gets a port's details.
 func (c *Client) GetPort(ctx context.Context, portID string) (*Port, error) {
-	url := fmt.Sprintf("%s/ports/%s", c.NetworkingURL, portID)
+	url := fmt.Sprintf("%s/ports/%s", c.networkingURL(), portID)
 	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -636,7 +636,7 @@ func (c *Client) GetPort(ctx context.Context, portID string) (*Port, error) {
 
 // CreatePort creates a port on a local network.
 func (c *Client) CreatePort(ctx context.Context, opts CreatePortRequest) (*Port, error) {
-	url := c.NetworkingURL + "/ports"
+	url := c.networkingURL() + "/ports"
 	body := map[string]interface{}{"port": opts}
 	req, err := c.newRequest(ctx, http.MethodPost, url, body)
 	if err != nil {
@@ -651,7 +651,7 @@ func (c *Client) CreatePort(ctx context.Context, opts CreatePortRequest) (*Port,
 
 // AllocateAdditionalIP allocates additional public IP addresses.
 func (c *Client) AllocateAdditionalIP(ctx context.Context, count int, securityGroups []string) (*Port, error) {
-	url := c.NetworkingURL + "/allocateips"
+	url := c.networkingURL() + "/allocateips"
 	body := map[string]interface{}{
 		"allocateip": AllocateIPRequest{
 			Count:          count,
@@ -671,7 +671,7 @@ func (c *Client) AllocateAdditionalIP(ctx context.Context, count int, securityGr
 
 // UpdatePort updates a port.
 func (c *Client) UpdatePort(ctx context.Context, portID string, opts UpdatePortRequest) (*Port, error) {
-	url := fmt.Sprintf("%s/ports/%s", c.NetworkingURL, portID)
+	url := fmt.Sprintf("%s/ports/%s", c.networkingURL(), portID)
 	body := map[string]interface{}{"port": opts}
 	req, err := c.newRequest(ctx, http.MethodPut, url, body)
 	if err != nil {
@@ -686,7 +686,7 @@ func (c *Client) UpdatePort(ctx context.Context, portID string, opts UpdatePortR
 
 // DeletePort deletes a port.
 func (c *Client) DeletePort(ctx context.Context, portID string) error {
-	url := fmt.Sprintf("%s/ports/%s", c.NetworkingURL, portID)
+	url := fmt.Sprintf("%s/ports/%s", c.networkingURL(), portID)
 	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
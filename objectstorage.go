@@ -60,6 +60,9 @@ type Object struct {
 	Bytes        int64  `json:"bytes"`
 	ContentType  string `json:"content_type"`
 	LastModified string `json:"last_modified"`
+	// Subdir is set, instead of the other fields, for the pseudo-directory
+	// entries of a listing with ListObjectsOptions.Delimiter.
+	Subdir string `json:"subdir,omitempty"`
 }
 
 // SLOSegment represents a segment for Static Large Object upload.
@@ -80,7 +83,7 @@ type ListObjectsOptions struct {
 }
 
 func (c *Client) objectStoragePath(parts ...string) string {
-	path := fmt.Sprintf("%s/AUTH_%s", c.ObjectStorageURL, c.tenantID())
+	path := fmt.Sprintf("%s/AUTH_%s", c.objectStorageURL(), c.tenantID())
 	for _, p := range parts {
 		// Encode each segment but preserve "/" within object names.
 		encoded := url.PathEscape(p)
@@ -97,21 +100,20 @@ func (c *Client) objectStoragePath(parts ...string) string {
 // GetAccountInfo gets object storage account information.
 func (c *Client) GetAccountInfo(ctx context.Context) (*AccountInfo, error) {
 	url := c.objectStoragePath()
-	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodHead, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return nil, &APIError{StatusCode: resp.StatusCode, Status: resp.Status}
+		return nil, newResponseError(resp, nil)
 	}
 
 	info := &AccountInfo{}
@@ -127,15 +129,14 @@ func (c *Client) GetAccountInfo(ctx context.Context) (*AccountInfo, error) {
 // Must be in 100GB increments (e.g., 100, 200, 300...).
 func (c *Client) SetAccountQuota(ctx context.Context, gigaBytes string) error {
 	url := c.objectStoragePath()
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("X-Account-Meta-Quota-Giga-Bytes", gigaBytes)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -143,7 +144,7 @@ func (c *Client) SetAccountQuota(ctx context.Context, gigaBytes string) error {
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -169,14 +170,13 @@ func (c *Client) ListContainers(ctx context.Context) ([]Container, error) {
 // CreateContainer creates a container.
 func (c *Client) CreateContainer(ctx context.Context, name string) error {
 	url := c.objectStoragePath(name)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodPut, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -184,7 +184,7 @@ func (c *Client) CreateContainer(ctx context.Context, name string) error {
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -192,14 +192,13 @@ func (c *Client) CreateContainer(ctx context.Context, name string) error {
 // DeleteContainer deletes an empty container.
 func (c *Client) DeleteContainer(ctx context.Context, name string) error {
 	url := c.objectStoragePath(name)
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -207,7 +206,7 @@ func (c *Client) DeleteContainer(ctx context.Context, name string) error {
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -215,14 +214,13 @@ func (c *Client) DeleteContainer(ctx context.Context, name string) error {
 // GetContainerInfo gets container details from response headers.
 func (c *Client) GetContainerInfo(ctx context.Context, name string) (*ContainerInfo, error) {
 	url := c.objectStoragePath(name)
-	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodHead, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -230,7 +228,7 @@ func (c *Client) GetContainerInfo(ctx context.Context, name string) (*ContainerI
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, newAPIError(resp.StatusCode, resp.Status, string(body))
+		return nil, newResponseError(resp, body)
 	}
 
 	info := &ContainerInfo{
@@ -288,13 +286,12 @@ func (c *Client) ListObjects(ctx context.Context, container string, opts *ListOb
 // UploadObject uploads an object to a container.
 func (c *Client) UploadObject(ctx context.Context, container, objectName string, data io.Reader) error {
 	url := c.objectStoragePath(container, objectName)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, data)
+	req, err := c.newRawRequest(ctx, http.MethodPut, url, data)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -302,7 +299,7 @@ func (c *Client) UploadObject(ctx context.Context, container, objectName string,
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -310,13 +307,12 @@ func (c *Client) UploadObject(ctx context.Context, container, objectName string,
 // DownloadObject downloads an object from a container.
 func (c *Client) DownloadObject(ctx context.Context, container, objectName string) (io.ReadCloser, error) {
 	url := c.objectStoragePath(container, objectName)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -324,7 +320,7 @@ func (c *Client) DownloadObject(ctx context.Context, container, objectName strin
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, newAPIError(resp.StatusCode, resp.Status, string(body))
+		return nil, newResponseError(resp, body)
 	}
 	return resp.Body, nil
 }
@@ -332,13 +328,12 @@ func (c *Client) DownloadObject(ctx context.Context, container, objectName strin
 // DeleteObject deletes an object from a container.
 func (c *Client) DeleteObject(ctx context.Context, container, objectName string) error {
 	url := c.objectStoragePath(container, objectName)
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -346,7 +341,7 @@ func (c *Client) DeleteObject(ctx context.Context, container, objectName string)
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -354,14 +349,13 @@ func (c *Client) DeleteObject(ctx context.Context, container, objectName string)
 // GetObjectInfo gets object details from response headers.
 func (c *Client) GetObjectInfo(ctx context.Context, container, objectName string) (*ObjectInfo, error) {
 	url := c.objectStoragePath(container, objectName)
-	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodHead, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -369,7 +363,7 @@ func (c *Client) GetObjectInfo(ctx context.Context, container, objectName string
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, newAPIError(resp.StatusCode, resp.Status, string(body))
+		return nil, newResponseError(resp, body)
 	}
 
 	info := &ObjectInfo{
@@ -387,14 +381,13 @@ func (c *Client) GetObjectInfo(ctx context.Context, container, objectName string
 // CopyObject copies an object to another container/name.
 func (c *Client) CopyObject(ctx context.Context, srcContainer, srcObject, dstContainer, dstObject string) error {
 	url := c.objectStoragePath(srcContainer, srcObject)
-	req, err := http.NewRequestWithContext(ctx, "COPY", url, nil)
+	req, err := c.newRawRequest(ctx, "COPY", url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("Destination", fmt.Sprintf("%s/%s", dstContainer, dstObject))
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -402,7 +395,7 @@ func (c *Client) CopyObject(ctx context.Context, srcContainer, srcObject, dstCon
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -410,14 +403,13 @@ func (c *Client) CopyObject(ctx context.Context, srcContainer, srcObject, dstCon
 // ScheduleObjectDeletion schedules an object for deletion at a specific Unix timestamp.
 func (c *Client) ScheduleObjectDeletion(ctx context.Context, container, objectName string, deleteAt int64) error {
 	url := c.objectStoragePath(container, objectName)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("X-Delete-At", fmt.Sprintf("%d", deleteAt))
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -425,7 +417,7 @@ func (c *Client) ScheduleObjectDeletion(ctx context.Context, container, objectNa
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -433,14 +425,13 @@ func (c *Client) ScheduleObjectDeletion(ctx context.Context, container, objectNa
 // ScheduleObjectDeletionAfter schedules an object for deletion after a duration in seconds.
 func (c *Client) ScheduleObjectDeletionAfter(ctx context.Context, container, objectName string, deleteAfterSeconds int64) error {
 	url := c.objectStoragePath(container, objectName)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("X-Delete-After", fmt.Sprintf("%d", deleteAfterSeconds))
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -448,7 +439,7 @@ func (c *Client) ScheduleObjectDeletionAfter(ctx context.Context, container, obj
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -460,14 +451,13 @@ func (c *Client) ScheduleObjectDeletionAfter(ctx context.Context, container, obj
 // EnableVersioning enables object versioning on a container.
 func (c *Client) EnableVersioning(ctx context.Context, container, versionsContainer string) error {
 	url := c.objectStoragePath(container)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("X-Versions-Location", versionsContainer)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -475,7 +465,7 @@ func (c *Client) EnableVersioning(ctx context.Context, container, versionsContai
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -483,14 +473,13 @@ func (c *Client) EnableVersioning(ctx context.Context, container, versionsContai
 // DisableVersioning disables object versioning on a container.
 func (c *Client) DisableVersioning(ctx context.Context, container string) error {
 	url := c.objectStoragePath(container)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("X-Remove-Versions-Location", "")
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -498,7 +487,7 @@ func (c *Client) DisableVersioning(ctx context.Context, container string) error
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -506,14 +495,13 @@ func (c *Client) DisableVersioning(ctx context.Context, container string) error
 // EnableWebPublishing makes a container publicly accessible.
 func (c *Client) EnableWebPublishing(ctx context.Context, container string) error {
 	url := c.objectStoragePath(container)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("X-Container-Read", ".r:*")
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -521,7 +509,7 @@ func (c *Client) EnableWebPublishing(ctx context.Context, container string) erro
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -529,14 +517,13 @@ func (c *Client) EnableWebPublishing(ctx context.Context, container string) erro
 // DisableWebPublishing disables public access on a container.
 func (c *Client) DisableWebPublishing(ctx context.Context, container string) error {
 	url := c.objectStoragePath(container)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("X-Container-Read", "")
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -544,7 +531,7 @@ func (c *Client) DisableWebPublishing(ctx context.Context, container string) err
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -552,14 +539,13 @@ func (c *Client) DisableWebPublishing(ctx context.Context, container string) err
 // SetTempURLKey registers a key for temporary URL generation.
 func (c *Client) SetTempURLKey(ctx context.Context, key string) error {
 	url := c.objectStoragePath()
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("X-Account-Meta-Temp-URL-Key", key)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -567,7 +553,7 @@ func (c *Client) SetTempURLKey(ctx context.Context, key string) error {
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -575,14 +561,13 @@ func (c *Client) SetTempURLKey(ctx context.Context, key string) error {
 // RemoveTempURLKey removes the temporary URL key from the account metadata.
 func (c *Client) RemoveTempURLKey(ctx context.Context) error {
 	url := c.objectStoragePath()
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("X-Remove-Account-Meta-Temp-URL-Key", "")
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -590,7 +575,7 @@ func (c *Client) RemoveTempURLKey(ctx context.Context) error {
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -640,15 +625,14 @@ func (c *Client) GenerateTempURL(method, container, objectName, key string, expi
 // CreateDLOManifest creates a Dynamic Large Object manifest.
 func (c *Client) CreateDLOManifest(ctx context.Context, container, manifestName, segmentContainer, segmentPrefix string) error {
 	url := c.objectStoragePath(container, manifestName)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, nil)
+	req, err := c.newRawRequest(ctx, http.MethodPut, url, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-Auth-Token", c.Token)
 	req.Header.Set("X-Object-Manifest", fmt.Sprintf("%s/%s", segmentContainer, segmentPrefix))
 	req.ContentLength = 0
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -656,7 +640,7 @@ func (c *Client) CreateDLOManifest(ctx context.Context, container, manifestName,
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
@@ -670,7 +654,7 @@ func (c *Client) CreateSLOManifest(ctx context.Context, container, manifestName
 		return err
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -678,7 +662,7 @@ func (c *Client) CreateSLOManifest(ctx context.Context, container, manifestName
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return newAPIError(resp.StatusCode, resp.Status, string(body))
+		return newResponseError(resp, body)
 	}
 	return nil
 }
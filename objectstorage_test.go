@@ -688,3 +688,44 @@ func TestCreateSLOManifest_Success(t *testing.T) {
 		t.Errorf("URI should contain multipart-manifest=put: %q", capturedURI)
 	}
 }
+
+// ============================================================
+// Raw requests and token refresh
+// ============================================================
+
+func TestRawRequests_ReauthenticateOn401(t *testing.T) {
+	ts := &tokenRefreshServer{}
+	server, client := setupTestServer(ts.handler)
+	defer server.Close()
+
+	ctx := context.Background()
+	_, err := client.Authenticate(ctx, "user", "pass", "tenant")
+	assertNoError(t, err)
+
+	calls := map[string]func() error{
+		"UploadObject": func() error {
+			return client.UploadObject(ctx, "c", "o", strings.NewReader("data"))
+		},
+		"DeleteObject": func() error { return client.DeleteObject(ctx, "c", "o") },
+		"CreateDLOManifest": func() error {
+			return client.CreateDLOManifest(ctx, "c", "m", "segments", "m/")
+		},
+		"UploadISOImage": func() error {
+			return client.UploadISOImage(ctx, "image-1", strings.NewReader("iso"))
+		},
+	}
+	for name, call := range calls {
+		// Revoke the token behind the client's back.
+		ts.mu.Lock()
+		ts.issued++
+		want := fmt.Sprintf("token-%d", ts.issued+1)
+		ts.mu.Unlock()
+
+		if err := call(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if got := client.currentToken(); got != want {
+			t.Errorf("%s: token = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Package otelconoha traces ConoHa SDK operations with OpenTelemetry.
+//
+// It lives in its own module so that the conoha package does not depend
+// on OpenTelemetry.
+//
+// Example:
+//
+//	client := conoha.NewClient(otelconoha.WithTracing())
+//
+// Every SDK method then creates a client span such as
+// "conoha.compute.CreateServer" with the region, service type, resource ID
+// and HTTP status code as attributes. Wrap the HTTP client transport with
+// otelhttp to get child spans for the individual HTTP requests.
+package otelconoha
+
+import (
+	"context"
+	"net/http"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name of the spans.
+const ScopeName = "github.com/leonunix/conohav3-golang-sdk/otelconoha"
+
+// Span attribute keys.
+const (
+	RegionKey      = attribute.Key("conoha.region")
+	ServiceTypeKey = attribute.Key("conoha.service_type")
+	ResourceIDKey  = attribute.Key("conoha.resource_id")
+	OperationKey   = attribute.Key("conoha.operation")
+
+	HTTPMethodKey     = attribute.Key("http.request.method")
+	HTTPURLKey        = attribute.Key("url.full")
+	HTTPStatusCodeKey = attribute.Key("http.response.status_code")
+)
+
+// Option configures the tracer.
+type Option func(*config)
+
+type config struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider sets the tracer provider. The global provider is used
+// by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = tp
+	}
+}
+
+// NewTracer returns a conoha.Tracer backed by OpenTelemetry.
+func NewTracer(opts ...Option) conoha.Tracer {
+	cfg := config{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.provider == nil {
+		cfg.provider = otel.GetTracerProvider()
+	}
+	return &otelTracer{tracer: cfg.provider.Tracer(ScopeName)}
+}
+
+// WithTracing is a conoha.ClientOption that enables tracing with
+// NewTracer(opts...).
+func WithTracing(opts ...Option) conoha.ClientOption {
+	return conoha.WithTracer(NewTracer(opts...))
+}
+
+type otelTracer struct {
+	tracer trace.Tracer
+}
+
+func (t *otelTracer) Start(ctx context.Context, op conoha.Operation) (context.Context, conoha.Span) {
+	attrs := []attribute.KeyValue{
+		OperationKey.String(op.Name),
+		HTTPMethodKey.String(op.Method),
+		HTTPURLKey.String(op.URL),
+	}
+	if op.Region != "" {
+		attrs = append(attrs, RegionKey.String(op.Region))
+	}
+	if op.Service != "" {
+		attrs = append(attrs, ServiceTypeKey.String(op.Service))
+	}
+	if op.ResourceID != "" {
+		attrs = append(attrs, ResourceIDKey.String(op.ResourceID))
+	}
+	ctx, span := t.tracer.Start(ctx, op.String(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, &otelSpan{span: span}
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s *otelSpan) End(statusCode int, err error) {
+	if statusCode != 0 {
+		s.span.SetAttributes(HTTPStatusCodeKey.Int(statusCode))
+	}
+	switch {
+	case err != nil:
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	case statusCode >= 400:
+		s.span.SetStatus(codes.Error, http.StatusText(statusCode))
+	}
+	s.span.End()
+}
@@ -0,0 +1,83 @@
+package otelconoha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	conoha "github.com/leonunix/conohav3-golang-sdk"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const serverID = "4e6c1a2b-3d4e-4f5a-8b9c-0d1e2f3a4b5c"
+
+func setup(t *testing.T, status int) (*conoha.Client, *tracetest.SpanRecorder) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"server":{"id":"` + serverID + `"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := conoha.NewClient(
+		conoha.WithRegion("c3j1"),
+		conoha.WithComputeURL(server.URL+"/compute"),
+		WithTracing(WithTracerProvider(provider)),
+	)
+	client.Token = "token"
+	return client, recorder
+}
+
+func TestTracing_CreatesClientSpan(t *testing.T) {
+	client, recorder := setup(t, http.StatusOK)
+
+	if _, err := client.GetServer(context.Background(), serverID); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	s := spans[0]
+	if s.Name() != "conoha.compute.GetServer" {
+		t.Errorf("span name = %q", s.Name())
+	}
+	if s.SpanKind() != trace.SpanKindClient {
+		t.Errorf("span kind = %v", s.SpanKind())
+	}
+	want := map[string]interface{}{
+		string(RegionKey):         "c3j1",
+		string(ServiceTypeKey):    "compute",
+		string(ResourceIDKey):     serverID,
+		string(HTTPStatusCodeKey): int64(200),
+	}
+	got := map[string]interface{}{}
+	for _, kv := range s.Attributes() {
+		got[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("attribute %s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestTracing_ErrorStatus(t *testing.T) {
+	client, recorder := setup(t, http.StatusNotFound)
+
+	if _, err := client.GetServer(context.Background(), serverID); err == nil {
+		t.Fatal("expected error")
+	}
+
+	s := recorder.Ended()[0]
+	if s.Status().Code != codes.Error {
+		t.Errorf("status = %v, want Error", s.Status())
+	}
+}
@@ -0,0 +1,365 @@
+package conoha
+
+import (
+	"context"
+	"errors"
+)
+
+// DefaultPageSize is the page size the ListAll* and Each* helpers request
+// when the options do not set a Limit.
+const DefaultPageSize = 100
+
+// ErrStopIteration can be returned by the callback of an Each* helper to
+// stop iterating early. The helper then returns nil.
+var ErrStopIteration = errors.New("conoha: stop iteration")
+
+// eachMarkerPage pages through a marker-based listing. fetch returns the
+// page that follows marker ("" for the first page), and key returns the
+// marker of an item. Iteration stops at the first empty page, when fn
+// returns an error, or when ctx is done.
+func eachMarkerPage[T any](ctx context.Context, marker string, fetch func(marker string) ([]T, error), key func(T) string, fn func(T) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := fetch(marker)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		for _, item := range page {
+			if err := fn(item); err != nil {
+				if errors.Is(err, ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+		next := key(page[len(page)-1])
+		if next == "" || next == marker {
+			// The service ignored the marker; stop instead of looping forever.
+			return nil
+		}
+		marker = next
+	}
+}
+
+// eachOffsetPage pages through an offset-based listing. fetch returns the
+// page that starts at offset. Iteration stops at the first empty page,
+// when fn returns an error, or when ctx is done.
+func eachOffsetPage[T any](ctx context.Context, offset int, fetch func(offset int) ([]T, error), fn func(T) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := fetch(offset)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		for _, item := range page {
+			if err := fn(item); err != nil {
+				if errors.Is(err, ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+		offset += len(page)
+	}
+}
+
+// collect runs each and returns every item it yields.
+func collect[T any](each func(fn func(T) error) error) ([]T, error) {
+	var all []T
+	err := each(func(item T) error {
+		all = append(all, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+func pageSize(limit int) int {
+	if limit > 0 {
+		return limit
+	}
+	return DefaultPageSize
+}
+
+// ------------------------------------------------------------
+// Compute
+// ------------------------------------------------------------
+
+// EachServer calls fn for every server matching opts, fetching further
+// pages as needed. opts.Limit sets the page size and opts.Marker the
+// starting point.
+func (c *Client) EachServer(ctx context.Context, opts *ListServersOptions, fn func(Server) error) error {
+	o := ListServersOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Limit = pageSize(o.Limit)
+	return eachMarkerPage(ctx, o.Marker, func(marker string) ([]Server, error) {
+		o.Marker = marker
+		return c.ListServers(ctx, &o)
+	}, func(s Server) string { return s.ID }, fn)
+}
+
+// ListAllServers lists every server matching opts, across all pages.
+func (c *Client) ListAllServers(ctx context.Context, opts *ListServersOptions) ([]Server, error) {
+	return collect(func(fn func(Server) error) error { return c.EachServer(ctx, opts, fn) })
+}
+
+// EachServerDetail is like EachServer but yields servers with full details.
+func (c *Client) EachServerDetail(ctx context.Context, opts *ListServersOptions, fn func(ServerDetail) error) error {
+	o := ListServersOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Limit = pageSize(o.Limit)
+	return eachMarkerPage(ctx, o.Marker, func(marker string) ([]ServerDetail, error) {
+		o.Marker = marker
+		return c.ListServersDetail(ctx, &o)
+	}, func(s ServerDetail) string { return s.ID }, fn)
+}
+
+// ListAllServersDetail lists every server matching opts with full details,
+// across all pages.
+func (c *Client) ListAllServersDetail(ctx context.Context, opts *ListServersOptions) ([]ServerDetail, error) {
+	return collect(func(fn func(ServerDetail) error) error { return c.EachServerDetail(ctx, opts, fn) })
+}
+
+// EachKeypair calls fn for every SSH keypair, fetching further pages as
+// needed.
+func (c *Client) EachKeypair(ctx context.Context, opts *ListKeypairsOptions, fn func(Keypair) error) error {
+	o := ListKeypairsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Limit = pageSize(o.Limit)
+	return eachMarkerPage(ctx, o.Marker, func(marker string) ([]Keypair, error) {
+		o.Marker = marker
+		return c.ListKeypairs(ctx, &o)
+	}, func(k Keypair) string { return k.Name }, fn)
+}
+
+// ListAllKeypairs lists every SSH keypair, across all pages.
+func (c *Client) ListAllKeypairs(ctx context.Context, opts *ListKeypairsOptions) ([]Keypair, error) {
+	return collect(func(fn func(Keypair) error) error { return c.EachKeypair(ctx, opts, fn) })
+}
+
+// ------------------------------------------------------------
+// Image
+// ------------------------------------------------------------
+
+// EachImage calls fn for every image matching opts, fetching further
+// pages as needed.
+func (c *Client) EachImage(ctx context.Context, opts *ListImagesOptions, fn func(Image) error) error {
+	o := ListImagesOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Limit = pageSize(o.Limit)
+	return eachMarkerPage(ctx, o.Marker, func(marker string) ([]Image, error) {
+		o.Marker = marker
+		return c.ListImages(ctx, &o)
+	}, func(img Image) string { return img.ID }, fn)
+}
+
+// ListAllImages lists every image matching opts, across all pages.
+func (c *Client) ListAllImages(ctx context.Context, opts *ListImagesOptions) ([]Image, error) {
+	return collect(func(fn func(Image) error) error { return c.EachImage(ctx, opts, fn) })
+}
+
+// ------------------------------------------------------------
+// Network
+// ------------------------------------------------------------
+
+// EachPort calls fn for every port matching opts, fetching further pages
+// as needed.
+func (c *Client) EachPort(ctx context.Context, opts *ListPortsOptions, fn func(Port) error) error {
+	o := ListPortsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Limit = pageSize(o.Limit)
+	return eachMarkerPage(ctx, o.Marker, func(marker string) ([]Port, error) {
+		o.Marker = marker
+		return c.ListPorts(ctx, &o)
+	}, func(p Port) string { return p.ID }, fn)
+}
+
+// ListAllPorts lists every port matching opts, across all pages.
+func (c *Client) ListAllPorts(ctx context.Context, opts *ListPortsOptions) ([]Port, error) {
+	return collect(func(fn func(Port) error) error { return c.EachPort(ctx, opts, fn) })
+}
+
+// EachSecurityGroup calls fn for every security group, fetching further
+// pages as needed.
+func (c *Client) EachSecurityGroup(ctx context.Context, opts *ListSecurityGroupsOptions, fn func(SecurityGroup) error) error {
+	o := ListSecurityGroupsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Limit = pageSize(o.Limit)
+	return eachMarkerPage(ctx, o.Marker, func(marker string) ([]SecurityGroup, error) {
+		o.Marker = marker
+		return c.ListSecurityGroups(ctx, &o)
+	}, func(sg SecurityGroup) string { return sg.ID }, fn)
+}
+
+// ListAllSecurityGroups lists every security group, across all pages.
+func (c *Client) ListAllSecurityGroups(ctx context.Context, opts *ListSecurityGroupsOptions) ([]SecurityGroup, error) {
+	return collect(func(fn func(SecurityGroup) error) error { return c.EachSecurityGroup(ctx, opts, fn) })
+}
+
+// EachNetwork calls fn for every network, fetching further pages as
+// needed.
+func (c *Client) EachNetwork(ctx context.Context, opts *ListNetworksOptions, fn func(Network) error) error {
+	o := ListNetworksOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Limit = pageSize(o.Limit)
+	return eachMarkerPage(ctx, o.Marker, func(marker string) ([]Network, error) {
+		o.Marker = marker
+		return c.ListNetworks(ctx, &o)
+	}, func(n Network) string { return n.ID }, fn)
+}
+
+// ListAllNetworks lists every network, across all pages.
+func (c *Client) ListAllNetworks(ctx context.Context, opts *ListNetworksOptions) ([]Network, error) {
+	return collect(func(fn func(Network) error) error { return c.EachNetwork(ctx, opts, fn) })
+}
+
+// ------------------------------------------------------------
+// Block Storage
+// ------------------------------------------------------------
+
+// EachVolume calls fn for every volume, fetching further pages as needed.
+// Pages are requested by marker; opts.Offset is ignored.
+func (c *Client) EachVolume(ctx context.Context, opts *ListVolumesOptions, fn func(Volume) error) error {
+	o := ListVolumesOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Limit = pageSize(o.Limit)
+	o.Offset = 0
+	return eachMarkerPage(ctx, o.Marker, func(marker string) ([]Volume, error) {
+		o.Marker = marker
+		return c.ListVolumes(ctx, &o)
+	}, func(v Volume) string { return v.ID }, fn)
+}
+
+// ListAllVolumes lists every volume, across all pages.
+func (c *Client) ListAllVolumes(ctx context.Context, opts *ListVolumesOptions) ([]Volume, error) {
+	return collect(func(fn func(Volume) error) error { return c.EachVolume(ctx, opts, fn) })
+}
+
+// EachBackup calls fn for every backup, fetching further pages by offset.
+func (c *Client) EachBackup(ctx context.Context, opts *ListBackupsOptions, fn func(Backup) error) error {
+	o := ListBackupsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Limit = pageSize(o.Limit)
+	return eachOffsetPage(ctx, o.Offset, func(offset int) ([]Backup, error) {
+		o.Offset = offset
+		return c.ListBackups(ctx, &o)
+	}, fn)
+}
+
+// ListAllBackups lists every backup, across all pages.
+func (c *Client) ListAllBackups(ctx context.Context, opts *ListBackupsOptions) ([]Backup, error) {
+	return collect(func(fn func(Backup) error) error { return c.EachBackup(ctx, opts, fn) })
+}
+
+// ------------------------------------------------------------
+// DNS
+// ------------------------------------------------------------
+
+// EachDomain calls fn for every DNS domain, fetching further pages by
+// offset.
+func (c *Client) EachDomain(ctx context.Context, opts *ListDomainsOptions, fn func(Domain) error) error {
+	o := ListDomainsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Limit = pageSize(o.Limit)
+	return eachOffsetPage(ctx, o.Offset, func(offset int) ([]Domain, error) {
+		o.Offset = offset
+		return c.ListDomains(ctx, &o)
+	}, fn)
+}
+
+// ListAllDomains lists every DNS domain, across all pages.
+func (c *Client) ListAllDomains(ctx context.Context, opts *ListDomainsOptions) ([]Domain, error) {
+	return collect(func(fn func(Domain) error) error { return c.EachDomain(ctx, opts, fn) })
+}
+
+// EachDNSRecord calls fn for every record of a domain, fetching further
+// pages by offset.
+func (c *Client) EachDNSRecord(ctx context.Context, domainID string, opts *ListDNSRecordsOptions, fn func(DNSRecord) error) error {
+	o := ListDNSRecordsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Limit = pageSize(o.Limit)
+	return eachOffsetPage(ctx, o.Offset, func(offset int) ([]DNSRecord, error) {
+		o.Offset = offset
+		return c.ListDNSRecords(ctx, domainID, &o)
+	}, fn)
+}
+
+// ListAllDNSRecords lists every record of a domain, across all pages.
+func (c *Client) ListAllDNSRecords(ctx context.Context, domainID string, opts *ListDNSRecordsOptions) ([]DNSRecord, error) {
+	return collect(func(fn func(DNSRecord) error) error { return c.EachDNSRecord(ctx, domainID, opts, fn) })
+}
+
+// ------------------------------------------------------------
+// Object Storage
+// ------------------------------------------------------------
+
+// EachObject calls fn for every object in a container matching opts,
+// fetching further pages as needed. With opts.Delimiter, fn is also called
+// for each pseudo-directory, as an Object with only Subdir set.
+func (c *Client) EachObject(ctx context.Context, container string, opts *ListObjectsOptions, fn func(Object) error) error {
+	o := ListObjectsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Limit = pageSize(o.Limit)
+	return eachMarkerPage(ctx, o.Marker, func(marker string) ([]Object, error) {
+		o.Marker = marker
+		return c.ListObjects(ctx, container, &o)
+	}, func(obj Object) string { return objectMarker(obj, o.Reverse) }, fn)
+}
+
+// objectMarker returns the marker that continues a listing after obj. A
+// pseudo-directory is skipped as a whole: its last byte, the delimiter, is
+// incremented so that the next page starts after every name it groups.
+func objectMarker(obj Object, reverse bool) string {
+	if obj.Subdir == "" {
+		return obj.Name
+	}
+	marker := []byte(obj.Subdir)
+	last := len(marker) - 1
+	if reverse || marker[last] == 0xff {
+		return obj.Subdir
+	}
+	marker[last]++
+	return string(marker)
+}
+
+// ListAllObjects lists every object in a container matching opts, across
+// all pages.
+func (c *Client) ListAllObjects(ctx context.Context, container string, opts *ListObjectsOptions) ([]Object, error) {
+	return collect(func(fn func(Object) error) error { return c.EachObject(ctx, container, opts, fn) })
+}
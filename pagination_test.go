@@ -0,0 +1,233 @@
+package conoha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// pagedIDs serves ids in pages using the limit and marker query parameters.
+func pagedIDs(r *http.Request, ids []string) []string {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	start := 0
+	if marker := r.URL.Query().Get("marker"); marker != "" {
+		for i, id := range ids {
+			if id == marker {
+				start = i + 1
+			}
+		}
+	}
+	end := start + limit
+	if limit == 0 || end > len(ids) {
+		end = len(ids)
+	}
+	return ids[start:end]
+}
+
+func serverIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("s%02d", i)
+	}
+	return ids
+}
+
+func TestListAllServers_FollowsMarkers(t *testing.T) {
+	ids := serverIDs(7)
+	var requests int
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("status") != "ACTIVE" {
+			t.Errorf("status filter lost: %s", r.URL.RawQuery)
+		}
+		var servers []Server
+		for _, id := range pagedIDs(r, ids) {
+			servers = append(servers, Server{ID: id})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"servers": servers})
+	})
+	defer server.Close()
+
+	opts := &ListServersOptions{Limit: 3, Status: "ACTIVE"}
+	servers, err := client.ListAllServers(context.Background(), opts)
+	assertNoError(t, err)
+
+	if len(servers) != 7 || servers[0].ID != "s00" || servers[6].ID != "s06" {
+		t.Errorf("servers = %v", servers)
+	}
+	// Pages of 3, 3, 1 and a final empty page.
+	if requests != 4 {
+		t.Errorf("requests = %d, want 4", requests)
+	}
+	if opts.Marker != "" {
+		t.Errorf("caller's options were modified: %+v", opts)
+	}
+}
+
+func TestEachServer_DefaultPageSize(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("limit"); got != strconv.Itoa(DefaultPageSize) {
+			t.Errorf("limit = %q, want %d", got, DefaultPageSize)
+		}
+		w.Write([]byte(`{"servers":[]}`))
+	})
+	defer server.Close()
+
+	err := client.EachServer(context.Background(), nil, func(Server) error { return nil })
+	assertNoError(t, err)
+}
+
+func TestEachServer_StopIteration(t *testing.T) {
+	ids := serverIDs(10)
+	var requests int
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var servers []Server
+		for _, id := range pagedIDs(r, ids) {
+			servers = append(servers, Server{ID: id})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"servers": servers})
+	})
+	defer server.Close()
+
+	var seen []string
+	err := client.EachServer(context.Background(), &ListServersOptions{Limit: 2}, func(s Server) error {
+		seen = append(seen, s.ID)
+		if s.ID == "s02" {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	assertNoError(t, err)
+	if len(seen) != 3 || requests != 2 {
+		t.Errorf("seen = %v after %d requests", seen, requests)
+	}
+
+	boom := errors.New("boom")
+	err = client.EachServer(context.Background(), nil, func(Server) error { return boom })
+	if !errors.Is(err, boom) {
+		t.Errorf("err = %v, want boom", err)
+	}
+}
+
+func TestEachServer_StopsOnContextCancel(t *testing.T) {
+	ids := serverIDs(10)
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var servers []Server
+		for _, id := range pagedIDs(r, ids) {
+			servers = append(servers, Server{ID: id})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"servers": servers})
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var seen int
+	err := client.EachServer(ctx, &ListServersOptions{Limit: 2}, func(Server) error {
+		seen++
+		if seen == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if seen != 2 {
+		t.Errorf("seen = %d, want 2", seen)
+	}
+}
+
+func TestEachServer_StopsWhenMarkerIsIgnored(t *testing.T) {
+	var requests int
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"servers":[{"id":"s1"},{"id":"s2"}]}`))
+	})
+	defer server.Close()
+
+	servers, err := client.ListAllServers(context.Background(), &ListServersOptions{Limit: 2})
+	assertNoError(t, err)
+	if requests != 2 || len(servers) != 4 {
+		t.Errorf("requests = %d, servers = %d", requests, len(servers))
+	}
+}
+
+func TestListAllDNSRecords_FollowsOffsets(t *testing.T) {
+	var offsets []string
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/domains/d1/records" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var records []DNSRecord
+		for i := offset; i < 5 && i < offset+2; i++ {
+			records = append(records, DNSRecord{UUID: fmt.Sprintf("r%d", i)})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"records": records})
+	})
+	defer server.Close()
+
+	records, err := client.ListAllDNSRecords(context.Background(), "d1", &ListDNSRecordsOptions{Limit: 2})
+	assertNoError(t, err)
+
+	if len(records) != 5 || records[4].UUID != "r4" {
+		t.Errorf("records = %v", records)
+	}
+	if fmt.Sprint(offsets) != "[ 2 4 5]" {
+		t.Errorf("offsets = %q", offsets)
+	}
+}
+
+func TestListAllObjects_UsesNameAsMarker(t *testing.T) {
+	names := []string{"a.txt", "b.txt", "c.txt"}
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var objects []Object
+		for _, name := range pagedIDs(r, names) {
+			objects = append(objects, Object{Name: name})
+		}
+		json.NewEncoder(w).Encode(objects)
+	})
+	defer server.Close()
+
+	objects, err := client.ListAllObjects(context.Background(), "bucket", &ListObjectsOptions{Limit: 2})
+	assertNoError(t, err)
+	if len(objects) != 3 || objects[2].Name != "c.txt" {
+		t.Errorf("objects = %v", objects)
+	}
+}
+
+func TestListAllObjects_PageEndingWithSubdir(t *testing.T) {
+	var markers []string
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("delimiter") != "/" {
+			t.Errorf("delimiter lost: %s", r.URL.RawQuery)
+		}
+		marker := r.URL.Query().Get("marker")
+		markers = append(markers, marker)
+		switch marker {
+		case "":
+			w.Write([]byte(`[{"name":"a.txt"},{"subdir":"logs/"}]`))
+		case "logs0":
+			w.Write([]byte(`[{"name":"z.txt"}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	})
+	defer server.Close()
+
+	objects, err := client.ListAllObjects(context.Background(), "bucket", &ListObjectsOptions{Limit: 2, Delimiter: "/"})
+	assertNoError(t, err)
+	if len(objects) != 3 || objects[1].Subdir != "logs/" || objects[2].Name != "z.txt" {
+		t.Errorf("objects = %+v", objects)
+	}
+	if len(markers) != 3 || markers[1] != "logs0" {
+		t.Errorf("markers = %q, want the page after logs/ to start at logs0", markers)
+	}
+}
@@ -0,0 +1,173 @@
+package conoha
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// RateLimit configures client-side throttling of API requests.
+//
+// Requests are throttled before they are sent, so batch jobs that create
+// many resources in parallel stay below the API's own limits instead of
+// receiving 429 Too Many Requests. Waiting callers give up when their
+// context is done.
+type RateLimit struct {
+	// Default applies to every service that has no entry in Services.
+	// Each service gets its own bucket. The zero value means unlimited.
+	Default RateLimitRule
+
+	// Services overrides Default for individual services, keyed by the
+	// ServiceType* constants (e.g. ServiceTypeDNS).
+	Services map[string]RateLimitRule
+
+	// MaxInFlight caps the number of requests in flight across all
+	// services. A response counts as in flight until its body is closed.
+	// Zero means unlimited.
+	MaxInFlight int
+}
+
+// RateLimitRule is a token bucket that allows Rate requests per second on
+// average, with bursts of up to Burst requests. A Rate of zero or less
+// means unlimited; Burst values below 1 are treated as 1.
+type RateLimitRule struct {
+	Rate  float64
+	Burst int
+}
+
+// WithRateLimit throttles requests made by the client.
+//
+// Example:
+//
+//	client := conoha.NewClient(conoha.WithRateLimit(conoha.RateLimit{
+//	    Default: conoha.RateLimitRule{Rate: 10, Burst: 20},
+//	    Services: map[string]conoha.RateLimitRule{
+//	        conoha.ServiceTypeDNS: {Rate: 2, Burst: 5},
+//	    },
+//	    MaxInFlight: 16,
+//	}))
+func WithRateLimit(rl RateLimit) ClientOption {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(rl)
+	}
+}
+
+// rateLimiter applies a RateLimit. A nil *rateLimiter never blocks.
+type rateLimiter struct {
+	config RateLimit
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket // nil entries mean unlimited
+
+	inFlight chan struct{} // nil means unlimited
+}
+
+func newRateLimiter(rl RateLimit) *rateLimiter {
+	l := &rateLimiter{
+		config:  rl,
+		buckets: make(map[string]*tokenBucket),
+	}
+	if rl.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, rl.MaxInFlight)
+	}
+	return l
+}
+
+// acquire blocks until a request to service may be sent. The returned
+// function releases the in-flight slot and must be called exactly once.
+func (l *rateLimiter) acquire(ctx context.Context, service string) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	if b := l.bucket(service); b != nil {
+		if err := b.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if l.inFlight == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.inFlight <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-l.inFlight }) }, nil
+}
+
+// bucket returns the token bucket for service, creating it on first use.
+func (l *rateLimiter) bucket(service string) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[service]; ok {
+		return b
+	}
+	rule, ok := l.config.Services[service]
+	if !ok {
+		rule = l.config.Default
+	}
+	b := newTokenBucket(rule)
+	l.buckets[service] = b
+	return b
+}
+
+// tokenBucket is a minimal token bucket rate limiter.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64 // may go negative while callers hold reservations
+	last   time.Time
+}
+
+// newTokenBucket returns a full bucket for rule, or nil if rule is unlimited.
+func newTokenBucket(rule RateLimitRule) *tokenBucket {
+	if rule.Rate <= 0 {
+		return nil
+	}
+	burst := float64(rule.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rule.Rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait takes one token, sleeping until it becomes available or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if err := sleepContext(ctx, delay); err != nil {
+		// Hand the reserved token back for other callers.
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// releaseOnClose calls release when the response body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
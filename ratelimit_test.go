@@ -0,0 +1,164 @@
+package conoha
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimit_ThrottlesToRate(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(204)
+	})
+	defer server.Close()
+	WithRateLimit(RateLimit{Default: RateLimitRule{Rate: 20, Burst: 1}})(client)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		assertNoError(t, client.DeleteServer(context.Background(), "s1"))
+	}
+	// The first request uses the burst; the other four wait 50ms each.
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("5 requests at 20/s took %v, want at least 200ms", elapsed)
+	}
+}
+
+func TestRateLimit_SeparateBucketsPerService(t *testing.T) {
+	l := newRateLimiter(RateLimit{
+		Default: RateLimitRule{Rate: 1, Burst: 1},
+		Services: map[string]RateLimitRule{
+			ServiceTypeDNS: {Rate: 0.001, Burst: 1},
+		},
+	})
+	ctx := context.Background()
+
+	// Each service starts with a full bucket of its own.
+	for _, svc := range []string{ServiceTypeCompute, ServiceTypeDNS, ServiceTypeNetwork} {
+		start := time.Now()
+		release, err := l.acquire(ctx, svc)
+		assertNoError(t, err)
+		release()
+		if time.Since(start) > 50*time.Millisecond {
+			t.Errorf("%s: first request was throttled", svc)
+		}
+	}
+
+	// The DNS bucket is now empty and refills slowly.
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx, ServiceTypeDNS); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRateLimit_UnlimitedByDefault(t *testing.T) {
+	var l *rateLimiter
+	release, err := l.acquire(context.Background(), ServiceTypeCompute)
+	assertNoError(t, err)
+	release()
+
+	l = newRateLimiter(RateLimit{})
+	for i := 0; i < 100; i++ {
+		release, err := l.acquire(context.Background(), ServiceTypeCompute)
+		assertNoError(t, err)
+		release()
+	}
+}
+
+func TestRateLimit_MaxInFlight(t *testing.T) {
+	var current, peak int32
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+		w.Write([]byte(`{"server":{"id":"s1"}}`))
+	})
+	defer server.Close()
+	WithRateLimit(RateLimit{MaxInFlight: 2})(client)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetServer(context.Background(), "s1"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("peak in-flight = %d, want <= 2", peak)
+	}
+}
+
+func TestRateLimit_InFlightReleasedOnBodyClose(t *testing.T) {
+	l := newRateLimiter(RateLimit{MaxInFlight: 1})
+	ctx := context.Background()
+
+	release, err := l.acquire(ctx, ServiceTypeObjectStore)
+	assertNoError(t, err)
+	body := &releaseOnClose{ReadCloser: http.NoBody, release: release}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(waitCtx, ServiceTypeObjectStore); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("second acquire: err = %v, want context.DeadlineExceeded", err)
+	}
+
+	body.Close()
+	body.Close() // closing twice must not release twice
+	release, err = l.acquire(ctx, ServiceTypeObjectStore)
+	assertNoError(t, err)
+	release()
+}
+
+func TestRateLimit_StopsWhenContextCancelled(t *testing.T) {
+	var calls int32
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(204)
+	})
+	defer server.Close()
+	WithRateLimit(RateLimit{Default: RateLimitRule{Rate: 0.001, Burst: 1}})(client)
+
+	assertNoError(t, client.DeleteServer(context.Background(), "s1"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := client.DeleteServer(ctx, "s2")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestClient_ServiceForURL(t *testing.T) {
+	c := NewClient(WithRegion("c3j1"))
+
+	tests := map[string]string{
+		c.ComputeURL + "/servers/detail":              ServiceTypeCompute,
+		c.DNSServiceURL + "/domains":                  ServiceTypeDNS,
+		c.ObjectStorageURL + "/AUTH_t/bucket/key":     ServiceTypeObjectStore,
+		c.IdentityURL + "/auth/tokens":                ServiceTypeIdentity,
+		"https://example.com/somewhere/else/entirely": "",
+	}
+	for u, want := range tests {
+		if got := c.serviceForURL(u); got != want {
+			t.Errorf("serviceForURL(%q) = %q, want %q", u, got, want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package conoha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AmbiguousNameError is returned by the Resolve* methods when several
+// resources have the requested name. Use one of the IDs instead.
+type AmbiguousNameError struct {
+	Resource string // "server", "flavor", "image", "security group", "volume", "domain" or "network"
+	Name     string
+	IDs      []string // IDs of the resources with the name, in listing order
+}
+
+func (e *AmbiguousNameError) Error() string {
+	return fmt.Sprintf("conoha: %d %ss are named %q: %s", len(e.IDs), e.Resource, e.Name, strings.Join(e.IDs, ", "))
+}
+
+// resolve finds the resource whose ID is nameOrID, or else the only one
+// that has it as a name. A nameOrID that looks like an ID is first fetched
+// with get; the listing is only searched when it is not an ID or get finds
+// nothing. A missing resource is reported with an error matching
+// ErrNotFound and several with an *AmbiguousNameError.
+func resolve[T any](resource, nameOrID string, get func(id string) (*T, error), each func(fn func(T) error) error, id func(T) string, hasName func(T, string) bool) (*T, error) {
+	if uuidPattern.MatchString(nameOrID) {
+		item, err := get(nameOrID)
+		if err == nil {
+			return item, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+	}
+	var byID *T
+	var byName []T
+	err := each(func(item T) error {
+		if id(item) == nameOrID {
+			byID = &item
+			return ErrStopIteration
+		}
+		if hasName(item, nameOrID) {
+			byName = append(byName, item)
+		}
+		return nil
+	})
+	switch {
+	case err != nil:
+		return nil, err
+	case byID != nil:
+		return byID, nil
+	case len(byName) == 1:
+		return &byName[0], nil
+	case len(byName) == 0:
+		return nil, fmt.Errorf("%w: no %s has the name or ID %q", ErrNotFound, resource, nameOrID)
+	}
+	ids := make([]string, len(byName))
+	for i, item := range byName {
+		ids[i] = id(item)
+	}
+	return nil, &AmbiguousNameError{Resource: resource, Name: nameOrID, IDs: ids}
+}
+
+// eachOf adapts a single-page listing to resolve.
+func eachOf[T any](items []T, err error) func(fn func(T) error) error {
+	return func(fn func(T) error) error {
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				if errors.Is(err, ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ResolveServer returns the server with the given ID or name. The name of
+// a server is its instance_name_tag metadata, the name shown in the
+// control panel; its API name (e.g. "vm-0a1b2c3d") matches too.
+func (c *Client) ResolveServer(ctx context.Context, nameOrID string) (*ServerDetail, error) {
+	return resolve("server", nameOrID,
+		func(id string) (*ServerDetail, error) { return c.GetServer(ctx, id) },
+		func(fn func(ServerDetail) error) error { return c.EachServerDetail(ctx, nil, fn) },
+		func(s ServerDetail) string { return s.ID },
+		func(s ServerDetail, name string) bool {
+			return s.Metadata["instance_name_tag"] == name || s.Name == name
+		})
+}
+
+// ResolveFlavor returns the flavor with the given ID or name, such as
+// "g2l-t-c2m1".
+func (c *Client) ResolveFlavor(ctx context.Context, nameOrID string) (*FlavorDetail, error) {
+	return resolve("flavor", nameOrID,
+		func(id string) (*FlavorDetail, error) { return c.GetFlavor(ctx, id) },
+		eachOf(c.ListFlavorsDetail(ctx)),
+		func(f FlavorDetail) string { return f.ID },
+		func(f FlavorDetail, name string) bool { return f.Name == name })
+}
+
+// ResolveImage returns the image with the given ID or name, such as
+// "vmi-ubuntu-24.04-amd64".
+func (c *Client) ResolveImage(ctx context.Context, nameOrID string) (*Image, error) {
+	return resolve("image", nameOrID,
+		func(id string) (*Image, error) { return c.GetImage(ctx, id) },
+		func(fn func(Image) error) error { return c.EachImage(ctx, nil, fn) },
+		func(img Image) string { return img.ID },
+		func(img Image, name string) bool { return img.Name == name })
+}
+
+// ResolveSecurityGroup returns the security group with the given ID or
+// name.
+func (c *Client) ResolveSecurityGroup(ctx context.Context, nameOrID string) (*SecurityGroup, error) {
+	return resolve("security group", nameOrID,
+		func(id string) (*SecurityGroup, error) { return c.GetSecurityGroup(ctx, id) },
+		func(fn func(SecurityGroup) error) error { return c.EachSecurityGroup(ctx, nil, fn) },
+		func(sg SecurityGroup) string { return sg.ID },
+		func(sg SecurityGroup, name string) bool { return sg.Name == name })
+}
+
+// ResolveVolume returns the volume with the given ID or name, with full
+// details.
+func (c *Client) ResolveVolume(ctx context.Context, nameOrID string) (*Volume, error) {
+	fetched := false
+	v, err := resolve("volume", nameOrID,
+		func(id string) (*Volume, error) {
+			v, err := c.GetVolume(ctx, id)
+			fetched = err == nil
+			return v, err
+		},
+		func(fn func(Volume) error) error { return c.EachVolume(ctx, nil, fn) },
+		func(v Volume) string { return v.ID },
+		func(v Volume, name string) bool { return v.Name == name })
+	if err != nil || fetched {
+		return v, err
+	}
+	// The volume listing is the basic one, without status and size.
+	return c.GetVolume(ctx, v.ID)
+}
+
+// ResolveDomain returns the DNS domain with the given ID or name. The
+// trailing dot of the name may be omitted: "example.com" matches
+// "example.com.".
+func (c *Client) ResolveDomain(ctx context.Context, nameOrID string) (*Domain, error) {
+	return resolve("domain", nameOrID,
+		func(id string) (*Domain, error) { return c.GetDomain(ctx, id) },
+		func(fn func(Domain) error) error { return c.EachDomain(ctx, nil, fn) },
+		func(d Domain) string { return d.UUID },
+		func(d Domain, name string) bool { return d.Name == name || d.Name == name+"." })
+}
+
+// ResolveNetwork returns the network with the given ID or name.
+func (c *Client) ResolveNetwork(ctx context.Context, nameOrID string) (*Network, error) {
+	return resolve("network", nameOrID,
+		func(id string) (*Network, error) { return c.GetNetwork(ctx, id) },
+		func(fn func(Network) error) error { return c.EachNetwork(ctx, nil, fn) },
+		func(n Network) string { return n.ID },
+		func(n Network, name string) bool { return n.Name == name })
+}
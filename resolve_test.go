@@ -0,0 +1,229 @@
+package conoha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestResolveServer(t *testing.T) {
+	servers := []ServerDetail{
+		{ID: "s00", Name: "vm-00", Metadata: map[string]string{"instance_name_tag": "web"}},
+		{ID: "s01", Name: "vm-01", Metadata: map[string]string{"instance_name_tag": "db"}},
+		{ID: "s02", Name: "vm-02", Metadata: map[string]string{"instance_name_tag": "db"}},
+		{ID: "s03", Name: "vm-03", Metadata: map[string]string{"instance_name_tag": "s00"}},
+	}
+	var requests int
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/servers/detail" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var page []ServerDetail
+		for _, id := range pagedIDs(r, []string{"s00", "s01", "s02", "s03"}) {
+			for _, s := range servers {
+				if s.ID == id {
+					page = append(page, s)
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"servers": page})
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	for _, tc := range []struct{ arg, want string }{
+		{"web", "s00"},
+		{"vm-01", "s01"},
+		{"s02", "s02"},
+		// An ID takes precedence over a name.
+		{"s00", "s00"},
+	} {
+		s, err := client.ResolveServer(ctx, tc.arg)
+		assertNoError(t, err)
+		if s.ID != tc.want {
+			t.Errorf("ResolveServer(%q) = %s, want %s", tc.arg, s.ID, tc.want)
+		}
+	}
+
+	_, err := client.ResolveServer(ctx, "db")
+	var ambiguous *AmbiguousNameError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("expected *AmbiguousNameError, got %T: %v", err, err)
+	}
+	if ambiguous.Resource != "server" || ambiguous.Name != "db" || len(ambiguous.IDs) != 2 || ambiguous.IDs[0] != "s01" || ambiguous.IDs[1] != "s02" {
+		t.Errorf("error = %+v", ambiguous)
+	}
+	if want := `conoha: 2 servers are named "db": s01, s02`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	_, err = client.ResolveServer(ctx, "mail")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if requests == 0 {
+		t.Error("no requests were made")
+	}
+}
+
+func TestResolveServer_StopsAtID(t *testing.T) {
+	var requests int
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var page []ServerDetail
+		for _, id := range pagedIDs(r, serverIDs(DefaultPageSize*2)) {
+			page = append(page, ServerDetail{ID: id})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"servers": page})
+	})
+	defer server.Close()
+
+	s, err := client.ResolveServer(context.Background(), "s00")
+	assertNoError(t, err)
+	if s.ID != "s00" || requests != 1 {
+		t.Errorf("server %s after %d requests, want s00 after 1", s.ID, requests)
+	}
+}
+
+func TestResolveFlavor(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"flavors":[{"id":"f1","name":"g2l-t-c2m1"},{"id":"f2","name":"g2l-t-c3m2"}]}`))
+	})
+	defer server.Close()
+
+	f, err := client.ResolveFlavor(context.Background(), "g2l-t-c3m2")
+	assertNoError(t, err)
+	if f.ID != "f2" {
+		t.Errorf("flavor = %s, want f2", f.ID)
+	}
+	_, err = client.ResolveFlavor(context.Background(), "g2l-t-c9m9")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestResolveVolume_GetsDetails(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/test-tenant-id/volumes":
+			if r.URL.Query().Get("marker") != "" {
+				w.Write([]byte(`{"volumes":[]}`))
+				return
+			}
+			w.Write([]byte(`{"volumes":[{"id":"v1","name":"boot"},{"id":"v2","name":"data"}]}`))
+		case "/test-tenant-id/volumes/v2":
+			w.Write([]byte(`{"volume":{"id":"v2","name":"data","status":"available","size":200}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	v, err := client.ResolveVolume(context.Background(), "data")
+	assertNoError(t, err)
+	if v.ID != "v2" || v.Status != "available" || v.Size != 200 {
+		t.Errorf("volume = %+v", v)
+	}
+}
+
+func TestResolveDomain_TrailingDot(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") != "" && r.URL.Query().Get("offset") != "0" {
+			w.Write([]byte(`{"domains":[],"total_count":1}`))
+			return
+		}
+		w.Write([]byte(`{"domains":[{"uuid":"d1","name":"example.com."}],"total_count":1}`))
+	})
+	defer server.Close()
+
+	for _, name := range []string{"example.com", "example.com.", "d1"} {
+		d, err := client.ResolveDomain(context.Background(), name)
+		assertNoError(t, err)
+		if d.UUID != "d1" {
+			t.Errorf("ResolveDomain(%q) = %s, want d1", name, d.UUID)
+		}
+	}
+}
+
+func TestResolveSecurityGroup_ListError(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"boom"}`))
+	})
+	defer server.Close()
+
+	_, err := client.ResolveSecurityGroup(context.Background(), "web")
+	assertAPIError(t, err, http.StatusInternalServerError)
+}
+
+func TestResolve_GetsByID(t *testing.T) {
+	const id = "0b6e6c2a-3f1d-4c5e-9a8b-7c6d5e4f3a2b"
+	var paths []string
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/servers/" + id:
+			w.Write([]byte(`{"server":{"id":"` + id + `","status":"ACTIVE"}}`))
+		case "/test-tenant-id/volumes/" + id:
+			w.Write([]byte(`{"volume":{"id":"` + id + `","status":"available"}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	s, err := client.ResolveServer(ctx, id)
+	assertNoError(t, err)
+	v, err := client.ResolveVolume(ctx, id)
+	assertNoError(t, err)
+	if s.Status != "ACTIVE" || v.Status != "available" || len(paths) != 2 {
+		t.Errorf("server %+v, volume %+v after requests %v", s, v, paths)
+	}
+}
+
+func TestResolve_IDNotFoundFallsBackToNames(t *testing.T) {
+	const name = "0b6e6c2a-3f1d-4c5e-9a8b-7c6d5e4f3a2b"
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/networks/"+name:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"NeutronError":{"message":"not found"}}`))
+		case r.URL.Path == "/networks" && r.URL.Query().Get("marker") == "":
+			w.Write([]byte(`{"networks":[{"id":"n1","name":"` + name + `"}]}`))
+		default:
+			w.Write([]byte(`{"networks":[]}`))
+		}
+	})
+	defer server.Close()
+
+	n, err := client.ResolveNetwork(context.Background(), name)
+	assertNoError(t, err)
+	if n.ID != "n1" {
+		t.Errorf("network = %s, want n1", n.ID)
+	}
+}
+
+func TestResolveSecurityGroup_Pages(t *testing.T) {
+	ids := serverIDs(DefaultPageSize + 1)
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var page []SecurityGroup
+		for _, id := range pagedIDs(r, ids) {
+			page = append(page, SecurityGroup{ID: id, Name: "name-" + id})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"security_groups": page})
+	})
+	defer server.Close()
+
+	last := ids[len(ids)-1]
+	sg, err := client.ResolveSecurityGroup(context.Background(), "name-"+last)
+	assertNoError(t, err)
+	if sg.ID != last {
+		t.Errorf("security group = %s, want %s", sg.ID, last)
+	}
+}
@@ -0,0 +1,202 @@
+package conoha
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the Client retries transient API failures.
+//
+// Zero-valued fields fall back to the values of DefaultRetryPolicy.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// A value of 1 disables retries.
+	MaxAttempts int
+
+	// MinBackoff is the delay before the first retry. Each further retry
+	// doubles it, up to MaxBackoff. A random jitter of up to half the delay
+	// is subtracted so that parallel clients do not retry in lockstep.
+	// A Retry-After header replaces the computed delay; when it asks for
+	// more than MaxBackoff, the response is returned instead of retried.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Retryable reports whether a failed attempt should be retried.
+	// resp is nil when err is a transport error. Requests whose body cannot
+	// be replayed (e.g. an *os.File passed to UploadObject) are never
+	// retried, whatever Retryable returns.
+	Retryable func(req *http.Request, resp *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy returns the policy used for zero-valued RetryPolicy fields:
+// 4 attempts, backoff from 500ms up to 30s, and DefaultRetryable.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		Retryable:   DefaultRetryable,
+	}
+}
+
+// DefaultRetryable retries idempotent requests (GET, HEAD, PUT, DELETE)
+// that failed with a transport error, 429 Too Many Requests, or a 5xx
+// status that indicates a temporary server-side problem.
+func DefaultRetryable(req *http.Request, resp *http.Response, err error) bool {
+	if !isIdempotentMethod(req.Method) {
+		return false
+	}
+	if err != nil {
+		// Do not retry once the caller has given up.
+		return req.Context().Err() == nil
+	}
+	return isRetryableStatus(resp.StatusCode)
+}
+
+// WithRetryPolicy enables retries of transient failures.
+// Without this option the client never retries.
+//
+// Example:
+//
+//	client := conoha.NewClient(conoha.WithRetryPolicy(conoha.RetryPolicy{
+//	    MaxAttempts: 5,
+//	}))
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		def := DefaultRetryPolicy()
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = def.MaxAttempts
+		}
+		if policy.MinBackoff <= 0 {
+			policy.MinBackoff = def.MinBackoff
+		}
+		if policy.MaxBackoff <= 0 {
+			policy.MaxBackoff = def.MaxBackoff
+		}
+		if policy.MaxBackoff < policy.MinBackoff {
+			policy.MaxBackoff = policy.MinBackoff
+		}
+		if policy.Retryable == nil {
+			policy.Retryable = def.Retryable
+		}
+		c.retryPolicy = &policy
+	}
+}
+
+// sendWithRetry sends req, retrying it according to the client's
+// RetryPolicy. Without a policy it sends req exactly once.
+func (c *Client) sendWithRetry(req *http.Request) (*http.Response, error) {
+	policy := c.retryPolicy
+	for attempt := 1; ; attempt++ {
+		resp, err := c.attempt(req, attempt)
+		if policy == nil || attempt >= policy.MaxAttempts || !policy.Retryable(req, resp, err) {
+			return resp, err
+		}
+		next, rewindErr := rewindRequest(req)
+		if rewindErr != nil {
+			return resp, err
+		}
+
+		wait := policy.backoff(attempt)
+		if resp != nil {
+			if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if d > policy.MaxBackoff {
+					// Waiting that long is the caller's decision.
+					return resp, err
+				}
+				wait = d
+			}
+			drainAndClose(resp.Body)
+		}
+		if c.metrics != nil {
+			c.metrics.IncRetry(operationFromContext(req.Context()))
+		}
+		c.logEvent(req.Context(), slog.LevelInfo, "conoha retrying request",
+			slog.String("method", req.Method),
+			slog.String("url", req.URL.String()),
+			slog.Int("attempt", attempt),
+			slog.Duration("wait", wait))
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		req = next
+	}
+}
+
+// backoff returns the delay before retry number attempt (starting at 1).
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.MinBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if half := int64(d / 2); half > 0 {
+		d -= time.Duration(rand.Int63n(half))
+	}
+	return d
+}
+
+// parseRetryAfter parses a Retry-After header, given either as a number of
+// seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isRetryableStatus reports whether an HTTP status usually indicates a
+// temporary condition that may succeed when retried.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,309 @@
+package conoha
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetries is a retry policy with negligible backoff for tests.
+var fastRetries = WithRetryPolicy(RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  time.Millisecond,
+	MaxBackoff:  2 * time.Millisecond,
+})
+
+func TestRetry_GETRetriedOn503(t *testing.T) {
+	var calls int32
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(503)
+			return
+		}
+		w.Write([]byte(`{"servers":[{"id":"s1"}]}`))
+	})
+	defer server.Close()
+	fastRetries(client)
+
+	servers, err := client.ListServers(context.Background(), nil)
+	assertNoError(t, err)
+
+	if len(servers) != 1 {
+		t.Errorf("len(servers) = %d", len(servers))
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(502)
+	})
+	defer server.Close()
+	fastRetries(client)
+
+	_, err := client.GetServer(context.Background(), "s1")
+	assertAPIError(t, err, 502)
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRetry_POSTNotRetriedByDefault(t *testing.T) {
+	var calls int32
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(503)
+	})
+	defer server.Close()
+	fastRetries(client)
+
+	_, err := client.CreateServer(context.Background(), CreateServerRequest{FlavorRef: "f"})
+	assertAPIError(t, err, 503)
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestRetry_NotFoundNotRetried(t *testing.T) {
+	var calls int32
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(404)
+	})
+	defer server.Close()
+	fastRetries(client)
+
+	_, err := client.GetServer(context.Background(), "missing")
+	assertAPIError(t, err, 404)
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestRetry_ReplaysRequestBody(t *testing.T) {
+	var bodies []string
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			w.WriteHeader(500)
+			return
+		}
+		w.Write([]byte(`{"volume":{"id":"v1"}}`))
+	})
+	defer server.Close()
+	fastRetries(client)
+
+	_, err := client.UpdateVolume(context.Background(), "v1", "renamed", nil)
+	assertNoError(t, err)
+
+	if len(bodies) != 2 {
+		t.Fatalf("attempts = %d, want 2", len(bodies))
+	}
+	if bodies[0] == "" || bodies[0] != bodies[1] {
+		t.Errorf("retried body differs: %q vs %q", bodies[0], bodies[1])
+	}
+}
+
+func TestRetry_StreamingBodyNeverReplayed(t *testing.T) {
+	var calls int32
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(503)
+	})
+	defer server.Close()
+	WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		Retryable:   func(*http.Request, *http.Response, error) bool { return true },
+	})(client)
+
+	// io.MultiReader hides the concrete reader type, so no GetBody is set.
+	body := io.MultiReader(strings.NewReader("iso data"))
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, server.URL+"/upload", body)
+	assertNoError(t, err)
+
+	_, _, err = client.doRaw(req)
+	assertAPIError(t, err, 503)
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestRetry_CustomPredicate(t *testing.T) {
+	var calls int32
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(409)
+			return
+		}
+		w.WriteHeader(204)
+	})
+	defer server.Close()
+	WithRetryPolicy(RetryPolicy{
+		MinBackoff: time.Millisecond,
+		Retryable: func(req *http.Request, resp *http.Response, err error) bool {
+			return err == nil && resp.StatusCode == 409
+		},
+	})(client)
+
+	err := client.StartServer(context.Background(), "s1")
+	assertNoError(t, err)
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestRetry_HonorsRetryAfter(t *testing.T) {
+	var calls int32
+	var first time.Time
+	var gap time.Duration
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(429)
+			return
+		}
+		gap = time.Since(first)
+		w.WriteHeader(204)
+	})
+	defer server.Close()
+	WithRetryPolicy(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Second})(client)
+
+	err := client.DeleteServer(context.Background(), "s1")
+	assertNoError(t, err)
+	if gap < 900*time.Millisecond {
+		t.Errorf("retried after %v, want about 1s (Retry-After)", gap)
+	}
+}
+
+func TestRetry_RetryAfterBeyondMaxBackoff(t *testing.T) {
+	var calls int32
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(429)
+	})
+	defer server.Close()
+	fastRetries(client)
+
+	start := time.Now()
+	err := client.DeleteServer(context.Background(), "s1")
+	assertAPIError(t, err, 429)
+	if n := atomic.LoadInt32(&calls); n != 1 || time.Since(start) > time.Second {
+		t.Errorf("%d calls in %v, want 1 call returned at once", n, time.Since(start))
+	}
+}
+
+func TestRetry_StopsWhenContextCancelled(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(503)
+	})
+	defer server.Close()
+	WithRetryPolicy(RetryPolicy{MaxAttempts: 10, MinBackoff: time.Hour})(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetServer(ctx, "s1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRetry_DisabledByDefault(t *testing.T) {
+	var calls int32
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(503)
+	})
+	defer server.Close()
+
+	_, err := client.GetServer(context.Background(), "s1")
+	assertAPIError(t, err, 503)
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestWithRetryPolicy_FillsDefaults(t *testing.T) {
+	c := NewClient(WithRetryPolicy(RetryPolicy{MaxAttempts: 2}))
+	def := DefaultRetryPolicy()
+
+	if c.retryPolicy.MaxAttempts != 2 {
+		t.Errorf("MaxAttempts = %d", c.retryPolicy.MaxAttempts)
+	}
+	if c.retryPolicy.MinBackoff != def.MinBackoff || c.retryPolicy.MaxBackoff != def.MaxBackoff {
+		t.Errorf("backoff = %v..%v", c.retryPolicy.MinBackoff, c.retryPolicy.MaxBackoff)
+	}
+	if c.retryPolicy.Retryable == nil {
+		t.Error("Retryable should default to DefaultRetryable")
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt, ceiling := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		3:  400 * time.Millisecond,
+		10: time.Second,
+	} {
+		d := p.backoff(attempt)
+		if d > ceiling || d < ceiling/2 {
+			t.Errorf("backoff(%d) = %v, want within [%v, %v]", attempt, d, ceiling/2, ceiling)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"Wed, 01 Jan 2025 00:00:10 GMT", 10 * time.Second, true},
+		{"Tue, 31 Dec 2024 23:59:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDefaultRetryable(t *testing.T) {
+	get, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	post, _ := http.NewRequest(http.MethodPost, "https://example.com", nil)
+
+	if !DefaultRetryable(get, &http.Response{StatusCode: 429}, nil) {
+		t.Error("GET 429 should be retryable")
+	}
+	if !DefaultRetryable(get, nil, errors.New("connection reset")) {
+		t.Error("GET transport error should be retryable")
+	}
+	if DefaultRetryable(get, &http.Response{StatusCode: 400}, nil) {
+		t.Error("GET 400 should not be retryable")
+	}
+	if DefaultRetryable(post, &http.Response{StatusCode: 503}, nil) {
+		t.Error("POST should not be retryable")
+	}
+}
@@ -0,0 +1,186 @@
+package conoha
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TokenStore caches issued tokens so that they can be reused across
+// clients and processes instead of authenticating every time.
+//
+// Keys identify the identity endpoint, user and tenant a token was issued
+// for. Load returns nil and no error when nothing is cached for key.
+type TokenStore interface {
+	Load(ctx context.Context, key string) (*CachedToken, error)
+	Save(ctx context.Context, key string, token *CachedToken) error
+	Delete(ctx context.Context, key string) error
+}
+
+// CachedToken is a token as kept in a TokenStore.
+type CachedToken struct {
+	// Token is the X-Subject-Token value.
+	Token string `json:"token"`
+	// Details holds the token body, including its catalog and ExpiresAt.
+	Details Token `json:"details"`
+}
+
+// WithTokenStore makes Authenticate, AuthenticateByName and
+// AuthenticateWithCredential reuse a token cached in store until shortly
+// before it expires. Newly issued tokens are saved to store, and a cached
+// token the API rejects with 401 is removed.
+//
+// Errors from the store are logged and otherwise ignored; authentication
+// then falls back to issuing a new token.
+func WithTokenStore(store TokenStore) ClientOption {
+	return func(c *Client) {
+		c.tokenStore = store
+	}
+}
+
+// tokenCacheKey builds the TokenStore key for a token issued by
+// identityURL for user in tenant.
+func tokenCacheKey(identityURL, user, tenant string) string {
+	return identityURL + "|" + user + "|" + tenant
+}
+
+// loadCachedToken looks up key in the token store and, if it holds a token
+// that is not about to expire, makes it the client's token. It returns nil
+// if no usable token is cached.
+func (c *Client) loadCachedToken(ctx context.Context, key string) *CachedToken {
+	if c.tokenStore == nil {
+		return nil
+	}
+	cached, err := c.tokenStore.Load(ctx, key)
+	if err != nil {
+		c.logEvent(ctx, slog.LevelWarn, "conoha token cache load failed", slog.String("error", err.Error()))
+		return nil
+	}
+	if cached == nil || cached.Token == "" {
+		return nil
+	}
+	if time.Until(parseTokenExpiry(cached.Details.ExpiresAt)) < tokenRefreshWindow {
+		return nil
+	}
+	return cached
+}
+
+func (c *Client) saveCachedToken(ctx context.Context, key string, cached *CachedToken) {
+	if c.tokenStore == nil {
+		return
+	}
+	if err := c.tokenStore.Save(ctx, key, cached); err != nil {
+		c.logEvent(ctx, slog.LevelWarn, "conoha token cache save failed", slog.String("error", err.Error()))
+	}
+}
+
+func (c *Client) deleteCachedToken(ctx context.Context, key string) {
+	if c.tokenStore == nil {
+		return
+	}
+	if err := c.tokenStore.Delete(ctx, key); err != nil {
+		c.logEvent(ctx, slog.LevelWarn, "conoha token cache delete failed", slog.String("error", err.Error()))
+	}
+}
+
+// ------------------------------------------------------------
+// File token store
+// ------------------------------------------------------------
+
+// FileTokenStore is a TokenStore that keeps one file per key in Dir.
+// Files are written with 0600 permissions and replaced atomically, so the
+// store can be shared by concurrent processes.
+type FileTokenStore struct {
+	Dir string
+}
+
+// NewFileTokenStore returns a FileTokenStore in dir. An empty dir selects
+// "conoha/tokens" under the user's cache directory (os.UserCacheDir).
+func NewFileTokenStore(dir string) (*FileTokenStore, error) {
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("locate token cache directory: %w", err)
+		}
+		dir = filepath.Join(cacheDir, "conoha", "tokens")
+	}
+	return &FileTokenStore{Dir: dir}, nil
+}
+
+// path returns the file for key. Keys are hashed so that they are safe
+// file names and do not reveal user or tenant IDs.
+func (s *FileTokenStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Load implements TokenStore.
+func (s *FileTokenStore) Load(ctx context.Context, key string) (*CachedToken, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cached CachedToken
+	if err := json.Unmarshal(data, &cached); err != nil {
+		// A corrupt entry is treated as a cache miss; it is overwritten by
+		// the next Save.
+		return nil, nil
+	}
+	return &cached, nil
+}
+
+// Save implements TokenStore.
+func (s *FileTokenStore) Save(ctx context.Context, key string, token *CachedToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(s.Dir, ".token-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	// CreateTemp already uses 0600; Chmod guards against unusual umasks.
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, s.path(key)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// Delete implements TokenStore.
+func (s *FileTokenStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
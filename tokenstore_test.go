@@ -0,0 +1,123 @@
+package conoha
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	store, err := NewFileTokenStore(filepath.Join(t.TempDir(), "tokens"))
+	assertNoError(t, err)
+	ctx := context.Background()
+
+	cached, err := store.Load(ctx, "missing")
+	if cached != nil || err != nil {
+		t.Fatalf("Load(missing) = %v, %v", cached, err)
+	}
+
+	want := &CachedToken{Token: "tok", Details: Token{ExpiresAt: "2030-01-01T00:00:00Z"}}
+	assertNoError(t, store.Save(ctx, "key", want))
+	got, err := store.Load(ctx, "key")
+	assertNoError(t, err)
+	if got.Token != "tok" || got.Details.ExpiresAt != want.Details.ExpiresAt {
+		t.Errorf("Load = %+v", got)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(store.path("key"))
+		assertNoError(t, err)
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Errorf("file mode = %o, want 600", perm)
+		}
+	}
+
+	assertNoError(t, store.Delete(ctx, "key"))
+	assertNoError(t, store.Delete(ctx, "key"))
+	if got, _ := store.Load(ctx, "key"); got != nil {
+		t.Errorf("Load after Delete = %+v", got)
+	}
+}
+
+func TestTokenStore_ReusesCachedToken(t *testing.T) {
+	ts := &tokenRefreshServer{expiresAt: time.Now().Add(time.Hour).UTC().Format(time.RFC3339)}
+	server, client := setupTestServer(ts.handler)
+	defer server.Close()
+	store, _ := NewFileTokenStore(t.TempDir())
+	WithTokenStore(store)(client)
+
+	_, err := client.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+
+	// A second client, e.g. the next CLI invocation, reuses the token.
+	otherServer, other := setupTestServer(ts.handler)
+	defer otherServer.Close()
+	other.IdentityURL = client.IdentityURL
+	other.ComputeURL = client.ComputeURL
+	WithTokenStore(store)(other)
+	_, err = other.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+
+	if issued, _ := ts.counts(); issued != 1 {
+		t.Errorf("tokens issued = %d, want 1", issued)
+	}
+	if other.Token != "token-1" || other.TenantID != "tenant" {
+		t.Errorf("Token = %q, TenantID = %q", other.Token, other.TenantID)
+	}
+
+	// A different user does not see the cached token.
+	_, err = other.Authenticate(context.Background(), "someone-else", "pass", "tenant")
+	assertNoError(t, err)
+	if issued, _ := ts.counts(); issued != 2 {
+		t.Errorf("tokens issued = %d, want 2", issued)
+	}
+}
+
+func TestTokenStore_IgnoresExpiringToken(t *testing.T) {
+	ts := &tokenRefreshServer{}
+	server, client := setupTestServer(ts.handler)
+	defer server.Close()
+	store, _ := NewFileTokenStore(t.TempDir())
+	WithTokenStore(store)(client)
+
+	key := tokenCacheKey(client.IdentityURL, "user", "tenant")
+	soon := time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+	assertNoError(t, store.Save(context.Background(), key, &CachedToken{Token: "stale", Details: Token{ExpiresAt: soon}}))
+
+	_, err := client.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+	if client.Token != "token-1" {
+		t.Errorf("Token = %q, want a newly issued token", client.Token)
+	}
+}
+
+func TestTokenStore_InvalidatesOn401(t *testing.T) {
+	ts := &tokenRefreshServer{}
+	server, client := setupTestServer(ts.handler)
+	defer server.Close()
+	store, _ := NewFileTokenStore(t.TempDir())
+	WithTokenStore(store)(client)
+
+	// The cache holds a token the API no longer accepts.
+	key := tokenCacheKey(client.IdentityURL, "user", "tenant")
+	later := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	assertNoError(t, store.Save(context.Background(), key, &CachedToken{Token: "revoked", Details: Token{ExpiresAt: later}}))
+
+	_, err := client.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+	if client.Token != "revoked" {
+		t.Fatalf("Token = %q, want the cached token", client.Token)
+	}
+
+	_, err = client.ListServers(context.Background(), nil)
+	assertNoError(t, err)
+
+	cached, err := store.Load(context.Background(), key)
+	assertNoError(t, err)
+	if cached == nil || cached.Token != "token-1" {
+		t.Errorf("cached = %+v, want token-1", cached)
+	}
+}
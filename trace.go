@@ -0,0 +1,140 @@
+package conoha
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Operation describes the SDK call that a request belongs to.
+type Operation struct {
+	// Service is the service type of the endpoint the request goes to,
+	// one of the ServiceType* constants, or "" for an unknown URL.
+	Service string
+
+	// Name is the name of the Client method, e.g. "CreateServer".
+	Name string
+
+	Region string
+
+	// ResourceID is the last UUID in the request path, if any.
+	ResourceID string
+
+	Method string
+	URL    string
+}
+
+// String returns the qualified operation name, e.g.
+// "conoha.compute.CreateServer".
+func (op Operation) String() string {
+	parts := []string{"conoha"}
+	if op.Service != "" {
+		parts = append(parts, op.Service)
+	}
+	if op.Name != "" {
+		parts = append(parts, op.Name)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Tracer starts a span for every SDK operation. The span covers the whole
+// call, including token refreshes and retries.
+//
+// The otelconoha module provides an OpenTelemetry implementation, so that
+// this package itself does not depend on OpenTelemetry.
+type Tracer interface {
+	// Start starts a span for op. The returned context is used for the
+	// HTTP requests of the operation, so spans created by an instrumented
+	// http.Client transport become children of this span.
+	Start(ctx context.Context, op Operation) (context.Context, Span)
+}
+
+// Span is an operation span started by a Tracer.
+type Span interface {
+	// End finishes the span. statusCode is the final HTTP status code, or 0
+	// when no response was received, in which case err is the cause.
+	End(statusCode int, err error)
+}
+
+// WithTracer creates a span for every SDK operation with t.
+func WithTracer(t Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = t
+	}
+}
+
+// operationNameKey is the context key that overrides the operation name
+// otherwise derived from the call stack.
+type operationNameKey struct{}
+
+// withOperationName returns a copy of ctx whose requests are attributed to
+// the operation name.
+func withOperationName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationNameKey{}, name)
+}
+
+// operation describes the SDK call that req belongs to.
+func (c *Client) operation(req *http.Request) Operation {
+	c.mu.RLock()
+	region := c.Region
+	c.mu.RUnlock()
+
+	name, ok := req.Context().Value(operationNameKey{}).(string)
+	if !ok {
+		name = callerMethodName()
+	}
+	return Operation{
+		Service:    c.serviceForURL(req.URL.String()),
+		Name:       name,
+		Region:     region,
+		ResourceID: resourceIDFromPath(req.URL.Path),
+		Method:     req.Method,
+		URL:        req.URL.String(),
+	}
+}
+
+// clientMethodPrefix is the prefix of the runtime function names of
+// *Client methods.
+var clientMethodPrefix = reflect.TypeOf(Client{}).PkgPath() + ".(*Client)."
+
+// callerMethodName returns the name of the innermost exported *Client
+// method on the call stack, which is the SDK method the user called.
+func callerMethodName() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if name, ok := strings.CutPrefix(frame.Function, clientMethodPrefix); ok && isExportedMethod(name) {
+			return name
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// isExportedMethod reports whether name is an exported method name rather
+// than an unexported method or a closure such as "CreateServer.func1".
+func isExportedMethod(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r) && !strings.Contains(name, ".")
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// resourceIDFromPath returns the last UUID segment of path, or "".
+func resourceIDFromPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if uuidPattern.MatchString(segments[i]) {
+			return segments[i]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,155 @@
+package conoha
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingTracer records the operations it starts spans for.
+type recordingTracer struct {
+	mu    sync.Mutex
+	ops   []Operation
+	ended []int
+	errs  []error
+}
+
+type spanKey struct{}
+
+func (t *recordingTracer) Start(ctx context.Context, op Operation) (context.Context, Span) {
+	t.mu.Lock()
+	t.ops = append(t.ops, op)
+	t.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, op.Name), &recordingSpan{t: t}
+}
+
+type recordingSpan struct{ t *recordingTracer }
+
+func (s *recordingSpan) End(statusCode int, err error) {
+	s.t.mu.Lock()
+	s.t.ended = append(s.t.ended, statusCode)
+	s.t.errs = append(s.t.errs, err)
+	s.t.mu.Unlock()
+}
+
+func TestTracer_SpanPerOperation(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(202)
+		w.Write([]byte(`{"server":{"id":"s1"}}`))
+	})
+	defer server.Close()
+	client.ComputeURL = server.URL + "/compute"
+	tracer := &recordingTracer{}
+	WithTracer(tracer)(client)
+
+	var spanInContext interface{}
+	WithMiddleware(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			spanInContext = req.Context().Value(spanKey{})
+			return next.Do(req)
+		})
+	})(client)
+
+	_, err := client.CreateServer(context.Background(), CreateServerRequest{FlavorRef: "f"})
+	assertNoError(t, err)
+
+	if len(tracer.ops) != 1 {
+		t.Fatalf("spans = %d, want 1", len(tracer.ops))
+	}
+	op := tracer.ops[0]
+	if op.String() != "conoha.compute.CreateServer" {
+		t.Errorf("operation = %q", op.String())
+	}
+	if op.Name != "CreateServer" || op.Region != DefaultRegion || op.Method != http.MethodPost {
+		t.Errorf("operation = %+v", op)
+	}
+	if tracer.ended[0] != 202 {
+		t.Errorf("span ended with status %d, want 202", tracer.ended[0])
+	}
+	if spanInContext != "CreateServer" {
+		t.Errorf("request context does not carry the span context")
+	}
+}
+
+func TestTracer_ServiceAndResourceID(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(204)
+	})
+	defer server.Close()
+	client.DNSServiceURL = server.URL + "/dns"
+	tracer := &recordingTracer{}
+	WithTracer(tracer)(client)
+
+	const domainID = "8a5e3f6c-1b2d-4c3e-9f8a-7b6c5d4e3f2a"
+	const recordID = "0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0"
+	assertNoError(t, client.DeleteDNSRecord(context.Background(), domainID, recordID))
+
+	op := tracer.ops[0]
+	if op.String() != "conoha.dns.DeleteDNSRecord" {
+		t.Errorf("operation = %q", op.String())
+	}
+	if op.ResourceID != recordID {
+		t.Errorf("ResourceID = %q, want %q", op.ResourceID, recordID)
+	}
+}
+
+func TestTracer_TransportError(t *testing.T) {
+	client := NewClient(WithHTTPClient(&http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})}))
+	tracer := &recordingTracer{}
+	WithTracer(tracer)(client)
+
+	_, err := client.ListFlavors(context.Background())
+	assertError(t, err)
+	if tracer.ended[0] != 0 || tracer.errs[0] == nil {
+		t.Errorf("span ended with %d, %v; want 0 and an error", tracer.ended[0], tracer.errs[0])
+	}
+}
+
+func TestTracer_TokenRefreshIsNamed(t *testing.T) {
+	ts := &tokenRefreshServer{
+		expiresAt: time.Now().Add(time.Minute).UTC().Format(time.RFC3339),
+	}
+	server, client := setupTestServer(ts.handler)
+	defer server.Close()
+	_, err := client.Authenticate(context.Background(), "user", "pass", "tenant")
+	assertNoError(t, err)
+	tracer := &recordingTracer{}
+	WithTracer(tracer)(client)
+
+	_, err = client.ListServers(context.Background(), nil)
+	assertNoError(t, err)
+
+	var names []string
+	for _, op := range tracer.ops {
+		names = append(names, op.Name)
+	}
+	// The token expires within the refresh window, so ListServers
+	// re-issues it first. The refresh span is nested in the ListServers one.
+	if strings.Join(names, ",") != "ListServers,RefreshToken" {
+		t.Errorf("spans = %v, want [ListServers RefreshToken]", names)
+	}
+}
+
+func TestResourceIDFromPath(t *testing.T) {
+	tests := map[string]string{
+		"/v2.1/servers/4e6c1a2b-3d4e-4f5a-8b9c-0d1e2f3a4b5c/action": "4e6c1a2b-3d4e-4f5a-8b9c-0d1e2f3a4b5c",
+		"/v2.1/servers/detail":  "",
+		"/v1/AUTH_t/bucket/obj": "",
+	}
+	for path, want := range tests {
+		if got := resourceIDFromPath(path); got != want {
+			t.Errorf("resourceIDFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -0,0 +1,280 @@
+package conoha
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+)
+
+// MaxUserDataSize is the largest user data the Compute API accepts, in
+// bytes after base64 encoding.
+const MaxUserDataSize = 65535
+
+// ErrUserDataTooLarge is returned (wrapped) when user data exceeds
+// MaxUserDataSize.
+var ErrUserDataTooLarge = errors.New("conoha: user data is too large")
+
+// UserData composes the user data that cloud-init runs on the first boot
+// of a server. Encode it for CreateServerRequest.UserData,
+// RebuildServerRequest.UserData or ServerSpec.UserData:
+//
+//	userData, err := (&conoha.UserData{
+//		Config: &conoha.CloudConfig{
+//			Packages: []string{"nginx"},
+//			RunCmd:   []string{"systemctl enable --now nginx"},
+//		},
+//	}).Encode()
+type UserData struct {
+	// Config is the cloud-config part.
+	Config *CloudConfig
+	// Scripts are shell scripts run once, after Config, in order. A script
+	// without a "#!" line runs with /bin/sh.
+	Scripts []string
+}
+
+// CloudConfig is a cloud-config document.
+type CloudConfig struct {
+	// Users are created in addition to the default user of the image.
+	Users []CloudUser
+	// SSHAuthorizedKeys are installed for the default user.
+	SSHAuthorizedKeys []string
+	// PackageUpdate and PackageUpgrade update the package index and
+	// upgrade the installed packages before Packages are installed.
+	PackageUpdate  bool
+	PackageUpgrade bool
+	Packages       []string
+	WriteFiles     []WriteFile
+	// RunCmd are shell commands run at the end of the first boot.
+	RunCmd []string
+}
+
+// CloudUser is a user created by cloud-init.
+type CloudUser struct {
+	Name              string
+	Groups            []string
+	Shell             string // e.g. "/bin/bash"
+	Sudo              string // sudoers rule, e.g. "ALL=(ALL) NOPASSWD:ALL"
+	SSHAuthorizedKeys []string
+}
+
+// WriteFile is a file written by cloud-init.
+type WriteFile struct {
+	Path        string
+	Content     string
+	Owner       string // e.g. "root:root"
+	Permissions string // e.g. "0644"
+	Append      bool
+}
+
+// mimeBoundary separates the parts of multipart user data. It is fixed so
+// that the same user data always encodes the same way.
+const mimeBoundary = "==conoha-user-data=="
+
+// Bytes returns the user data before encoding: the cloud-config document
+// or the script alone, or a MIME multipart archive of all the parts.
+func (u *UserData) Bytes() ([]byte, error) {
+	type part struct {
+		contentType, filename, body string
+	}
+	var parts []part
+	if u.Config != nil {
+		parts = append(parts, part{"text/cloud-config", "cloud-config.yaml", u.Config.String()})
+	}
+	for i, script := range u.Scripts {
+		if !strings.HasPrefix(script, "#!") {
+			script = "#!/bin/sh\n" + script
+		}
+		parts = append(parts, part{"text/x-shellscript", fmt.Sprintf("script-%d.sh", i+1), script})
+	}
+	switch len(parts) {
+	case 0:
+		return nil, nil
+	case 1:
+		return []byte(parts[0].body), nil
+	}
+
+	for _, p := range parts {
+		if strings.Contains(p.body, mimeBoundary) {
+			return nil, fmt.Errorf("conoha: user data part %s contains the MIME boundary %q", p.filename, mimeBoundary)
+		}
+	}
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.SetBoundary(mimeBoundary); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\nMIME-Version: 1.0\r\n\r\n", mimeBoundary)
+	for _, p := range parts {
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType + `; charset="utf-8"`},
+			"Mime-Version":              {"1.0"},
+			"Content-Transfer-Encoding": {"8bit"},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", p.filename)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		pw.Write([]byte(p.body))
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode returns the user data encoded with EncodeUserData. Empty user
+// data encodes to "".
+func (u *UserData) Encode() (string, error) {
+	raw, err := u.Bytes()
+	if err != nil || len(raw) == 0 {
+		return "", err
+	}
+	return EncodeUserData(raw)
+}
+
+// EncodeUserData base64-encodes raw user data, gzip-compressing it first
+// when that makes it smaller; cloud-init decompresses it. It returns an
+// error wrapping ErrUserDataTooLarge when the result exceeds
+// MaxUserDataSize.
+func EncodeUserData(raw []byte) (string, error) {
+	data := raw
+	var gz bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&gz, gzip.BestCompression)
+	zw.Write(raw)
+	if err := zw.Close(); err == nil && gz.Len() < len(raw) {
+		data = gz.Bytes()
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	if err := checkUserData(encoded); err != nil {
+		return "", err
+	}
+	return encoded, nil
+}
+
+// checkUserData checks the size of base64-encoded user data.
+func checkUserData(userData string) error {
+	if len(userData) > MaxUserDataSize {
+		return fmt.Errorf("%w: %d bytes base64-encoded, the limit is %d", ErrUserDataTooLarge, len(userData), MaxUserDataSize)
+	}
+	return nil
+}
+
+// String returns the cloud-config document, starting with the
+// "#cloud-config" line.
+func (cc *CloudConfig) String() string {
+	var b strings.Builder
+	b.WriteString("#cloud-config\n")
+	if len(cc.Users) > 0 {
+		// Listing users replaces the default user unless it is listed too.
+		b.WriteString("users:\n  - default\n")
+		for _, u := range cc.Users {
+			fields := []yamlField{
+				{"name", u.Name},
+				{"groups", strings.Join(u.Groups, ", ")},
+				{"shell", u.Shell},
+				{"sudo", u.Sudo},
+			}
+			writeYAMLItem(&b, fields, "ssh_authorized_keys", u.SSHAuthorizedKeys)
+		}
+	}
+	writeYAMLList(&b, "ssh_authorized_keys", cc.SSHAuthorizedKeys)
+	if cc.PackageUpdate {
+		b.WriteString("package_update: true\n")
+	}
+	if cc.PackageUpgrade {
+		b.WriteString("package_upgrade: true\n")
+	}
+	writeYAMLList(&b, "packages", cc.Packages)
+	if len(cc.WriteFiles) > 0 {
+		b.WriteString("write_files:\n")
+		for _, f := range cc.WriteFiles {
+			fields := []yamlField{
+				{"path", f.Path},
+				{"content", f.Content},
+				{"owner", f.Owner},
+				{"permissions", f.Permissions},
+			}
+			if f.Append {
+				fields = append(fields, yamlField{"append", true})
+			}
+			writeYAMLItem(&b, fields, "", nil)
+		}
+	}
+	writeYAMLList(&b, "runcmd", cc.RunCmd)
+	return b.String()
+}
+
+// yamlField is a key and a string or bool value of a mapping. Empty
+// strings are left out.
+type yamlField struct {
+	key   string
+	value any
+}
+
+// writeYAMLItem writes a mapping as an item of a top-level sequence,
+// followed by the list key: values when there are values.
+func writeYAMLItem(b *strings.Builder, fields []yamlField, key string, values []string) {
+	prefix := "  - "
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		v := fmt.Sprint(f.value)
+		if s, ok := f.value.(string); ok {
+			v = yamlString(s)
+		}
+		fmt.Fprintf(b, "%s%s: %s\n", prefix, f.key, v)
+		prefix = "    "
+	}
+	if len(values) > 0 {
+		fmt.Fprintf(b, "%s%s:\n", prefix, key)
+		for _, v := range values {
+			fmt.Fprintf(b, "      - %s\n", yamlString(v))
+		}
+	}
+}
+
+// writeYAMLList writes key: values at the top level, unless values is
+// empty.
+func writeYAMLList(b *strings.Builder, key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s:\n", key)
+	for _, v := range values {
+		fmt.Fprintf(b, "  - %s\n", yamlString(v))
+	}
+}
+
+// yamlString returns s as a YAML scalar: plain when it cannot be read as
+// anything but that string, otherwise double-quoted, which JSON string
+// syntax is valid for.
+func yamlString(s string) string {
+	plain := s != "" && (isLetter(s[0]) || s[0] == '/')
+	for i := 0; plain && i < len(s); i++ {
+		c := s[i]
+		plain = isLetter(c) || c >= '0' && c <= '9' || strings.IndexByte("._-/+@=", c) >= 0
+	}
+	switch strings.ToLower(s) {
+	case "y", "n", "yes", "no", "on", "off", "true", "false", "null":
+		plain = false
+	}
+	if plain {
+		return s
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,191 @@
+package conoha
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// decodeUserData reverses EncodeUserData.
+func decodeUserData(t *testing.T, encoded string) (raw []byte, gzipped bool) {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	assertNoError(t, err)
+	if !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		return data, false
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	assertNoError(t, err)
+	raw, err = io.ReadAll(zr)
+	assertNoError(t, err)
+	return raw, true
+}
+
+func TestCloudConfigString(t *testing.T) {
+	cc := &CloudConfig{
+		Users: []CloudUser{{
+			Name:              "deploy",
+			Groups:            []string{"sudo", "docker"},
+			Shell:             "/bin/bash",
+			Sudo:              "ALL=(ALL) NOPASSWD:ALL",
+			SSHAuthorizedKeys: []string{"ssh-ed25519 AAAA deploy@example"},
+		}},
+		SSHAuthorizedKeys: []string{"ssh-ed25519 BBBB root@example"},
+		PackageUpdate:     true,
+		Packages:          []string{"nginx", "yes"},
+		WriteFiles: []WriteFile{
+			{Path: "/etc/motd", Content: "Hello: \"world\"\n", Permissions: "0644"},
+			{Path: "/etc/hosts", Content: "192.0.2.1 db\n", Append: true},
+		},
+		RunCmd: []string{"systemctl enable --now nginx", "echo done"},
+	}
+	want := `#cloud-config
+users:
+  - default
+  - name: deploy
+    groups: "sudo, docker"
+    shell: /bin/bash
+    sudo: "ALL=(ALL) NOPASSWD:ALL"
+    ssh_authorized_keys:
+      - "ssh-ed25519 AAAA deploy@example"
+ssh_authorized_keys:
+  - "ssh-ed25519 BBBB root@example"
+package_update: true
+packages:
+  - nginx
+  - "yes"
+write_files:
+  - path: /etc/motd
+    content: "Hello: \"world\"\n"
+    permissions: "0644"
+  - path: /etc/hosts
+    content: "192.0.2.1 db\n"
+    append: true
+runcmd:
+  - "systemctl enable --now nginx"
+  - "echo done"
+`
+	if got := cc.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUserData_SinglePart(t *testing.T) {
+	encoded, err := (&UserData{Scripts: []string{"apt-get update"}}).Encode()
+	assertNoError(t, err)
+	raw, gzipped := decodeUserData(t, encoded)
+	if gzipped || string(raw) != "#!/bin/sh\napt-get update" {
+		t.Errorf("user data = %q (gzipped %v)", raw, gzipped)
+	}
+
+	encoded, err = (&UserData{}).Encode()
+	if encoded != "" || err != nil {
+		t.Errorf("empty user data = %q, %v", encoded, err)
+	}
+}
+
+func TestUserData_Multipart(t *testing.T) {
+	u := &UserData{
+		Config:  &CloudConfig{Packages: []string{"nginx"}},
+		Scripts: []string{"#!/bin/bash\necho one\n", "echo two\n"},
+	}
+	encoded, err := u.Encode()
+	assertNoError(t, err)
+	again, _ := u.Encode()
+	if again != encoded {
+		t.Error("encoding the same user data twice gave different results")
+	}
+
+	raw, _ := decodeUserData(t, encoded)
+	header, body, _ := strings.Cut(string(raw), "\r\n\r\n")
+	mediaType, params, err := mime.ParseMediaType(strings.TrimPrefix(strings.Split(header, "\r\n")[0], "Content-Type: "))
+	assertNoError(t, err)
+	if mediaType != "multipart/mixed" {
+		t.Fatalf("media type = %s", mediaType)
+	}
+	mr := multipart.NewReader(strings.NewReader(body), params["boundary"])
+	want := []struct{ contentType, body string }{
+		{"text/cloud-config", "#cloud-config\npackages:\n  - nginx\n"},
+		{"text/x-shellscript", "#!/bin/bash\necho one\n"},
+		{"text/x-shellscript", "#!/bin/sh\necho two\n"},
+	}
+	for i, w := range want {
+		p, err := mr.NextPart()
+		assertNoError(t, err)
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		data, _ := io.ReadAll(p)
+		if ct != w.contentType || string(data) != w.body {
+			t.Errorf("part %d = %s %q, want %s %q", i, ct, data, w.contentType, w.body)
+		}
+	}
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("expected 3 parts, got more (%v)", err)
+	}
+}
+
+func TestEncodeUserData_Gzip(t *testing.T) {
+	script := "#!/bin/sh\n" + strings.Repeat("echo 'hello, world' >> /tmp/log\n", 500)
+	encoded, err := EncodeUserData([]byte(script))
+	assertNoError(t, err)
+	raw, gzipped := decodeUserData(t, encoded)
+	if !gzipped || string(raw) != script {
+		t.Errorf("gzipped %v, round trip ok %v", gzipped, string(raw) == script)
+	}
+	if len(encoded) >= len(script) {
+		t.Errorf("encoded size %d is not smaller than %d", len(encoded), len(script))
+	}
+}
+
+func TestEncodeUserData_TooLarge(t *testing.T) {
+	// Random bytes do not compress; base64 makes 50000 bytes 66668.
+	data := make([]byte, 50000)
+	rand.Read(data)
+	_, err := EncodeUserData(data)
+	if !errors.Is(err, ErrUserDataTooLarge) {
+		t.Errorf("expected ErrUserDataTooLarge, got %v", err)
+	}
+}
+
+func TestCreateServer_UserDataTooLarge(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	defer server.Close()
+
+	userData := strings.Repeat("A", MaxUserDataSize+1)
+	_, err := client.CreateServer(context.Background(), CreateServerRequest{UserData: userData})
+	if !errors.Is(err, ErrUserDataTooLarge) {
+		t.Errorf("CreateServer: expected ErrUserDataTooLarge, got %v", err)
+	}
+	err = client.RebuildServer(context.Background(), "srv-1", RebuildServerRequest{UserData: userData})
+	if !errors.Is(err, ErrUserDataTooLarge) {
+		t.Errorf("RebuildServer: expected ErrUserDataTooLarge, got %v", err)
+	}
+}
+
+func TestRebuildServer_UserData(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"user_data":"I2Nsb3VkLWNvbmZpZwo="`) {
+			t.Errorf("body = %s", body)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+	defer server.Close()
+
+	err := client.RebuildServer(context.Background(), "srv-1", RebuildServerRequest{
+		ImageRef:  "img-1",
+		AdminPass: "pass",
+		UserData:  base64.StdEncoding.EncodeToString([]byte("#cloud-config\n")),
+	})
+	assertNoError(t, err)
+}
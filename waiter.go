@@ -0,0 +1,313 @@
+package conoha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Defaults used by the WaitFor* helpers when WaitOptions leaves a field
+// unset.
+const (
+	DefaultWaitInterval    = 5 * time.Second
+	DefaultWaitMaxInterval = time.Minute
+)
+
+// ErrWaitTimeout is returned (wrapped) by the WaitFor* helpers when the
+// resource did not reach the wanted state within WaitOptions.Timeout.
+var ErrWaitTimeout = errors.New("conoha: timed out waiting for resource")
+
+// WaitOptions controls how the WaitFor* helpers poll a resource.
+type WaitOptions struct {
+	// Interval is the delay between two polls. Default: 5s.
+	Interval time.Duration
+	// Backoff multiplies Interval after every poll. Values <= 1 poll at a
+	// fixed interval.
+	Backoff float64
+	// MaxInterval caps the delay when Backoff grows it. Default: 1m.
+	MaxInterval time.Duration
+	// Timeout bounds the whole wait. Zero waits until ctx is done.
+	Timeout time.Duration
+	// Progress, if set, is called with the status seen on every poll.
+	Progress func(status string)
+}
+
+// StatusError is returned when a resource enters a failed state (such as
+// ERROR) while waiting for another state.
+type StatusError struct {
+	Resource string // "server", "volume", "backup", "image" or "load balancer"
+	ID       string
+	Status   string // the failed status that was reached
+	Target   string // the status that was waited for
+	Reason   string // failure reason reported by the service, if any
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("conoha: %s %s entered status %s while waiting for %s", e.Resource, e.ID, e.Status, e.Target)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// waitTarget describes what a wait is polling for.
+type waitTarget struct {
+	resource string
+	id       string
+	target   string
+	// deleted makes a 404 from the poll count as success.
+	deleted bool
+	// failed reports whether status is a terminal failure.
+	failed func(status string) bool
+}
+
+// waitFor polls get until it reports the target status (or, for deletion
+// waits, a 404), the resource fails, or the wait times out. get returns the
+// resource, its current status, and a failure reason if the service
+// reports one.
+func waitFor[T any](ctx context.Context, opts *WaitOptions, t waitTarget, get func(ctx context.Context) (*T, string, string, error)) (*T, error) {
+	var o WaitOptions
+	if opts != nil {
+		o = *opts
+	}
+	interval := o.Interval
+	if interval <= 0 {
+		interval = DefaultWaitInterval
+	}
+	maxInterval := o.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultWaitMaxInterval
+	}
+
+	waitCtx := ctx
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+	// timedOut distinguishes our own Timeout from the caller's ctx.
+	timedOut := func(last string) error {
+		if waitCtx.Err() == nil || ctx.Err() != nil {
+			return nil
+		}
+		if last == "" {
+			return fmt.Errorf("%w: %s %s is not %s after %s", ErrWaitTimeout, t.resource, t.id, t.target, o.Timeout)
+		}
+		return fmt.Errorf("%w: %s %s is %s, not %s after %s", ErrWaitTimeout, t.resource, t.id, last, t.target, o.Timeout)
+	}
+
+	var last string
+	for {
+		res, status, reason, err := get(waitCtx)
+		if err != nil {
+			if t.deleted && IsNotFound(err) {
+				return nil, nil
+			}
+			if terr := timedOut(last); terr != nil {
+				return nil, terr
+			}
+			return nil, err
+		}
+		last = status
+		if o.Progress != nil {
+			o.Progress(status)
+		}
+		if status == t.target {
+			return res, nil
+		}
+		if t.failed(status) {
+			return res, &StatusError{Resource: t.resource, ID: t.id, Status: status, Target: t.target, Reason: reason}
+		}
+
+		if err := sleepContext(waitCtx, interval); err != nil {
+			if terr := timedOut(last); terr != nil {
+				return nil, terr
+			}
+			return nil, err
+		}
+		if o.Backoff > 1 {
+			interval = time.Duration(float64(interval) * o.Backoff)
+			if interval > maxInterval {
+				interval = maxInterval
+			}
+		}
+	}
+}
+
+// isErrorStatus reports whether status is an OpenStack error state such as
+// ERROR, error or error_deleting.
+func isErrorStatus(status string) bool {
+	s := strings.ToLower(status)
+	return s == "error" || strings.HasPrefix(s, "error_")
+}
+
+// ------------------------------------------------------------
+// Compute
+// ------------------------------------------------------------
+
+// WaitForServerStatus polls the server until its status is status (e.g.
+// "ACTIVE" or "SHUTOFF") and returns it. It fails fast with a *StatusError
+// if the server enters ERROR.
+func (c *Client) WaitForServerStatus(ctx context.Context, serverID, status string, opts *WaitOptions) (*ServerDetail, error) {
+	t := waitTarget{resource: "server", id: serverID, target: status, failed: func(s string) bool {
+		return s == "ERROR" && status != "ERROR"
+	}}
+	return waitFor(ctx, opts, t, func(ctx context.Context) (*ServerDetail, string, string, error) {
+		s, err := c.GetServer(ctx, serverID)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return s, s.Status, "", nil
+	})
+}
+
+// WaitForServerDeleted polls the server until it is gone. Only the 404
+// ends the wait: Nova keeps reporting ERROR while it deletes a failed
+// server.
+func (c *Client) WaitForServerDeleted(ctx context.Context, serverID string, opts *WaitOptions) error {
+	t := waitTarget{resource: "server", id: serverID, target: "DELETED", deleted: true, failed: func(string) bool {
+		return false
+	}}
+	_, err := waitFor(ctx, opts, t, func(ctx context.Context) (*ServerDetail, string, string, error) {
+		s, err := c.GetServer(ctx, serverID)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return s, s.Status, "", nil
+	})
+	return err
+}
+
+// ------------------------------------------------------------
+// Block Storage
+// ------------------------------------------------------------
+
+// WaitForVolumeStatus polls the volume until its status is status (e.g.
+// "available" or "in-use") and returns it. It fails fast with a
+// *StatusError if the volume enters an error state.
+func (c *Client) WaitForVolumeStatus(ctx context.Context, volumeID, status string, opts *WaitOptions) (*Volume, error) {
+	t := waitTarget{resource: "volume", id: volumeID, target: status, failed: func(s string) bool {
+		return isErrorStatus(s) && s != status
+	}}
+	return waitFor(ctx, opts, t, func(ctx context.Context) (*Volume, string, string, error) {
+		v, err := c.GetVolume(ctx, volumeID)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return v, v.Status, "", nil
+	})
+}
+
+// WaitForVolumeDeleted polls the volume until it is gone.
+func (c *Client) WaitForVolumeDeleted(ctx context.Context, volumeID string, opts *WaitOptions) error {
+	t := waitTarget{resource: "volume", id: volumeID, target: "deleted", deleted: true, failed: isErrorStatus}
+	_, err := waitFor(ctx, opts, t, func(ctx context.Context) (*Volume, string, string, error) {
+		v, err := c.GetVolume(ctx, volumeID)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return v, v.Status, "", nil
+	})
+	return err
+}
+
+// WaitForBackup polls the backup until it is "available" and returns it.
+// It fails fast with a *StatusError, carrying the backup's fail reason, if
+// the backup enters an error state.
+func (c *Client) WaitForBackup(ctx context.Context, backupID string, opts *WaitOptions) (*Backup, error) {
+	t := waitTarget{resource: "backup", id: backupID, target: "available", failed: isErrorStatus}
+	return waitFor(ctx, opts, t, c.pollBackup(backupID))
+}
+
+// WaitForBackupDeleted polls the backup until it is gone.
+func (c *Client) WaitForBackupDeleted(ctx context.Context, backupID string, opts *WaitOptions) error {
+	t := waitTarget{resource: "backup", id: backupID, target: "deleted", deleted: true, failed: isErrorStatus}
+	_, err := waitFor(ctx, opts, t, c.pollBackup(backupID))
+	return err
+}
+
+func (c *Client) pollBackup(backupID string) func(ctx context.Context) (*Backup, string, string, error) {
+	return func(ctx context.Context) (*Backup, string, string, error) {
+		b, err := c.GetBackup(ctx, backupID)
+		if err != nil {
+			return nil, "", "", err
+		}
+		var reason string
+		if b.FailReason != nil {
+			reason = *b.FailReason
+		}
+		return b, b.Status, reason, nil
+	}
+}
+
+// ------------------------------------------------------------
+// Image
+// ------------------------------------------------------------
+
+// isImageFailed reports whether an image status is terminal: "killed" when
+// the upload or import failed, or "deleted".
+func isImageFailed(status string) bool {
+	return status == "killed" || status == "deleted"
+}
+
+// WaitForImageActive polls the image until it is "active" and returns it.
+// It fails fast with a *StatusError if the image is killed or deleted.
+func (c *Client) WaitForImageActive(ctx context.Context, imageID string, opts *WaitOptions) (*Image, error) {
+	t := waitTarget{resource: "image", id: imageID, target: "active", failed: isImageFailed}
+	return waitFor(ctx, opts, t, c.pollImage(imageID))
+}
+
+// WaitForImageDeleted polls the image until it is gone.
+func (c *Client) WaitForImageDeleted(ctx context.Context, imageID string, opts *WaitOptions) error {
+	t := waitTarget{resource: "image", id: imageID, target: "deleted", deleted: true, failed: func(s string) bool {
+		return s == "killed"
+	}}
+	_, err := waitFor(ctx, opts, t, c.pollImage(imageID))
+	return err
+}
+
+func (c *Client) pollImage(imageID string) func(ctx context.Context) (*Image, string, string, error) {
+	return func(ctx context.Context) (*Image, string, string, error) {
+		img, err := c.GetImage(ctx, imageID)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return img, img.Status, "", nil
+	}
+}
+
+// ------------------------------------------------------------
+// Load Balancer
+// ------------------------------------------------------------
+
+// WaitForLoadBalancerActive polls the load balancer until its
+// provisioning status is ACTIVE and returns it. It fails fast with a
+// *StatusError if provisioning ends in ERROR.
+func (c *Client) WaitForLoadBalancerActive(ctx context.Context, lbID string, opts *WaitOptions) (*LoadBalancer, error) {
+	t := waitTarget{resource: "load balancer", id: lbID, target: "ACTIVE", failed: func(s string) bool {
+		return s == "ERROR" || s == "DELETED"
+	}}
+	return waitFor(ctx, opts, t, c.pollLoadBalancer(lbID))
+}
+
+// WaitForLoadBalancerDeleted polls the load balancer until it is gone or
+// its provisioning status is DELETED.
+func (c *Client) WaitForLoadBalancerDeleted(ctx context.Context, lbID string, opts *WaitOptions) error {
+	t := waitTarget{resource: "load balancer", id: lbID, target: "DELETED", deleted: true, failed: func(s string) bool {
+		return s == "ERROR"
+	}}
+	_, err := waitFor(ctx, opts, t, c.pollLoadBalancer(lbID))
+	return err
+}
+
+func (c *Client) pollLoadBalancer(lbID string) func(ctx context.Context) (*LoadBalancer, string, string, error) {
+	return func(ctx context.Context) (*LoadBalancer, string, string, error) {
+		lb, err := c.GetLoadBalancer(ctx, lbID)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return lb, lb.ProvisioningStatus, "", nil
+	}
+}
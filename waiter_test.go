@@ -0,0 +1,217 @@
+package conoha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// statusSequence serves one status per poll and repeats the last one.
+type statusSequence struct {
+	mu       sync.Mutex
+	statuses []string
+	polls    int
+}
+
+func (s *statusSequence) next() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.polls
+	if i >= len(s.statuses) {
+		i = len(s.statuses) - 1
+	}
+	s.polls++
+	return s.statuses[i]
+}
+
+var fastWait = &WaitOptions{Interval: time.Millisecond}
+
+func TestWaitForServerStatus(t *testing.T) {
+	seq := &statusSequence{statuses: []string{"BUILD", "BUILD", "ACTIVE"}}
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servers/s1" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		fmt.Fprintf(w, `{"server":{"id":"s1","status":%q}}`, seq.next())
+	})
+	defer server.Close()
+
+	var seen []string
+	opts := &WaitOptions{Interval: time.Millisecond, Progress: func(s string) { seen = append(seen, s) }}
+	s, err := client.WaitForServerStatus(context.Background(), "s1", "ACTIVE", opts)
+	assertNoError(t, err)
+
+	if s.Status != "ACTIVE" {
+		t.Errorf("Status = %q", s.Status)
+	}
+	if strings.Join(seen, ",") != "BUILD,BUILD,ACTIVE" {
+		t.Errorf("progress = %v", seen)
+	}
+}
+
+func TestWaitForServerStatus_FailsFastOnError(t *testing.T) {
+	seq := &statusSequence{statuses: []string{"BUILD", "ERROR", "ACTIVE"}}
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"server":{"id":"s1","status":%q}}`, seq.next())
+	})
+	defer server.Close()
+
+	_, err := client.WaitForServerStatus(context.Background(), "s1", "ACTIVE", fastWait)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("err = %v, want *StatusError", err)
+	}
+	if statusErr.Status != "ERROR" || statusErr.Target != "ACTIVE" || statusErr.ID != "s1" {
+		t.Errorf("StatusError = %+v", statusErr)
+	}
+	if seq.polls != 2 {
+		t.Errorf("polls = %d, want 2", seq.polls)
+	}
+}
+
+func TestWaitForServerStatus_Timeout(t *testing.T) {
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"server":{"id":"s1","status":"BUILD"}}`))
+	})
+	defer server.Close()
+
+	opts := &WaitOptions{Interval: 5 * time.Millisecond, Timeout: 30 * time.Millisecond}
+	_, err := client.WaitForServerStatus(context.Background(), "s1", "ACTIVE", opts)
+	if !errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("err = %v, want ErrWaitTimeout", err)
+	}
+	if !strings.Contains(err.Error(), "BUILD") {
+		t.Errorf("error should mention the last status: %v", err)
+	}
+
+	// The caller's own cancellation is reported as such.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.WaitForServerStatus(ctx, "s1", "ACTIVE", &WaitOptions{Interval: 5 * time.Millisecond, Timeout: time.Minute})
+	if errors.Is(err, ErrWaitTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestWaitForServerDeleted(t *testing.T) {
+	seq := &statusSequence{statuses: []string{"ACTIVE", "DELETING", "gone"}}
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		status := seq.next()
+		if status == "gone" {
+			w.WriteHeader(404)
+			w.Write([]byte(`{"itemNotFound":{"message":"not found","code":404}}`))
+			return
+		}
+		fmt.Fprintf(w, `{"server":{"id":"s1","status":%q}}`, status)
+	})
+	defer server.Close()
+
+	assertNoError(t, client.WaitForServerDeleted(context.Background(), "s1", fastWait))
+	if seq.polls != 3 {
+		t.Errorf("polls = %d, want 3", seq.polls)
+	}
+}
+
+func TestWaitForServerDeleted_FromError(t *testing.T) {
+	seq := &statusSequence{statuses: []string{"ERROR", "ERROR", "ERROR", "gone"}}
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		status := seq.next()
+		if status == "gone" {
+			w.WriteHeader(404)
+			w.Write([]byte(`{"itemNotFound":{"message":"not found","code":404}}`))
+			return
+		}
+		fmt.Fprintf(w, `{"server":{"id":"s1","status":%q}}`, status)
+	})
+	defer server.Close()
+
+	assertNoError(t, client.WaitForServerDeleted(context.Background(), "s1", fastWait))
+	if seq.polls != 4 {
+		t.Errorf("polls = %d, want 4", seq.polls)
+	}
+}
+
+func TestWaitForVolumeStatus_FailsFastOnError(t *testing.T) {
+	seq := &statusSequence{statuses: []string{"creating", "error"}}
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"volume":{"id":"v1","status":%q}}`, seq.next())
+	})
+	defer server.Close()
+
+	_, err := client.WaitForVolumeStatus(context.Background(), "v1", "available", fastWait)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Resource != "volume" || statusErr.Status != "error" {
+		t.Errorf("err = %v, want volume StatusError", err)
+	}
+}
+
+func TestWaitForBackup_ReportsFailReason(t *testing.T) {
+	seq := &statusSequence{statuses: []string{"creating", "error"}}
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"backup":{"id":"b1","status":%q,"fail_reason":"disk full"}}`, seq.next())
+	})
+	defer server.Close()
+
+	_, err := client.WaitForBackup(context.Background(), "b1", fastWait)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Reason != "disk full" {
+		t.Errorf("err = %v, want StatusError with fail reason", err)
+	}
+}
+
+func TestWaitForImageActive(t *testing.T) {
+	seq := &statusSequence{statuses: []string{"queued", "saving", "active"}}
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":"i1","status":%q}`, seq.next())
+	})
+	defer server.Close()
+
+	img, err := client.WaitForImageActive(context.Background(), "i1", fastWait)
+	assertNoError(t, err)
+	if img.Status != "active" {
+		t.Errorf("Status = %q", img.Status)
+	}
+}
+
+func TestWaitForLoadBalancerActive(t *testing.T) {
+	seq := &statusSequence{statuses: []string{"PENDING_CREATE", "ERROR"}}
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"loadbalancer":{"id":"lb1","provisioning_status":%q}}`, seq.next())
+	})
+	defer server.Close()
+
+	_, err := client.WaitForLoadBalancerActive(context.Background(), "lb1", fastWait)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != "ERROR" || statusErr.Target != "ACTIVE" {
+		t.Errorf("err = %v, want StatusError", err)
+	}
+}
+
+func TestWaitFor_Backoff(t *testing.T) {
+	var times []time.Time
+	server, client := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		status := "BUILD"
+		if len(times) == 4 {
+			status = "ACTIVE"
+		}
+		fmt.Fprintf(w, `{"server":{"id":"s1","status":%q}}`, status)
+	})
+	defer server.Close()
+
+	opts := &WaitOptions{Interval: 10 * time.Millisecond, Backoff: 2, MaxInterval: 25 * time.Millisecond}
+	_, err := client.WaitForServerStatus(context.Background(), "s1", "ACTIVE", opts)
+	assertNoError(t, err)
+
+	// Delays of 10ms, 20ms, then capped at 25ms.
+	for i, min := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond} {
+		if d := times[i+1].Sub(times[i]); d < min {
+			t.Errorf("delay %d = %v, want >= %v", i, d, min)
+		}
+	}
+}